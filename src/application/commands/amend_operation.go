@@ -0,0 +1,25 @@
+package commands
+
+var _ Command = (*AmendOperation)(nil)
+
+// AmendOperation replaces a previously registered operation, identified by its id,
+// with a corrected RegisterBuy or RegisterSell command.
+type AmendOperation struct {
+	id          string
+	replacement Command
+}
+
+func NewAmendOperation(id string, replacement Command) AmendOperation {
+	return AmendOperation{
+		id:          id,
+		replacement: replacement,
+	}
+}
+
+func (command AmendOperation) ID() string {
+	return command.id
+}
+
+func (command AmendOperation) Replacement() Command {
+	return command.replacement
+}
@@ -2,8 +2,33 @@ package commands
 
 var _ Command = (*CalculateCapitalGain)(nil)
 
-type CalculateCapitalGain struct{}
+type CalculateCapitalGain struct {
+	chronological bool
+	incremental   bool
+}
 
 func NewCalculateCapitalGain() CalculateCapitalGain {
 	return CalculateCapitalGain{}
 }
+
+// Chronologically returns the command applying the registered operations in the order
+// they were traded, rather than in the order they were registered.
+func (command CalculateCapitalGain) Chronologically() CalculateCapitalGain {
+	command.chronological = true
+	return command
+}
+
+func (command CalculateCapitalGain) IsChronological() bool {
+	return command.chronological
+}
+
+// Incrementally returns the command carrying the resulting positions over to the next
+// calculation, so operations can be calculated one at a time as they arrive.
+func (command CalculateCapitalGain) Incrementally() CalculateCapitalGain {
+	command.incremental = true
+	return command
+}
+
+func (command CalculateCapitalGain) IsIncremental() bool {
+	return command.incremental
+}
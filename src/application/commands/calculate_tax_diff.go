@@ -0,0 +1,9 @@
+package commands
+
+var _ Command = (*CalculateTaxDiff)(nil)
+
+type CalculateTaxDiff struct{}
+
+func NewCalculateTaxDiff() CalculateTaxDiff {
+	return CalculateTaxDiff{}
+}
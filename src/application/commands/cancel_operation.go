@@ -0,0 +1,17 @@
+package commands
+
+var _ Command = (*CancelOperation)(nil)
+
+type CancelOperation struct {
+	id string
+}
+
+func NewCancelOperation(id string) CancelOperation {
+	return CancelOperation{
+		id: id,
+	}
+}
+
+func (command CancelOperation) ID() string {
+	return command.id
+}
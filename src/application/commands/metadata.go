@@ -0,0 +1,78 @@
+package commands
+
+import "time"
+
+// Metadata carries the optional attributes that identify a registered operation
+// and place it in time, without affecting how its tax is computed.
+type Metadata struct {
+	id       string
+	tradedAt time.Time
+	timed    bool
+	sequence int
+	account  string
+	ticker   string
+}
+
+func NewMetadata() Metadata {
+	return Metadata{}
+}
+
+func (metadata Metadata) WithID(id string) Metadata {
+	metadata.id = id
+	return metadata
+}
+
+// WithTradedOn places the operation on a day, without a time of day.
+func (metadata Metadata) WithTradedOn(tradedOn time.Time) Metadata {
+	metadata.tradedAt = tradedOn
+	metadata.timed = false
+	return metadata
+}
+
+// WithTradedAt places the operation at an exact time of day.
+func (metadata Metadata) WithTradedAt(tradedAt time.Time) Metadata {
+	metadata.tradedAt = tradedAt
+	metadata.timed = true
+	return metadata
+}
+
+// WithSequence orders the operation among others traded at the same moment.
+func (metadata Metadata) WithSequence(sequence int) Metadata {
+	metadata.sequence = sequence
+	return metadata
+}
+
+// WithAccount assigns the operation to an account, whose portfolio is computed independently.
+func (metadata Metadata) WithAccount(account string) Metadata {
+	metadata.account = account
+	return metadata
+}
+
+func (metadata Metadata) ID() string {
+	return metadata.id
+}
+
+func (metadata Metadata) TradedAt() time.Time {
+	return metadata.tradedAt
+}
+
+func (metadata Metadata) IsTimed() bool {
+	return metadata.timed
+}
+
+func (metadata Metadata) Sequence() int {
+	return metadata.sequence
+}
+
+func (metadata Metadata) Account() string {
+	return metadata.account
+}
+
+func (metadata Metadata) WithTicker(ticker string) Metadata {
+	metadata.ticker = ticker
+	return metadata
+}
+
+func (metadata Metadata) Ticker() string {
+	return metadata.ticker
+}
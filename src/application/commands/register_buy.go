@@ -5,6 +5,8 @@ var _ Command = (*RegisterBuy)(nil)
 type RegisterBuy struct {
 	quantity int
 	unitCost float64
+	fees     float64
+	metadata Metadata
 }
 
 func NewRegisterBuy(quantity int, unitCost float64) RegisterBuy {
@@ -21,3 +23,22 @@ func (command RegisterBuy) Quantity() int {
 func (command RegisterBuy) UnitCost() float64 {
 	return command.unitCost
 }
+
+func (command RegisterBuy) Fees() float64 {
+	return command.fees
+}
+
+func (command RegisterBuy) Metadata() Metadata {
+	return command.metadata
+}
+
+func (command RegisterBuy) WithMetadata(metadata Metadata) RegisterBuy {
+	command.metadata = metadata
+	return command
+}
+
+// WithFees returns the command charging the given brokerage fees and taxes on the operation.
+func (command RegisterBuy) WithFees(fees float64) RegisterBuy {
+	command.fees = fees
+	return command
+}
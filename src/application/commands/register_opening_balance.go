@@ -0,0 +1,40 @@
+package commands
+
+var _ Command = (*RegisterOpeningBalance)(nil)
+
+type RegisterOpeningBalance struct {
+	quantity        int
+	averageUnitCost float64
+	accumulatedLoss float64
+	account         string
+}
+
+func NewRegisterOpeningBalance(quantity int, averageUnitCost float64, accumulatedLoss float64) RegisterOpeningBalance {
+	return RegisterOpeningBalance{
+		quantity:        quantity,
+		averageUnitCost: averageUnitCost,
+		accumulatedLoss: accumulatedLoss,
+	}
+}
+
+func (command RegisterOpeningBalance) Quantity() int {
+	return command.quantity
+}
+
+func (command RegisterOpeningBalance) AverageUnitCost() float64 {
+	return command.averageUnitCost
+}
+
+func (command RegisterOpeningBalance) AccumulatedLoss() float64 {
+	return command.accumulatedLoss
+}
+
+func (command RegisterOpeningBalance) Account() string {
+	return command.account
+}
+
+// ForAccount returns the command seeding the portfolio of the given account.
+func (command RegisterOpeningBalance) ForAccount(account string) RegisterOpeningBalance {
+	command.account = account
+	return command
+}
@@ -5,6 +5,8 @@ var _ Command = (*RegisterSell)(nil)
 type RegisterSell struct {
 	quantity int
 	unitCost float64
+	fees     float64
+	metadata Metadata
 }
 
 func NewRegisterSell(quantity int, unitCost float64) RegisterSell {
@@ -21,3 +23,22 @@ func (command RegisterSell) Quantity() int {
 func (command RegisterSell) UnitCost() float64 {
 	return command.unitCost
 }
+
+func (command RegisterSell) Fees() float64 {
+	return command.fees
+}
+
+func (command RegisterSell) Metadata() Metadata {
+	return command.metadata
+}
+
+func (command RegisterSell) WithMetadata(metadata Metadata) RegisterSell {
+	command.metadata = metadata
+	return command
+}
+
+// WithFees returns the command charging the given brokerage fees and taxes on the operation.
+func (command RegisterSell) WithFees(fees float64) RegisterSell {
+	command.fees = fees
+	return command
+}
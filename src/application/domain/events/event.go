@@ -8,4 +8,25 @@ type Event interface {
 	//
 	// [return] float64   tax amount associated with this event.
 	Amount() float64
+
+	// OperationID returns the identifier of the operation that produced this event.
+	// An empty identifier represents an operation registered without one.
+	//
+	// [return] string   identifier of the originating operation.
+	OperationID() string
+
+	// Trade returns the operation that produced this event.
+	//
+	// [return] Trade   side, quantity, unit cost and date of the operation.
+	Trade() Trade
+
+	// Realization returns the profit or loss realized by the operation.
+	//
+	// [return] Realization   realized gain and deducted accumulated loss.
+	Realization() Realization
+
+	// Position returns the investor position right after the operation.
+	//
+	// [return] Snapshot   quantity, average unit cost and accumulated loss.
+	Position() Snapshot
 }
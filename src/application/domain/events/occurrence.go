@@ -0,0 +1,24 @@
+package events
+
+// occurrence holds what every event knows about the operation that produced it.
+type occurrence struct {
+	trade       Trade
+	realization Realization
+	position    Snapshot
+}
+
+func (occurrence occurrence) OperationID() string {
+	return occurrence.trade.ID()
+}
+
+func (occurrence occurrence) Trade() Trade {
+	return occurrence.trade
+}
+
+func (occurrence occurrence) Realization() Realization {
+	return occurrence.realization
+}
+
+func (occurrence occurrence) Position() Snapshot {
+	return occurrence.position
+}
@@ -0,0 +1,24 @@
+package events
+
+// Realization describes the profit or loss realized by a sell operation and the
+// accumulated loss deducted from that profit. Buy operations realize nothing.
+type Realization struct {
+	gain         float64
+	deductedLoss float64
+}
+
+func NewRealization(gain float64, deductedLoss float64) Realization {
+	return Realization{
+		gain:         gain,
+		deductedLoss: deductedLoss,
+	}
+}
+
+// Gain returns the realized profit, or a negative value for a realized loss.
+func (realization Realization) Gain() float64 {
+	return realization.gain
+}
+
+func (realization Realization) DeductedLoss() float64 {
+	return realization.deductedLoss
+}
@@ -0,0 +1,28 @@
+package events
+
+// Snapshot describes the investor position right after the operation that produced an event.
+type Snapshot struct {
+	quantity        int
+	averageUnitCost float64
+	accumulatedLoss float64
+}
+
+func NewSnapshot(quantity int, averageUnitCost float64, accumulatedLoss float64) Snapshot {
+	return Snapshot{
+		quantity:        quantity,
+		averageUnitCost: averageUnitCost,
+		accumulatedLoss: accumulatedLoss,
+	}
+}
+
+func (snapshot Snapshot) Quantity() int {
+	return snapshot.quantity
+}
+
+func (snapshot Snapshot) AverageUnitCost() float64 {
+	return snapshot.averageUnitCost
+}
+
+func (snapshot Snapshot) AccumulatedLoss() float64 {
+	return snapshot.accumulatedLoss
+}
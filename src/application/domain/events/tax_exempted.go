@@ -1,12 +1,14 @@
 package events
 
 type TaxExempted struct {
+	occurrence
 	amount float64
 }
 
-func NewTaxExempted() TaxExempted {
+func NewTaxExempted(trade Trade, realization Realization, position Snapshot) TaxExempted {
 	return TaxExempted{
-		amount: 0.00,
+		occurrence: occurrence{trade: trade, realization: realization, position: position},
+		amount:     0.00,
 	}
 }
 
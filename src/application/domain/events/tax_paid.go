@@ -1,12 +1,14 @@
 package events
 
 type TaxPaid struct {
+	occurrence
 	amount float64
 }
 
-func NewTaxPaid(amount float64) TaxPaid {
+func NewTaxPaid(amount float64, trade Trade, realization Realization, position Snapshot) TaxPaid {
 	return TaxPaid{
-		amount: amount,
+		occurrence: occurrence{trade: trade, realization: realization, position: position},
+		amount:     amount,
 	}
 }
 
@@ -0,0 +1,77 @@
+package events
+
+import "time"
+
+const (
+	BuySide  = "buy"
+	SellSide = "sell"
+)
+
+// Trade describes the operation that produced an event.
+type Trade struct {
+	id       string
+	side     string
+	quantity int
+	unitCost float64
+	fees     float64
+	ticker   string
+	tradedAt time.Time
+	timed    bool
+}
+
+func NewTrade(id string, side string, quantity int, unitCost float64, tradedAt time.Time) Trade {
+	return Trade{
+		id:       id,
+		side:     side,
+		quantity: quantity,
+		unitCost: unitCost,
+		tradedAt: tradedAt,
+	}
+}
+
+// WithCosts returns the trade along with the asset it traded and the fees it was charged.
+func (trade Trade) WithCosts(ticker string, fees float64) Trade {
+	trade.ticker = ticker
+	trade.fees = fees
+	return trade
+}
+
+// Timed returns the trade placed at an exact time of day, rather than only on a day.
+func (trade Trade) Timed() Trade {
+	trade.timed = true
+	return trade
+}
+
+func (trade Trade) ID() string {
+	return trade.id
+}
+
+func (trade Trade) Side() string {
+	return trade.side
+}
+
+func (trade Trade) Quantity() int {
+	return trade.quantity
+}
+
+func (trade Trade) UnitCost() float64 {
+	return trade.unitCost
+}
+
+func (trade Trade) Fees() float64 {
+	return trade.fees
+}
+
+func (trade Trade) Ticker() string {
+	return trade.ticker
+}
+
+// TradedAt returns when the operation was traded, or the zero time when it is undated.
+func (trade Trade) TradedAt() time.Time {
+	return trade.tradedAt
+}
+
+// IsTimed reports whether the trade was placed at an exact time of day, even midnight.
+func (trade Trade) IsTimed() bool {
+	return trade.timed
+}
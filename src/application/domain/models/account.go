@@ -0,0 +1,46 @@
+package models
+
+// Account groups the operations of a single investor account, whose portfolio is
+// computed independently from the portfolios of other accounts.
+type Account struct {
+	name       string
+	operations []Operation
+}
+
+// NewAccount returns an account without operations.
+func NewAccount(name string) Account {
+	return Account{name: name, operations: make([]Operation, 0)}
+}
+
+// SplitByAccount splits the operations into one account per distinct account name, in the
+// order each account first appears, keeping the relative order of the operations of each account.
+func SplitByAccount(operations []Operation) []Account {
+	accounts := make([]Account, 0)
+	indexes := make(map[string]int)
+
+	for _, operation := range operations {
+		name := operation.Metadata().Account()
+		index, exists := indexes[name]
+
+		if !exists {
+			index = len(accounts)
+			indexes[name] = index
+			accounts = append(accounts, NewAccount(name))
+		}
+
+		accounts[index].operations = append(accounts[index].operations, operation)
+	}
+
+	return accounts
+}
+
+func (account Account) Name() string {
+	return account.name
+}
+
+func (account Account) Operations() []Operation {
+	operations := make([]Operation, len(account.operations))
+	copy(operations, account.operations)
+
+	return operations
+}
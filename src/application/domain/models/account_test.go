@@ -0,0 +1,51 @@
+package models_test
+
+import (
+	"testing"
+
+	"capital-gains/src/application/domain/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitByAccountGivenInterleavedAccountsWhenSplitThenEachAccountKeepsItsOperationsInOrder(t *testing.T) {
+	t.Parallel()
+
+	// Given operations of two accounts interleaved in a single input
+	operations := []models.Operation{
+		buyWith(models.NewMetadata().WithID("a-1").WithAccount("alice")),
+		buyWith(models.NewMetadata().WithID("b-1").WithAccount("bob")),
+		sellWith(models.NewMetadata().WithID("a-2").WithAccount("alice")),
+		sellWith(models.NewMetadata().WithID("b-2").WithAccount("bob")),
+	}
+
+	// When I split the operations by account
+	actual := models.SplitByAccount(operations)
+
+	// Then I expect one account per distinct name, in order of first appearance
+	assert.Len(t, actual, 2)
+	assert.Equal(t, "alice", actual[0].Name())
+	assert.Equal(t, "bob", actual[1].Name())
+
+	// And I expect each account to keep the relative order of its operations
+	assert.Equal(t, []models.Operation{operations[0], operations[2]}, actual[0].Operations())
+	assert.Equal(t, []models.Operation{operations[1], operations[3]}, actual[1].Operations())
+}
+
+func TestSplitByAccountGivenOperationsWithoutAccountWhenSplitThenASingleUnnamedAccountIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given operations that carry no account
+	operations := []models.Operation{
+		buyWith(models.NewMetadata()),
+		sellWith(models.NewMetadata()),
+	}
+
+	// When I split the operations by account
+	actual := models.SplitByAccount(operations)
+
+	// Then I expect every operation to belong to a single unnamed account
+	assert.Len(t, actual, 1)
+	assert.Equal(t, "", actual[0].Name())
+	assert.Equal(t, operations, actual[0].Operations())
+}
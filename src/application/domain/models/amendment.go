@@ -0,0 +1,17 @@
+package models
+
+type Amendment struct {
+	id          string
+	replacement Operation
+}
+
+func NewAmendment(id string, replacement Operation) Amendment {
+	return Amendment{
+		id:          id,
+		replacement: replacement,
+	}
+}
+
+func (amendment Amendment) ApplyTo(portfolio *Portfolio) error {
+	return portfolio.Amend(amendment.id, amendment.replacement)
+}
@@ -3,12 +3,15 @@ package models
 type Buy struct {
 	quantity Quantity
 	unitCost MonetaryValue
+	fees     MonetaryValue
+	metadata Metadata
 }
 
 func NewBuy(quantity Quantity, unitCost MonetaryValue) Buy {
 	return Buy{
 		quantity: quantity,
 		unitCost: unitCost,
+		fees:     NewZeroMonetaryValue(),
 	}
 }
 
@@ -17,3 +20,19 @@ func (buy Buy) ApplyTo(position *Position) Tax {
 
 	return NewTax(NewZeroMonetaryValue())
 }
+
+func (buy Buy) Metadata() Metadata {
+	return buy.metadata
+}
+
+func (buy Buy) WithMetadata(metadata Metadata) Buy {
+	buy.metadata = metadata
+	return buy
+}
+
+// WithFees returns the operation charging the given fees, reported along with its trade without
+// changing the cost of the bought shares.
+func (buy Buy) WithFees(fees MonetaryValue) Buy {
+	buy.fees = fees
+	return buy
+}
@@ -0,0 +1,15 @@
+package models
+
+type Cancellation struct {
+	id string
+}
+
+func NewCancellation(id string) Cancellation {
+	return Cancellation{
+		id: id,
+	}
+}
+
+func (cancellation Cancellation) ApplyTo(portfolio *Portfolio) error {
+	return portfolio.Cancel(cancellation.id)
+}
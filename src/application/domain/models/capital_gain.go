@@ -3,31 +3,71 @@ package models
 import "capital-gains/src/application/domain/events"
 
 type CapitalGain struct {
-	events   []events.Event
-	position Position
+	account      string
+	events       []events.Event
+	holdings     Holdings
+	appliedOrder []int
 }
 
 func NewCapitalGain() CapitalGain {
+	return NewCapitalGainFrom(NewPosition())
+}
+
+func NewCapitalGainFrom(position Position) CapitalGain {
+	return NewCapitalGainFor("", position)
+}
+
+// NewCapitalGainFor starts the capital gain calculation of an account from the given position.
+func NewCapitalGainFor(account string, position Position) CapitalGain {
+	return NewCapitalGainHolding(account, NewHoldings(position))
+}
+
+// NewCapitalGainHolding starts the capital gain calculation of an account from the given holdings.
+func NewCapitalGainHolding(account string, holdings Holdings) CapitalGain {
 	return CapitalGain{
-		events:   make([]events.Event, 0),
-		position: NewPosition(),
+		account:      account,
+		events:       make([]events.Event, 0),
+		holdings:     holdings,
+		appliedOrder: make([]int, 0),
 	}
 }
 
 func (capitalGain *CapitalGain) ApplyOperations(operations []Operation) {
 	for _, operation := range operations {
-		tax := operation.ApplyTo(&capitalGain.position)
+		capitalGain.appliedOrder = append(capitalGain.appliedOrder, len(capitalGain.events))
+		capitalGain.events = append(capitalGain.events, capitalGain.apply(operation))
+	}
+}
 
-		if tax.IsExempted() {
-			capitalGain.events = append(capitalGain.events, events.NewTaxExempted())
-			continue
-		}
+// ApplyOperationsChronologically applies the operations in the order they were traded,
+// while keeping their events in the order the operations were given.
+func (capitalGain *CapitalGain) ApplyOperationsChronologically(operations []Operation) error {
+	order, err := NewChronologicalOrder(operations)
 
-		capitalGain.events = append(
-			capitalGain.events,
-			events.NewTaxPaid(tax.Value().ToFloat64()),
-		)
+	if err != nil {
+		return err
 	}
+
+	offset := len(capitalGain.events)
+	appliedEvents := make([]events.Event, len(operations))
+
+	for _, index := range order {
+		capitalGain.appliedOrder = append(capitalGain.appliedOrder, offset+index)
+		appliedEvents[index] = capitalGain.apply(operations[index])
+	}
+
+	capitalGain.events = append(capitalGain.events, appliedEvents...)
+
+	return nil
+}
+
+func (capitalGain *CapitalGain) Account() string {
+	return capitalGain.account
+}
+
+// Holdings returns the positions of the account after every applied operation.
+func (capitalGain *CapitalGain) Holdings() Holdings {
+	return capitalGain.holdings
 }
 
 func (capitalGain *CapitalGain) Events() []events.Event {
@@ -36,3 +76,62 @@ func (capitalGain *CapitalGain) Events() []events.Event {
 
 	return taxEvents
 }
+
+// AppliedOrder returns the indexes of the events in the order their operations were applied.
+func (capitalGain *CapitalGain) AppliedOrder() []int {
+	appliedOrder := make([]int, len(capitalGain.appliedOrder))
+	copy(appliedOrder, capitalGain.appliedOrder)
+
+	return appliedOrder
+}
+
+func (capitalGain *CapitalGain) apply(operation Operation) events.Event {
+	ticker := operation.Metadata().Ticker()
+	position := capitalGain.holdings.PositionOf(ticker)
+	tax := operation.ApplyTo(&position)
+	capitalGain.holdings.Hold(ticker, position)
+
+	trade := tradeOf(operation)
+	realization := events.NewRealization(tax.Gain().ToFloat64(), tax.DeductedLoss().ToFloat64())
+	snapshot := events.NewSnapshot(
+		position.Quantity().ToInt(),
+		position.AverageUnitCost().ToFloat64(),
+		position.AccumulatedLoss().ToFloat64(),
+	)
+
+	if tax.IsExempted() {
+		return events.NewTaxExempted(trade, realization, snapshot)
+	}
+
+	return events.NewTaxPaid(tax.Value().ToFloat64(), trade, realization, snapshot)
+}
+
+func tradeOf(operation Operation) events.Trade {
+	metadata := operation.Metadata()
+	trade := events.NewTrade(metadata.ID(), "", 0, 0.00, metadata.TradedAt())
+
+	switch typedOperation := operation.(type) {
+	case Buy:
+		trade = events.NewTrade(
+			metadata.ID(),
+			events.BuySide,
+			typedOperation.quantity.ToInt(),
+			typedOperation.unitCost.ToFloat64(),
+			metadata.TradedAt(),
+		).WithCosts(metadata.Ticker(), typedOperation.fees.ToFloat64())
+	case Sell:
+		trade = events.NewTrade(
+			metadata.ID(),
+			events.SellSide,
+			typedOperation.quantity.ToInt(),
+			typedOperation.unitCost.ToFloat64(),
+			metadata.TradedAt(),
+		).WithCosts(metadata.Ticker(), typedOperation.fees.ToFloat64())
+	}
+
+	if metadata.IsTimed() {
+		return trade.Timed()
+	}
+
+	return trade
+}
@@ -1042,3 +1042,97 @@ func TestCapitalGainApplyOperationsGivenWeightedAverageCostWithRepeatingDecimalW
 
 	assert.Equal(t, expectedTaxAmounts, taxAmounts)
 }
+
+func TestCapitalGainApplyOperationsGivenOpeningPositionWithAccumulatedLossWhenApplyOperationsThenSellUsesOpeningBalance(t *testing.T) {
+	t.Parallel()
+
+	// Given an opening position of 10000 shares at an average unit cost of 10.00
+	// And an accumulated loss of 5000.00 carried over from a previous history
+	openingPosition := models.NewOpeningPosition(
+		models.NewQuantity(10000),
+		models.NewMonetaryValue(10.00),
+		models.NewMonetaryValue(5000.00),
+	)
+
+	// And I start a capital gain calculation from the opening position
+	capitalGain := models.NewCapitalGainFrom(openingPosition)
+
+	// And I create a sell operation of 5000 shares at 20.00
+	sellOperation := models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(20.00))
+
+	// When I apply the sell operation to the capital gain
+	capitalGain.ApplyOperations([]models.Operation{sellOperation})
+
+	// Then the tax should be computed over the profit after deducting the opening accumulated loss
+	taxAmounts := test.TaxAmountsFromEvents(capitalGain.Events())
+	expectedTaxAmounts := []float64{
+		9000.00, // sell ((20.00 - 10.00) * 5000 - 5000.00) * 20%
+	}
+
+	assert.Equal(t, expectedTaxAmounts, taxAmounts)
+}
+
+func TestCapitalGainApplyOperationsGivenOperationsWithFeesWhenApplyOperationsThenFeesAreReportedWithoutChangingTheTax(t *testing.T) {
+	t.Parallel()
+
+	// Given I start a new capital gain calculation
+	capitalGain := models.NewCapitalGain()
+
+	// And a buy of 10000 shares at 10.00 charged 100.00 in fees
+	buyOperation := models.NewBuy(models.NewQuantity(10000), models.NewMonetaryValue(10.00)).
+		WithFees(models.NewMonetaryValue(100.00))
+
+	// And a sell of 5000 shares at 20.00 charged 50.00 in fees
+	sellOperation := models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(20.00)).
+		WithFees(models.NewMonetaryValue(50.00))
+
+	// When I apply the buy and sell operations to the capital gain
+	capitalGain.ApplyOperations([]models.Operation{buyOperation, sellOperation})
+
+	// Then the fees should be reported with each trade, leaving the cost, the gain and the tax unchanged
+	taxEvents := capitalGain.Events()
+
+	assert.Equal(t, 100.00, taxEvents[0].Trade().Fees())
+	assert.Equal(t, 50.00, taxEvents[1].Trade().Fees())
+	assert.Equal(t, 10.00, taxEvents[1].Position().AverageUnitCost())
+	assert.Equal(t, 50000.00, taxEvents[1].Realization().Gain())
+	assert.Equal(t, []float64{0.00, 10000.00}, test.TaxAmountsFromEvents(taxEvents))
+}
+
+func TestCapitalGainApplyOperationsGivenOperationsOfTwoTickersWhenApplyOperationsThenPositionsAreKeptPerTickerAndLossIsShared(t *testing.T) {
+	t.Parallel()
+
+	// Given I start a new capital gain calculation
+	capitalGain := models.NewCapitalGain()
+
+	// And buys of 10000 PETR4 shares at 10.00 and of 10000 VALE3 shares at 100.00
+	petr4 := models.NewMetadata().WithTicker("PETR4")
+	vale3 := models.NewMetadata().WithTicker("VALE3")
+
+	// And a sell of 5000 VALE3 shares at 90.00 followed by a sell of 5000 PETR4 shares at 20.00
+	operations := []models.Operation{
+		models.NewBuy(models.NewQuantity(10000), models.NewMonetaryValue(10.00)).WithMetadata(petr4),
+		models.NewBuy(models.NewQuantity(10000), models.NewMonetaryValue(100.00)).WithMetadata(vale3),
+		models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(90.00)).WithMetadata(vale3),
+		models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(20.00)).WithMetadata(petr4),
+	}
+
+	// When I apply the operations to the capital gain
+	capitalGain.ApplyOperations(operations)
+
+	// Then each sell should realize its gain from the average unit cost of its own ticker
+	// And the loss realized on VALE3 should be deducted from the gain realized on PETR4
+	taxEvents := capitalGain.Events()
+	assert.Equal(t, -50000.00, taxEvents[2].Realization().Gain())
+	assert.Equal(t, 50000.00, taxEvents[3].Realization().Gain())
+	assert.Equal(t, []float64{0.00, 0.00, 0.00, 0.00}, test.TaxAmountsFromEvents(taxEvents))
+
+	// And the holdings should keep a position per ticker, sharing what is left of the loss
+	holdings := capitalGain.Holdings()
+	assert.Equal(t, []string{"PETR4", "VALE3"}, holdings.Tickers())
+	assert.Equal(t, 5000, holdings.PositionOf("PETR4").Quantity().ToInt())
+	assert.Equal(t, 10.00, holdings.PositionOf("PETR4").AverageUnitCost().ToFloat64())
+	assert.Equal(t, 5000, holdings.PositionOf("VALE3").Quantity().ToInt())
+	assert.Equal(t, 100.00, holdings.PositionOf("VALE3").AverageUnitCost().ToFloat64())
+	assert.True(t, holdings.AccumulatedLoss().IsZero())
+}
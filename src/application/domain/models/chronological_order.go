@@ -0,0 +1,179 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrUndatedOperation is returned when operations must be ordered chronologically,
+	// but one of them has no trade date.
+	ErrUndatedOperation = errors.New("operation has no trade date")
+
+	// ErrAmbiguousOrder is returned when a buy and a sell were traded at the same moment
+	// and no sequence tells which one came first.
+	ErrAmbiguousOrder = errors.New("buy and sell traded at the same moment without a sequence")
+)
+
+// NewChronologicalOrder returns the indexes of the operations sorted by the moment they were
+// traded. Operations of the same day are ordered by time of day only when all of them are timed.
+// Operations traded at the same moment keep their original order, unless all of them carry a
+// sequence; a buy and a sell at the same moment without a sequence cannot be ordered.
+func NewChronologicalOrder(operations []Operation) ([]int, error) {
+	order := make([]int, len(operations))
+
+	for index, operation := range operations {
+		if !operation.Metadata().IsDated() {
+			return nil, fmt.Errorf("%w: operation at index %d", ErrUndatedOperation, index)
+		}
+
+		order[index] = index
+	}
+
+	sort.SliceStable(order, func(first, second int) bool {
+		return dayOf(operations, order[first]).Before(dayOf(operations, order[second]))
+	})
+
+	chronologicalOrder := make([]int, 0, len(operations))
+
+	for _, sameDay := range splitWhen(order, func(first, second int) bool {
+		return !dayOf(operations, first).Equal(dayOf(operations, second))
+	}) {
+		for _, sameMoment := range momentsOf(operations, sameDay) {
+			resolved, err := resolveMoment(operations, sameMoment)
+
+			if err != nil {
+				return nil, err
+			}
+
+			chronologicalOrder = append(chronologicalOrder, resolved...)
+		}
+	}
+
+	return chronologicalOrder, nil
+}
+
+func momentsOf(operations []Operation, sameDay []int) [][]int {
+	for _, index := range sameDay {
+		if !operations[index].Metadata().IsTimed() {
+			return [][]int{sameDay}
+		}
+	}
+
+	sort.SliceStable(sameDay, func(first, second int) bool {
+		return tradedAtOf(operations, sameDay[first]).Before(tradedAtOf(operations, sameDay[second]))
+	})
+
+	return splitWhen(sameDay, func(first, second int) bool {
+		return !tradedAtOf(operations, first).Equal(tradedAtOf(operations, second))
+	})
+}
+
+func resolveMoment(operations []Operation, sameMoment []int) ([]int, error) {
+	if !isSequenced(operations, sameMoment) {
+		if hasBothSides(operations, sameMoment) {
+			return nil, ambiguousOrderError(operations, sameMoment)
+		}
+
+		return sameMoment, nil
+	}
+
+	sort.SliceStable(sameMoment, func(first, second int) bool {
+		return sequenceOf(operations, sameMoment[first]) < sequenceOf(operations, sameMoment[second])
+	})
+
+	for _, sameSequence := range splitWhen(sameMoment, func(first, second int) bool {
+		return sequenceOf(operations, first) != sequenceOf(operations, second)
+	}) {
+		if hasBothSides(operations, sameSequence) {
+			return nil, ambiguousOrderError(operations, sameSequence)
+		}
+	}
+
+	return sameMoment, nil
+}
+
+// splitWhen splits the indexes into consecutive groups, starting a new group whenever
+// the boundary function reports that two neighbouring indexes do not belong together.
+func splitWhen(indexes []int, boundary func(first, second int) bool) [][]int {
+	groups := make([][]int, 0)
+	start := 0
+
+	for position := 1; position <= len(indexes); position++ {
+		if position == len(indexes) || boundary(indexes[position-1], indexes[position]) {
+			groups = append(groups, indexes[start:position])
+			start = position
+		}
+	}
+
+	return groups
+}
+
+func isSequenced(operations []Operation, indexes []int) bool {
+	for _, index := range indexes {
+		if !operations[index].Metadata().HasSequence() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasBothSides(operations []Operation, indexes []int) bool {
+	hasBuy, hasSell := false, false
+
+	for _, index := range indexes {
+		switch operations[index].(type) {
+		case Buy:
+			hasBuy = true
+		case Sell:
+			hasSell = true
+		}
+	}
+
+	return hasBuy && hasSell
+}
+
+// AmbiguousOrderError reports the operations, by their index, that were traded at the same moment
+// with nothing telling whether the buys or the sells among them came first.
+type AmbiguousOrderError struct {
+	Indexes    []int
+	references []string
+}
+
+func (ambiguousOrder AmbiguousOrderError) Error() string {
+	return fmt.Sprintf("%s: operations at indexes %v", ErrAmbiguousOrder, ambiguousOrder.references)
+}
+
+func (ambiguousOrder AmbiguousOrderError) Unwrap() error {
+	return ErrAmbiguousOrder
+}
+
+func ambiguousOrderError(operations []Operation, indexes []int) error {
+	references := make([]string, len(indexes))
+
+	for position, index := range indexes {
+		references[position] = strconv.Itoa(index)
+
+		if id := operations[index].Metadata().ID(); id != "" {
+			references[position] = fmt.Sprintf("%d (%s)", index, id)
+		}
+	}
+
+	return AmbiguousOrderError{Indexes: append([]int(nil), indexes...), references: references}
+}
+
+func dayOf(operations []Operation, index int) time.Time {
+	return operations[index].Metadata().Day()
+}
+
+func tradedAtOf(operations []Operation, index int) time.Time {
+	return operations[index].Metadata().TradedAt()
+}
+
+func sequenceOf(operations []Operation, index int) int {
+	return operations[index].Metadata().Sequence()
+}
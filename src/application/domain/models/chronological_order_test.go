@@ -0,0 +1,161 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"capital-gains/src/application/domain/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tradedAt(id string, timestamp string) models.Metadata {
+	parsed, err := time.Parse("2006-01-02 15:04:05", timestamp)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return models.NewMetadata().WithID(id).WithTradedAt(parsed)
+}
+
+func buyWith(metadata models.Metadata) models.Operation {
+	return models.NewBuy(models.NewQuantity(100), models.NewMonetaryValue(10.00)).WithMetadata(metadata)
+}
+
+func sellWith(metadata models.Metadata) models.Operation {
+	return models.NewSell(models.NewQuantity(100), models.NewMonetaryValue(20.00)).WithMetadata(metadata)
+}
+
+func TestChronologicalOrderGivenOperationsOutOfOrderWhenNewChronologicalOrderThenIndexesAreSortedByTradeDate(t *testing.T) {
+	t.Parallel()
+
+	// Given operations exported by two brokers and interleaved out of order
+	operations := []models.Operation{
+		sellWith(tradedOn("sell-1", "2024-03-01")),
+		buyWith(tradedOn("buy-1", "2024-01-10")),
+		buyWith(tradedOn("buy-2", "2024-02-10")),
+	}
+
+	// When I compute the chronological order
+	order, err := models.NewChronologicalOrder(operations)
+
+	// Then I expect the operations to be ordered by trade date
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 0}, order)
+}
+
+func TestChronologicalOrderGivenSameDayTimedOperationsWhenNewChronologicalOrderThenIndexesAreSortedByTime(t *testing.T) {
+	t.Parallel()
+
+	// Given a sell and a buy on the same day, both with a time of day
+	operations := []models.Operation{
+		sellWith(tradedAt("sell-1", "2024-01-10 15:00:00")),
+		buyWith(tradedAt("buy-1", "2024-01-10 10:00:00")),
+	}
+
+	// When I compute the chronological order
+	order, err := models.NewChronologicalOrder(operations)
+
+	// Then I expect the buy to come first
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 0}, order)
+}
+
+func TestChronologicalOrderGivenSameMomentBuyAndSellWithoutSequenceWhenNewChronologicalOrderThenOrderIsAmbiguous(t *testing.T) {
+	t.Parallel()
+
+	// Given a sell and a buy on the same day, without time of day nor sequence
+	operations := []models.Operation{
+		sellWith(tradedOn("sell-1", "2024-01-10")),
+		buyWith(tradedOn("buy-1", "2024-01-10")),
+	}
+
+	// When I compute the chronological order
+	_, err := models.NewChronologicalOrder(operations)
+
+	// Then I expect the order to be rejected as ambiguous, naming both operations
+	assert.ErrorIs(t, err, models.ErrAmbiguousOrder)
+
+	var ambiguousOrder models.AmbiguousOrderError
+
+	assert.ErrorAs(t, err, &ambiguousOrder)
+	assert.Equal(t, []int{0, 1}, ambiguousOrder.Indexes)
+}
+
+func TestChronologicalOrderGivenSameMomentBuyAndSellWithSequenceWhenNewChronologicalOrderThenIndexesAreSortedBySequence(t *testing.T) {
+	t.Parallel()
+
+	// Given a sell and a buy on the same day, with a sequence telling the buy came first
+	operations := []models.Operation{
+		sellWith(tradedOn("sell-1", "2024-01-10").WithSequence(2)),
+		buyWith(tradedOn("buy-1", "2024-01-10").WithSequence(1)),
+	}
+
+	// When I compute the chronological order
+	order, err := models.NewChronologicalOrder(operations)
+
+	// Then I expect the buy to come first
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 0}, order)
+}
+
+func TestChronologicalOrderGivenSameMomentOperationsOfTheSameSideWhenNewChronologicalOrderThenOriginalOrderIsKept(t *testing.T) {
+	t.Parallel()
+
+	// Given two buys on the same day, without time of day nor sequence
+	operations := []models.Operation{
+		buyWith(tradedOn("buy-1", "2024-01-10")),
+		buyWith(tradedOn("buy-2", "2024-01-10")),
+	}
+
+	// When I compute the chronological order
+	order, err := models.NewChronologicalOrder(operations)
+
+	// Then I expect the original order to be kept
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1}, order)
+}
+
+func TestChronologicalOrderGivenUndatedOperationWhenNewChronologicalOrderThenUndatedOperationIsReported(t *testing.T) {
+	t.Parallel()
+
+	// Given a dated buy and an undated sell
+	operations := []models.Operation{
+		buyWith(tradedOn("buy-1", "2024-01-10")),
+		sellWith(models.NewMetadata().WithID("sell-1")),
+	}
+
+	// When I compute the chronological order
+	_, err := models.NewChronologicalOrder(operations)
+
+	// Then I expect the undated operation to be reported
+	assert.ErrorIs(t, err, models.ErrUndatedOperation)
+}
+
+func TestCapitalGainApplyOperationsChronologicallyGivenSellBeforeItsBuyWhenApplyThenEventsKeepInputOrder(t *testing.T) {
+	t.Parallel()
+
+	// Given a taxable sell given before the buy it depends on
+	operations := []models.Operation{
+		models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(20.00)).
+			WithMetadata(tradedOn("sell-1", "2024-02-05")),
+		models.NewBuy(models.NewQuantity(10000), models.NewMonetaryValue(10.00)).
+			WithMetadata(tradedOn("buy-1", "2024-01-10")),
+	}
+
+	// When I apply the operations chronologically
+	capitalGain := models.NewCapitalGain()
+	err := capitalGain.ApplyOperationsChronologically(operations)
+
+	// Then I expect the sell to be taxed as if applied after the buy, with events in input order
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sell-1", "buy-1"}, []string{
+		capitalGain.Events()[0].OperationID(),
+		capitalGain.Events()[1].OperationID(),
+	})
+	assert.Equal(t, 10000.00, capitalGain.Events()[0].Amount())
+
+	// And I expect the applied order to report the buy first
+	assert.Equal(t, []int{1, 0}, capitalGain.AppliedOrder())
+}
@@ -0,0 +1,12 @@
+package models
+
+// Correction defines a retroactive change to an operation registered earlier
+// in the portfolio, such as a trade amended or cancelled by the broker.
+type Correction interface {
+	// ApplyTo applies this correction to the given portfolio.
+	//
+	// [param]  portfolio *Portfolio   portfolio holding the operation to be corrected.
+	//
+	// [return] error   when the corrected operation is not part of the portfolio.
+	ApplyTo(portfolio *Portfolio) error
+}
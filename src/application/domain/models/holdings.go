@@ -0,0 +1,55 @@
+package models
+
+import (
+	"maps"
+	"slices"
+)
+
+// Holdings are the positions of an account, one per ticker traded, sharing the accumulated loss of
+// the account: a loss realized on one ticker is deducted from the gains realized on any other.
+type Holdings struct {
+	positions       map[string]Position
+	accumulatedLoss MonetaryValue
+}
+
+// NewHoldings returns the holdings starting from the given position, held in no ticker in particular,
+// whose accumulated loss becomes the one of the account.
+func NewHoldings(position Position) Holdings {
+	holdings := Holdings{positions: make(map[string]Position), accumulatedLoss: position.AccumulatedLoss()}
+
+	if !position.Quantity().IsZero() {
+		holdings.positions[""] = position
+	}
+
+	return holdings
+}
+
+// PositionOf returns the position held in the ticker, carrying the accumulated loss of the account.
+func (holdings Holdings) PositionOf(ticker string) Position {
+	position, exists := holdings.positions[ticker]
+
+	if !exists {
+		position = NewPosition()
+	}
+
+	return NewOpeningPosition(position.Quantity(), position.AverageUnitCost(), holdings.accumulatedLoss)
+}
+
+// Hold keeps the position as the one held in the ticker, its accumulated loss as the one of the account.
+func (holdings *Holdings) Hold(ticker string, position Position) {
+	positions := maps.Clone(holdings.positions)
+	positions[ticker] = position
+
+	holdings.positions = positions
+	holdings.accumulatedLoss = position.AccumulatedLoss()
+}
+
+// Tickers returns the tickers the account held a position in, in alphabetical order, the operations
+// without a ticker being held in the empty one.
+func (holdings Holdings) Tickers() []string {
+	return slices.Sorted(maps.Keys(holdings.positions))
+}
+
+func (holdings Holdings) AccumulatedLoss() MonetaryValue {
+	return holdings.accumulatedLoss
+}
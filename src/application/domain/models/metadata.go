@@ -0,0 +1,117 @@
+package models
+
+import "time"
+
+// Metadata carries the attributes that identify an operation and place it in time.
+// It never changes the tax produced by the operation it belongs to.
+type Metadata struct {
+	id       string
+	tradedAt time.Time
+	timed    bool
+	sequence int
+	account  string
+	ticker   string
+}
+
+func NewMetadata() Metadata {
+	return Metadata{}
+}
+
+func (metadata Metadata) WithID(id string) Metadata {
+	metadata.id = id
+	return metadata
+}
+
+// WithTradedOn places the operation on a day, without a time of day.
+func (metadata Metadata) WithTradedOn(tradedOn time.Time) Metadata {
+	year, month, day := tradedOn.Date()
+
+	metadata.tradedAt = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	metadata.timed = false
+	return metadata
+}
+
+// WithTradedAt places the operation at an exact time of day.
+func (metadata Metadata) WithTradedAt(tradedAt time.Time) Metadata {
+	metadata.tradedAt = tradedAt
+	metadata.timed = true
+	return metadata
+}
+
+// WithSequence orders the operation among others traded at the same moment.
+// A zero sequence means the operation has none.
+func (metadata Metadata) WithSequence(sequence int) Metadata {
+	metadata.sequence = sequence
+	return metadata
+}
+
+// WithAccount assigns the operation to an account, whose portfolio is computed independently.
+func (metadata Metadata) WithAccount(account string) Metadata {
+	metadata.account = account
+	return metadata
+}
+
+// WithTicker identifies the asset traded by the operation.
+func (metadata Metadata) WithTicker(ticker string) Metadata {
+	metadata.ticker = ticker
+	return metadata
+}
+
+func (metadata Metadata) ID() string {
+	return metadata.id
+}
+
+func (metadata Metadata) HasID() bool {
+	return metadata.id != ""
+}
+
+func (metadata Metadata) TradedAt() time.Time {
+	return metadata.tradedAt
+}
+
+func (metadata Metadata) IsDated() bool {
+	return !metadata.tradedAt.IsZero()
+}
+
+func (metadata Metadata) IsTimed() bool {
+	return metadata.timed
+}
+
+func (metadata Metadata) Sequence() int {
+	return metadata.sequence
+}
+
+func (metadata Metadata) HasSequence() bool {
+	return metadata.sequence > 0
+}
+
+// Day returns the day the operation was traded in, or the zero time when the operation is undated.
+func (metadata Metadata) Day() time.Time {
+	if !metadata.IsDated() {
+		return time.Time{}
+	}
+
+	year, month, day := metadata.tradedAt.Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Month returns the first day of the month the operation was traded in,
+// or the zero time when the operation is undated.
+func (metadata Metadata) Month() time.Time {
+	if !metadata.IsDated() {
+		return time.Time{}
+	}
+
+	year, month, _ := metadata.tradedAt.Date()
+
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (metadata Metadata) Account() string {
+	return metadata.account
+}
+
+func (metadata Metadata) Ticker() string {
+	return metadata.ticker
+}
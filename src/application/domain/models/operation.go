@@ -9,4 +9,9 @@ type Operation interface {
 	//
 	// [return] Tax   produced by this operation (zero means exempted).
 	ApplyTo(position *Position) Tax
+
+	// Metadata returns the attributes identifying this operation and placing it in time.
+	//
+	// [return] Metadata   identification of this operation.
+	Metadata() Metadata
 }
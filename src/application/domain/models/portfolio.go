@@ -0,0 +1,114 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOperationNotFound is returned when a correction targets an operation id
+// that is not registered in the portfolio, or that was already cancelled.
+var ErrOperationNotFound = errors.New("operation not found")
+
+type CorrectionStatus string
+
+const (
+	Unchanged CorrectionStatus = "unchanged"
+	Amended   CorrectionStatus = "amended"
+	Cancelled CorrectionStatus = "cancelled"
+)
+
+const noCorrectedIndex = -1
+
+type portfolioEntry struct {
+	original Operation
+	current  Operation
+	status   CorrectionStatus
+}
+
+// Portfolio holds the registered operations in the order they were applied,
+// along with the corrections made to them afterward.
+type Portfolio struct {
+	entries             []portfolioEntry
+	firstCorrectedIndex int
+}
+
+func NewPortfolio(operations []Operation) Portfolio {
+	entries := make([]portfolioEntry, len(operations))
+
+	for index, operation := range operations {
+		entries[index] = portfolioEntry{original: operation, current: operation, status: Unchanged}
+	}
+
+	return Portfolio{
+		entries:             entries,
+		firstCorrectedIndex: noCorrectedIndex,
+	}
+}
+
+func (portfolio *Portfolio) Amend(id string, replacement Operation) error {
+	index, err := portfolio.indexOf(id)
+
+	if err != nil {
+		return err
+	}
+
+	portfolio.entries[index].current = replacement
+	portfolio.entries[index].status = Amended
+	portfolio.markCorrected(index)
+
+	return nil
+}
+
+func (portfolio *Portfolio) Cancel(id string) error {
+	index, err := portfolio.indexOf(id)
+
+	if err != nil {
+		return err
+	}
+
+	portfolio.entries[index].current = nil
+	portfolio.entries[index].status = Cancelled
+	portfolio.markCorrected(index)
+
+	return nil
+}
+
+// Operations returns the operations as originally registered.
+func (portfolio Portfolio) Operations() []Operation {
+	operations := make([]Operation, len(portfolio.entries))
+
+	for index, entry := range portfolio.entries {
+		operations[index] = entry.original
+	}
+
+	return operations
+}
+
+// CorrectedOperations returns the operations after every correction, leaving out cancelled ones.
+func (portfolio Portfolio) CorrectedOperations() []Operation {
+	operations := make([]Operation, 0, len(portfolio.entries))
+
+	for _, entry := range portfolio.entries {
+		if entry.current != nil {
+			operations = append(operations, entry.current)
+		}
+	}
+
+	return operations
+}
+
+func (portfolio *Portfolio) indexOf(id string) (int, error) {
+	for index, entry := range portfolio.entries {
+		if id != "" && entry.current != nil && entry.original.Metadata().ID() == id {
+			return index, nil
+		}
+	}
+
+	return noCorrectedIndex, fmt.Errorf("%w: %q", ErrOperationNotFound, id)
+}
+
+func (portfolio *Portfolio) markCorrected(index int) {
+	if portfolio.firstCorrectedIndex == noCorrectedIndex || index < portfolio.firstCorrectedIndex {
+		portfolio.firstCorrectedIndex = index
+	}
+}
@@ -19,6 +19,30 @@ func NewPosition() Position {
 	}
 }
 
+func NewOpeningPosition(quantity Quantity, averageUnitCost MonetaryValue, accumulatedLoss MonetaryValue) Position {
+	if quantity.IsZero() {
+		averageUnitCost = NewZeroMonetaryValue()
+	}
+
+	return Position{
+		quantity:        quantity,
+		averageUnitCost: averageUnitCost,
+		accumulatedLoss: accumulatedLoss,
+	}
+}
+
+func (position Position) Quantity() Quantity {
+	return position.quantity
+}
+
+func (position Position) AverageUnitCost() MonetaryValue {
+	return position.averageUnitCost
+}
+
+func (position Position) AccumulatedLoss() MonetaryValue {
+	return position.accumulatedLoss
+}
+
 func (position *Position) Buy(quantity Quantity, unitCost MonetaryValue) {
 	combinedQuantity := position.quantity.Add(quantity)
 
@@ -49,28 +73,30 @@ func (position *Position) Sell(quantity Quantity, unitCost MonetaryValue) Tax {
 
 	if grossCapitalGain.IsNegative() {
 		position.accumulatedLoss = position.accumulatedLoss.Add(grossCapitalGain.AbsoluteValue())
-		return NewTax(NewZeroMonetaryValue())
+		return NewTax(NewZeroMonetaryValue()).WithRealization(grossCapitalGain, NewZeroMonetaryValue())
 	}
 
 	if proceeds.IsGreaterThan(taxFreeThreshold) {
 		if grossCapitalGain.IsZero() {
-			return NewTax(NewZeroMonetaryValue())
+			return NewTax(NewZeroMonetaryValue()).WithRealization(grossCapitalGain, NewZeroMonetaryValue())
 		}
 
 		netProfit := grossCapitalGain
 
 		if position.accumulatedLoss.IsGreaterThanOrEqual(netProfit) {
 			position.accumulatedLoss = position.accumulatedLoss.Subtract(netProfit)
-			return NewTax(NewZeroMonetaryValue())
+			return NewTax(NewZeroMonetaryValue()).WithRealization(grossCapitalGain, netProfit)
 		}
 
+		deductedLoss := position.accumulatedLoss
+
 		if position.accumulatedLoss.IsPositive() {
 			netProfit = netProfit.Subtract(position.accumulatedLoss)
 			position.accumulatedLoss = NewZeroMonetaryValue()
 		}
 
-		return NewTax(netProfit.MultiplyBy(taxRate))
+		return NewTax(netProfit.MultiplyBy(taxRate)).WithRealization(grossCapitalGain, deductedLoss)
 	}
 
-	return NewTax(NewZeroMonetaryValue())
+	return NewTax(NewZeroMonetaryValue()).WithRealization(grossCapitalGain, NewZeroMonetaryValue())
 }
@@ -3,15 +3,34 @@ package models
 type Sell struct {
 	quantity Quantity
 	unitCost MonetaryValue
+	fees     MonetaryValue
+	metadata Metadata
 }
 
 func NewSell(quantity Quantity, unitCost MonetaryValue) Sell {
 	return Sell{
 		quantity: quantity,
 		unitCost: unitCost,
+		fees:     NewZeroMonetaryValue(),
 	}
 }
 
 func (sell Sell) ApplyTo(position *Position) Tax {
 	return position.Sell(sell.quantity, sell.unitCost)
 }
+
+func (sell Sell) Metadata() Metadata {
+	return sell.metadata
+}
+
+func (sell Sell) WithMetadata(metadata Metadata) Sell {
+	sell.metadata = metadata
+	return sell
+}
+
+// WithFees returns the operation charging the given fees, reported along with its trade without
+// changing the realized gain.
+func (sell Sell) WithFees(fees MonetaryValue) Sell {
+	sell.fees = fees
+	return sell
+}
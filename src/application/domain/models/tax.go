@@ -1,17 +1,39 @@
 package models
 
 type Tax struct {
-	value MonetaryValue
+	value        MonetaryValue
+	gain         MonetaryValue
+	deductedLoss MonetaryValue
 }
 
 func NewTax(value MonetaryValue) Tax {
-	return Tax{value: value}
+	return Tax{
+		value:        value,
+		gain:         NewZeroMonetaryValue(),
+		deductedLoss: NewZeroMonetaryValue(),
+	}
+}
+
+// WithRealization returns the tax along with the gain realized by the operation
+// (negative for a loss) and the accumulated loss deducted from that gain.
+func (tax Tax) WithRealization(gain MonetaryValue, deductedLoss MonetaryValue) Tax {
+	tax.gain = gain
+	tax.deductedLoss = deductedLoss
+	return tax
 }
 
 func (tax Tax) Value() MonetaryValue {
 	return tax.value
 }
 
+func (tax Tax) Gain() MonetaryValue {
+	return tax.gain
+}
+
+func (tax Tax) DeductedLoss() MonetaryValue {
+	return tax.deductedLoss
+}
+
 func (tax Tax) IsExempted() bool {
 	return tax.value.IsZero()
 }
@@ -0,0 +1,186 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"capital-gains/src/application/domain/events"
+)
+
+// OperationTaxDiff compares the tax of a single operation before and after the corrections.
+type OperationTaxDiff struct {
+	index  int
+	id     string
+	status CorrectionStatus
+	month  time.Time
+	before MonetaryValue
+	after  MonetaryValue
+}
+
+func (diff OperationTaxDiff) Index() int {
+	return diff.index
+}
+
+func (diff OperationTaxDiff) ID() string {
+	return diff.id
+}
+
+func (diff OperationTaxDiff) Status() CorrectionStatus {
+	return diff.status
+}
+
+func (diff OperationTaxDiff) Month() time.Time {
+	return diff.month
+}
+
+func (diff OperationTaxDiff) Before() MonetaryValue {
+	return diff.before
+}
+
+func (diff OperationTaxDiff) After() MonetaryValue {
+	return diff.after
+}
+
+func (diff OperationTaxDiff) Difference() MonetaryValue {
+	return diff.after.Subtract(diff.before)
+}
+
+// MonthlyTaxDiff compares the tax due in a month before and after the corrections.
+// A non-zero difference means the tax payment of that month must be rectified.
+type MonthlyTaxDiff struct {
+	month  time.Time
+	before MonetaryValue
+	after  MonetaryValue
+}
+
+func (diff MonthlyTaxDiff) Month() time.Time {
+	return diff.month
+}
+
+func (diff MonthlyTaxDiff) Before() MonetaryValue {
+	return diff.before
+}
+
+func (diff MonthlyTaxDiff) After() MonetaryValue {
+	return diff.after
+}
+
+func (diff MonthlyTaxDiff) Difference() MonetaryValue {
+	return diff.after.Subtract(diff.before)
+}
+
+// TaxDiff reports how the corrections of a portfolio change the taxes, per operation
+// from the first corrected one onward, and per month wherever the monthly total changed.
+type TaxDiff struct {
+	account    string
+	operations []OperationTaxDiff
+	months     []MonthlyTaxDiff
+}
+
+func NewTaxDiff(openingPosition Position, portfolio Portfolio) TaxDiff {
+	return NewTaxDiffFor("", NewHoldings(openingPosition), portfolio)
+}
+
+// NewTaxDiffFor reports how the corrections change the taxes of the given account.
+func NewTaxDiffFor(account string, openingHoldings Holdings, portfolio Portfolio) TaxDiff {
+	original := NewCapitalGainHolding(account, openingHoldings)
+	original.ApplyOperations(portfolio.Operations())
+
+	corrected := NewCapitalGainHolding(account, openingHoldings)
+	corrected.ApplyOperations(portfolio.CorrectedOperations())
+
+	operationDiffs := compareOperations(portfolio, original.Events(), corrected.Events())
+
+	return TaxDiff{
+		account:    account,
+		operations: fromFirstCorrection(operationDiffs, portfolio.firstCorrectedIndex),
+		months:     compareMonths(portfolio, operationDiffs),
+	}
+}
+
+func (taxDiff TaxDiff) Account() string {
+	return taxDiff.account
+}
+
+func (taxDiff TaxDiff) Operations() []OperationTaxDiff {
+	operations := make([]OperationTaxDiff, len(taxDiff.operations))
+	copy(operations, taxDiff.operations)
+
+	return operations
+}
+
+func (taxDiff TaxDiff) Months() []MonthlyTaxDiff {
+	months := make([]MonthlyTaxDiff, len(taxDiff.months))
+	copy(months, taxDiff.months)
+
+	return months
+}
+
+func compareOperations(portfolio Portfolio, originalEvents, correctedEvents []events.Event) []OperationTaxDiff {
+	diffs := make([]OperationTaxDiff, len(portfolio.entries))
+	correctedIndex := 0
+
+	for index, entry := range portfolio.entries {
+		diff := OperationTaxDiff{
+			index:  index,
+			id:     entry.original.Metadata().ID(),
+			status: entry.status,
+			month:  entry.original.Metadata().Month(),
+			before: NewMonetaryValue(originalEvents[index].Amount()),
+			after:  NewZeroMonetaryValue(),
+		}
+
+		if entry.current != nil {
+			diff.after = NewMonetaryValue(correctedEvents[correctedIndex].Amount())
+			correctedIndex++
+		}
+
+		diffs[index] = diff
+	}
+
+	return diffs
+}
+
+func compareMonths(portfolio Portfolio, operationDiffs []OperationTaxDiff) []MonthlyTaxDiff {
+	totals := make(map[time.Time]*MonthlyTaxDiff)
+
+	totalOf := func(month time.Time) *MonthlyTaxDiff {
+		if _, exists := totals[month]; !exists {
+			totals[month] = &MonthlyTaxDiff{month: month, before: NewZeroMonetaryValue(), after: NewZeroMonetaryValue()}
+		}
+
+		return totals[month]
+	}
+
+	for index, entry := range portfolio.entries {
+		originalTotal := totalOf(entry.original.Metadata().Month())
+		originalTotal.before = originalTotal.before.Add(operationDiffs[index].before)
+
+		if entry.current != nil {
+			correctedTotal := totalOf(entry.current.Metadata().Month())
+			correctedTotal.after = correctedTotal.after.Add(operationDiffs[index].after)
+		}
+	}
+
+	months := make([]MonthlyTaxDiff, 0, len(totals))
+
+	for _, total := range totals {
+		if !total.Difference().IsZero() {
+			months = append(months, *total)
+		}
+	}
+
+	sort.Slice(months, func(first, second int) bool {
+		return months[first].month.Before(months[second].month)
+	})
+
+	return months
+}
+
+func fromFirstCorrection(operationDiffs []OperationTaxDiff, firstCorrectedIndex int) []OperationTaxDiff {
+	if firstCorrectedIndex == noCorrectedIndex {
+		return make([]OperationTaxDiff, 0)
+	}
+
+	return operationDiffs[firstCorrectedIndex:]
+}
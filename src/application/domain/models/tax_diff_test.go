@@ -0,0 +1,111 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"capital-gains/src/application/domain/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tradedOn(id string, date string) models.Metadata {
+	tradedAt, err := time.Parse("2006-01-02", date)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return models.NewMetadata().WithID(id).WithTradedOn(tradedAt)
+}
+
+func TestTaxDiffGivenCancelledLossSaleWhenNewTaxDiffThenLaterTaxableSaleAndItsMonthAreReported(t *testing.T) {
+	t.Parallel()
+
+	// Given a portfolio with a buy in January, a loss sale in February and a taxable sale in March
+	portfolio := models.NewPortfolio([]models.Operation{
+		models.NewBuy(models.NewQuantity(10000), models.NewMonetaryValue(10.00)).
+			WithMetadata(tradedOn("buy-1", "2024-01-10")),
+		models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(5.00)).
+			WithMetadata(tradedOn("sell-1", "2024-02-05")),
+		models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(20.00)).
+			WithMetadata(tradedOn("sell-2", "2024-03-01")),
+	})
+
+	// And the broker cancels the February loss sale
+	err := models.NewCancellation("sell-1").ApplyTo(&portfolio)
+	assert.NoError(t, err)
+
+	// When I calculate the tax diff
+	taxDiff := models.NewTaxDiff(models.NewPosition(), portfolio)
+
+	// Then I expect the operations from the cancelled one onward to be reported
+	operations := taxDiff.Operations()
+	assert.Len(t, operations, 2)
+
+	assert.Equal(t, "sell-1", operations[0].ID())
+	assert.Equal(t, models.Cancelled, operations[0].Status())
+	assert.Equal(t, models.NewZeroMonetaryValue(), operations[0].Difference())
+
+	// And I expect the March sale to lose the offset of the cancelled loss
+	assert.Equal(t, "sell-2", operations[1].ID())
+	assert.Equal(t, models.Unchanged, operations[1].Status())
+	assert.Equal(t, models.NewMonetaryValue(5000.00), operations[1].Before())
+	assert.Equal(t, models.NewMonetaryValue(10000.00), operations[1].After())
+
+	// And I expect only March to be reported as a month to be rectified
+	months := taxDiff.Months()
+	assert.Len(t, months, 1)
+	assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), months[0].Month())
+	assert.Equal(t, models.NewMonetaryValue(5000.00), months[0].Difference())
+}
+
+func TestTaxDiffGivenAmendedSaleMovedToAnotherMonthWhenNewTaxDiffThenBothMonthsAreReported(t *testing.T) {
+	t.Parallel()
+
+	// Given a portfolio with a buy and a taxable sale in February
+	portfolio := models.NewPortfolio([]models.Operation{
+		models.NewBuy(models.NewQuantity(10000), models.NewMonetaryValue(10.00)).
+			WithMetadata(tradedOn("buy-1", "2024-01-10")),
+		models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(20.00)).
+			WithMetadata(tradedOn("sell-1", "2024-02-05")),
+	})
+
+	// And the broker amends the sale to a lower price traded in March
+	replacement := models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(15.00)).
+		WithMetadata(tradedOn("sell-1", "2024-03-05"))
+	err := models.NewAmendment("sell-1", replacement).ApplyTo(&portfolio)
+	assert.NoError(t, err)
+
+	// When I calculate the tax diff
+	taxDiff := models.NewTaxDiff(models.NewPosition(), portfolio)
+
+	// Then I expect the amended sale to be reported with its tax before and after the correction
+	operations := taxDiff.Operations()
+	assert.Len(t, operations, 1)
+	assert.Equal(t, models.Amended, operations[0].Status())
+	assert.Equal(t, models.NewMonetaryValue(10000.00), operations[0].Before())
+	assert.Equal(t, models.NewMonetaryValue(5000.00), operations[0].After())
+
+	// And I expect February to be fully refunded and March to be charged
+	months := taxDiff.Months()
+	assert.Len(t, months, 2)
+	assert.Equal(t, models.NewMonetaryValue(-10000.00), months[0].Difference())
+	assert.Equal(t, models.NewMonetaryValue(5000.00), months[1].Difference())
+}
+
+func TestTaxDiffGivenCorrectionOfUnknownOperationWhenApplyToThenOperationNotFoundIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a portfolio with a single identified buy
+	portfolio := models.NewPortfolio([]models.Operation{
+		models.NewBuy(models.NewQuantity(100), models.NewMonetaryValue(10.00)).
+			WithMetadata(tradedOn("buy-1", "2024-01-10")),
+	})
+
+	// When I cancel an operation that is not part of the portfolio
+	err := models.NewCancellation("unknown").ApplyTo(&portfolio)
+
+	// Then I expect the operation not found error
+	assert.ErrorIs(t, err, models.ErrOperationNotFound)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"sort"
+
+	"capital-gains/src/application/domain/models"
+)
+
+// accountsOf splits the operations by account, adding the accounts that only have an
+// opening position, and a single unnamed account when there is nothing at all.
+func accountsOf(operations []models.Operation, openingPositions map[string]models.Holdings) []models.Account {
+	accounts := models.SplitByAccount(operations)
+	known := make(map[string]struct{}, len(accounts))
+
+	for _, account := range accounts {
+		known[account.Name()] = struct{}{}
+	}
+
+	positionOnly := make([]string, 0)
+
+	for name := range openingPositions {
+		if _, exists := known[name]; !exists {
+			positionOnly = append(positionOnly, name)
+		}
+	}
+
+	sort.Strings(positionOnly)
+
+	for _, name := range positionOnly {
+		accounts = append(accounts, models.NewAccount(name))
+	}
+
+	if len(accounts) == 0 {
+		accounts = append(accounts, models.NewAccount(""))
+	}
+
+	return accounts
+}
+
+func openingHoldingsOf(openingPositions map[string]models.Holdings, account string) models.Holdings {
+	if holdings, exists := openingPositions[account]; exists {
+		return holdings
+	}
+
+	return models.NewHoldings(models.NewPosition())
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"capital-gains/src/application/commands"
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/application/ports/outbound"
+)
+
+type AmendOperationHandler struct {
+	corrections outbound.Corrections
+}
+
+func NewAmendOperationHandler(corrections outbound.Corrections) *AmendOperationHandler {
+	return &AmendOperationHandler{
+		corrections: corrections,
+	}
+}
+
+func (handler *AmendOperationHandler) Handle(command commands.AmendOperation) {
+	replacement := newOperation(command.Replacement())
+
+	amendment := models.NewAmendment(command.ID(), replacement)
+
+	handler.corrections.Save(amendment)
+}
@@ -8,24 +8,45 @@ import (
 
 type CalculateCapitalGainHandler struct {
 	operations   outbound.Operations
+	positions    outbound.Positions
 	capitalGains outbound.CapitalGains
 }
 
 func NewCalculateCapitalGainHandler(
 	operations outbound.Operations,
+	positions outbound.Positions,
 	capitalGains outbound.CapitalGains,
 ) *CalculateCapitalGainHandler {
 	return &CalculateCapitalGainHandler{
 		operations:   operations,
+		positions:    positions,
 		capitalGains: capitalGains,
 	}
 }
 
-func (handler *CalculateCapitalGainHandler) Handle(_ commands.CalculateCapitalGain) {
+func (handler *CalculateCapitalGainHandler) Handle(command commands.CalculateCapitalGain) {
 	operations := handler.operations.FindAll()
+	openingPositions := handler.positions.FindAll()
 
-	capitalGain := models.NewCapitalGain()
-	capitalGain.ApplyOperations(operations)
+	for _, account := range accountsOf(operations, openingPositions) {
+		capitalGain := models.NewCapitalGainHolding(account.Name(), openingHoldingsOf(openingPositions, account.Name()))
+		applyOperations(&capitalGain, account.Operations(), command.IsChronological())
 
-	handler.capitalGains.Save(capitalGain)
+		if command.IsIncremental() {
+			handler.positions.Save(account.Name(), capitalGain.Holdings())
+		}
+
+		handler.capitalGains.Save(capitalGain)
+	}
+}
+
+func applyOperations(capitalGain *models.CapitalGain, operations []models.Operation, chronological bool) {
+	if !chronological {
+		capitalGain.ApplyOperations(operations)
+		return
+	}
+
+	if err := capitalGain.ApplyOperationsChronologically(operations); err != nil {
+		panic(err)
+	}
 }
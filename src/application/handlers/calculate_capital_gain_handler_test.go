@@ -9,6 +9,7 @@ import (
 	"capital-gains/src/application/handlers"
 	"capital-gains/src/driven/capitalgains"
 	"capital-gains/src/driven/operations"
+	"capital-gains/src/driven/positions"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -34,8 +35,15 @@ func TestCalculateCapitalGainHandlerGivenRegisteredOperationsWhenHandleThenCapit
 	// And I have a configured capital gain repository
 	capitalGainRepository := capitalgains.NewRepository()
 
+	// And I have a configured positions repository
+	positionsRepository := positions.NewRepository()
+
 	// And I have a handler to calculate the capital gain using the registered operations
-	calculateHandler := handlers.NewCalculateCapitalGainHandler(operationsRepository, capitalGainRepository)
+	calculateHandler := handlers.NewCalculateCapitalGainHandler(
+		operationsRepository,
+		positionsRepository,
+		capitalGainRepository,
+	)
 
 	// And I have a command to calculate the capital gain
 	calculateCommand := commands.NewCalculateCapitalGain()
@@ -61,3 +69,41 @@ func TestCalculateCapitalGainHandlerGivenRegisteredOperationsWhenHandleThenCapit
 
 	assert.Equal(t, expectedTaxAmounts, taxAmounts)
 }
+
+func TestCalculateCapitalGainHandlerGivenIncrementalCommandsWhenHandleThenPositionIsCarriedOverBetweenCalculations(t *testing.T) {
+	t.Parallel()
+
+	// Given that I have configured operations, positions and capital gain repositories
+	operationsRepository := operations.NewRepository()
+	positionsRepository := positions.NewRepository()
+	capitalGainRepository := capitalgains.NewRepository()
+
+	// And I have the handlers to register operations and calculate the capital gain
+	registerBuyHandler := handlers.NewRegisterBuyHandler(operationsRepository)
+	registerSellHandler := handlers.NewRegisterSellHandler(operationsRepository)
+	calculateHandler := handlers.NewCalculateCapitalGainHandler(
+		operationsRepository,
+		positionsRepository,
+		capitalGainRepository,
+	)
+
+	// When I register and incrementally calculate a buy of 10000 units at 10.00
+	registerBuyHandler.Handle(commands.NewRegisterBuy(10000, 10.00))
+	calculateHandler.Handle(commands.NewCalculateCapitalGain().Incrementally())
+	firstCapitalGains := capitalGainRepository.FindAll()
+
+	// And I register and incrementally calculate a sell of 5000 units at 20.00 on its own
+	registerSellHandler.Handle(commands.NewRegisterSell(5000, 20.00))
+	calculateHandler.Handle(commands.NewCalculateCapitalGain().Incrementally())
+	secondCapitalGains := capitalGainRepository.FindAll()
+
+	// Then I expect each calculation to hold only the event of its own operation
+	assert.Equal(t, []float64{0.00}, test.TaxAmountsFromEvents(firstCapitalGains[0].Events()))
+	assert.Equal(t, []float64{10000.00}, test.TaxAmountsFromEvents(secondCapitalGains[0].Events()))
+
+	// And I expect the sell to have been calculated from the position left by the buy
+	assert.Equal(t, 5000, secondCapitalGains[0].Holdings().PositionOf("").Quantity().ToInt())
+
+	// And I expect the position to still be carried over for the next calculation
+	assert.Len(t, positionsRepository.FindAll(), 1)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+
+	"capital-gains/src/application/commands"
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/application/ports/outbound"
+)
+
+type CalculateTaxDiffHandler struct {
+	operations  outbound.Operations
+	positions   outbound.Positions
+	corrections outbound.Corrections
+	taxDiffs    outbound.TaxDiffs
+}
+
+func NewCalculateTaxDiffHandler(
+	operations outbound.Operations,
+	positions outbound.Positions,
+	corrections outbound.Corrections,
+	taxDiffs outbound.TaxDiffs,
+) *CalculateTaxDiffHandler {
+	return &CalculateTaxDiffHandler{
+		operations:  operations,
+		positions:   positions,
+		corrections: corrections,
+		taxDiffs:    taxDiffs,
+	}
+}
+
+func (handler *CalculateTaxDiffHandler) Handle(_ commands.CalculateTaxDiff) error {
+	openingPositions := handler.positions.FindAll()
+	accounts := accountsOf(handler.operations.FindAll(), openingPositions)
+	portfolios := make([]models.Portfolio, len(accounts))
+
+	for index, account := range accounts {
+		portfolios[index] = models.NewPortfolio(account.Operations())
+	}
+
+	for _, correction := range handler.corrections.FindAll() {
+		if err := applyCorrection(correction, portfolios); err != nil {
+			return err
+		}
+	}
+
+	for index, account := range accounts {
+		openingHoldings := openingHoldingsOf(openingPositions, account.Name())
+		handler.taxDiffs.Save(models.NewTaxDiffFor(account.Name(), openingHoldings, portfolios[index]))
+	}
+
+	return nil
+}
+
+// applyCorrection applies the correction to the portfolio of the account holding the corrected operation.
+func applyCorrection(correction models.Correction, portfolios []models.Portfolio) error {
+	var err error
+
+	for index := range portfolios {
+		if err = correction.ApplyTo(&portfolios[index]); !errors.Is(err, models.ErrOperationNotFound) {
+			return err
+		}
+	}
+
+	return err
+}
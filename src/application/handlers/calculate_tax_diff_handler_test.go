@@ -0,0 +1,80 @@
+package handlers_test
+
+import (
+	"testing"
+
+	"capital-gains/src/application/commands"
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/application/handlers"
+	"capital-gains/src/driven/corrections"
+	"capital-gains/src/driven/operations"
+	"capital-gains/src/driven/positions"
+	"capital-gains/src/driven/taxdiffs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateTaxDiffHandlerGivenRegisteredCancellationWhenHandleThenTaxDiffIsPersisted(t *testing.T) {
+	t.Parallel()
+
+	// Given that I have configured operations and corrections repositories
+	operationsRepository := operations.NewRepository()
+	correctionsRepository := corrections.NewRepository()
+
+	// And I register a buy of 10000 units at 10.00 and an identified sell of 5000 units at 20.00
+	handlers.NewRegisterBuyHandler(operationsRepository).Handle(commands.NewRegisterBuy(10000, 10.00))
+	handlers.NewRegisterSellHandler(operationsRepository).Handle(
+		commands.NewRegisterSell(5000, 20.00).WithMetadata(commands.NewMetadata().WithID("sell-1")),
+	)
+
+	// And I register the cancellation of the sell
+	handlers.NewCancelOperationHandler(correctionsRepository).Handle(commands.NewCancelOperation("sell-1"))
+
+	// And I have a handler to calculate the tax diff
+	taxDiffsRepository := taxdiffs.NewRepository()
+	handler := handlers.NewCalculateTaxDiffHandler(
+		operationsRepository,
+		positions.NewRepository(),
+		correctionsRepository,
+		taxDiffsRepository,
+	)
+
+	// When I handle the calculate tax diff command
+	err := handler.Handle(commands.NewCalculateTaxDiff())
+
+	// Then I expect no error
+	assert.NoError(t, err)
+
+	// And I expect one tax diff to be stored with the cancelled sell refunding its tax
+	taxDiffs := taxDiffsRepository.FindAll()
+	assert.Len(t, taxDiffs, 1)
+
+	operationDiffs := taxDiffs[0].Operations()
+	assert.Len(t, operationDiffs, 1)
+	assert.Equal(t, models.Cancelled, operationDiffs[0].Status())
+	assert.Equal(t, models.NewMonetaryValue(-10000.00), operationDiffs[0].Difference())
+}
+
+func TestCalculateTaxDiffHandlerGivenCorrectionOfUnknownOperationWhenHandleThenReturnsError(t *testing.T) {
+	t.Parallel()
+
+	// Given that I register the amendment of an operation that was never registered
+	correctionsRepository := corrections.NewRepository()
+	handlers.NewAmendOperationHandler(correctionsRepository).Handle(
+		commands.NewAmendOperation("unknown", commands.NewRegisterBuy(100, 10.00)),
+	)
+
+	// And I have a handler to calculate the tax diff
+	handler := handlers.NewCalculateTaxDiffHandler(
+		operations.NewRepository(),
+		positions.NewRepository(),
+		correctionsRepository,
+		taxdiffs.NewRepository(),
+	)
+
+	// When I handle the calculate tax diff command
+	err := handler.Handle(commands.NewCalculateTaxDiff())
+
+	// Then I expect the operation not to be found
+	assert.ErrorIs(t, err, models.ErrOperationNotFound)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"capital-gains/src/application/commands"
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/application/ports/outbound"
+)
+
+type CancelOperationHandler struct {
+	corrections outbound.Corrections
+}
+
+func NewCancelOperationHandler(corrections outbound.Corrections) *CancelOperationHandler {
+	return &CancelOperationHandler{
+		corrections: corrections,
+	}
+}
+
+func (handler *CancelOperationHandler) Handle(command commands.CancelOperation) {
+	cancellation := models.NewCancellation(command.ID())
+
+	handler.corrections.Save(cancellation)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"capital-gains/src/application/commands"
+	"capital-gains/src/application/domain/models"
+)
+
+func newBuy(command commands.RegisterBuy) models.Buy {
+	quantity := models.NewQuantity(command.Quantity())
+	unitCost := models.NewMonetaryValue(command.UnitCost())
+	fees := models.NewMonetaryValue(command.Fees())
+
+	return models.NewBuy(quantity, unitCost).WithFees(fees).WithMetadata(newMetadata(command.Metadata()))
+}
+
+func newSell(command commands.RegisterSell) models.Sell {
+	quantity := models.NewQuantity(command.Quantity())
+	unitCost := models.NewMonetaryValue(command.UnitCost())
+	fees := models.NewMonetaryValue(command.Fees())
+
+	return models.NewSell(quantity, unitCost).WithFees(fees).WithMetadata(newMetadata(command.Metadata()))
+}
+
+func newOperation(command commands.Command) models.Operation {
+	switch typedCommand := command.(type) {
+	case commands.RegisterBuy:
+		return newBuy(typedCommand)
+	case commands.RegisterSell:
+		return newSell(typedCommand)
+	default:
+		panic("unsupported operation")
+	}
+}
+
+func newMetadata(metadata commands.Metadata) models.Metadata {
+	converted := models.NewMetadata().
+		WithID(metadata.ID()).
+		WithSequence(metadata.Sequence()).
+		WithAccount(metadata.Account()).
+		WithTicker(metadata.Ticker())
+
+	if metadata.TradedAt().IsZero() {
+		return converted
+	}
+
+	if metadata.IsTimed() {
+		return converted.WithTradedAt(metadata.TradedAt())
+	}
+
+	return converted.WithTradedOn(metadata.TradedAt())
+}
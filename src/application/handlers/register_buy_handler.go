@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"capital-gains/src/application/commands"
-	"capital-gains/src/application/domain/models"
 	"capital-gains/src/application/ports/outbound"
 )
 
@@ -17,10 +16,5 @@ func NewRegisterBuyHandler(operations outbound.Operations) *RegisterBuyHandler {
 }
 
 func (handler *RegisterBuyHandler) Handle(command commands.RegisterBuy) {
-	quantity := models.NewQuantity(command.Quantity())
-	unitCost := models.NewMonetaryValue(command.UnitCost())
-
-	buy := models.NewBuy(quantity, unitCost)
-
-	handler.operations.Save(buy)
+	handler.operations.Save(newBuy(command))
 }
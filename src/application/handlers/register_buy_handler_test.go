@@ -27,3 +27,24 @@ func TestRegisterBuyHandlerGivenValidCommandWhenHandleThenBuyOperationIsPersiste
 
 	assert.Len(t, actual, 1)
 }
+
+func TestRegisterBuyHandlerGivenCommandsWithSameIDWhenHandleThenBuyOperationIsPersistedOnce(t *testing.T) {
+	t.Parallel()
+
+	// Given that I have two commands to register the same identified buy operation
+	command := commands.NewRegisterBuy(100, 10.00).WithMetadata(commands.NewMetadata().WithID("trade-1"))
+
+	// And I have a configured operations repository
+	repository := operations.NewRepository()
+
+	// When I handle both commands with the buy handler
+	handler := handlers.NewRegisterBuyHandler(repository)
+	handler.Handle(command)
+	handler.Handle(command)
+
+	// Then I expect the operation to be saved only once in the repository
+	actual := repository.FindAll()
+
+	assert.Len(t, actual, 1)
+	assert.Equal(t, "trade-1", actual[0].Metadata().ID())
+}
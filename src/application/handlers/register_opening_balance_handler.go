@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"capital-gains/src/application/commands"
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/application/ports/outbound"
+)
+
+type RegisterOpeningBalanceHandler struct {
+	positions outbound.Positions
+}
+
+func NewRegisterOpeningBalanceHandler(positions outbound.Positions) *RegisterOpeningBalanceHandler {
+	return &RegisterOpeningBalanceHandler{
+		positions: positions,
+	}
+}
+
+func (handler *RegisterOpeningBalanceHandler) Handle(command commands.RegisterOpeningBalance) {
+	quantity := models.NewQuantity(command.Quantity())
+	averageUnitCost := models.NewMonetaryValue(command.AverageUnitCost())
+	accumulatedLoss := models.NewMonetaryValue(command.AccumulatedLoss())
+
+	position := models.NewOpeningPosition(quantity, averageUnitCost, accumulatedLoss)
+
+	handler.positions.Save(command.Account(), models.NewHoldings(position))
+}
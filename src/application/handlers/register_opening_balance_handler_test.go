@@ -0,0 +1,58 @@
+package handlers_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driven/positions"
+
+	"capital-gains/src/application/commands"
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/application/handlers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterOpeningBalanceHandlerGivenValidCommandWhenHandleThenOpeningPositionIsPersisted(t *testing.T) {
+	t.Parallel()
+
+	// Given that I have a command to register an opening balance
+	command := commands.NewRegisterOpeningBalance(100, 10.00, 500.00)
+
+	// And I have a configured positions repository
+	repository := positions.NewRepository()
+
+	// When I handle the command with the opening balance handler
+	handler := handlers.NewRegisterOpeningBalanceHandler(repository)
+	handler.Handle(command)
+
+	// Then I expect the opening position to be saved in the repository
+	actual := repository.FindAll()[""].PositionOf("")
+
+	assert.Equal(t, models.NewQuantity(100), actual.Quantity())
+	assert.Equal(t, models.NewMonetaryValue(10.00), actual.AverageUnitCost())
+	assert.Equal(t, models.NewMonetaryValue(500.00), actual.AccumulatedLoss())
+
+	// And I expect the repository to be cleared after reading it
+	assert.Empty(t, repository.FindAll())
+}
+
+func TestRegisterOpeningBalanceHandlerGivenCommandForAccountWhenHandleThenOpeningPositionIsPersistedForAccount(t *testing.T) {
+	t.Parallel()
+
+	// Given that I have a command to register an opening balance for an account
+	command := commands.NewRegisterOpeningBalance(50, 20.00, 0.00).ForAccount("alice")
+
+	// And I have a configured positions repository
+	repository := positions.NewRepository()
+
+	// When I handle the command with the opening balance handler
+	handler := handlers.NewRegisterOpeningBalanceHandler(repository)
+	handler.Handle(command)
+
+	// Then I expect the opening position to be saved for that account only
+	actual := repository.FindAll()
+
+	assert.Len(t, actual, 1)
+	assert.Equal(t, models.NewQuantity(50), actual["alice"].PositionOf("").Quantity())
+	assert.Equal(t, models.NewMonetaryValue(20.00), actual["alice"].PositionOf("").AverageUnitCost())
+}
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"capital-gains/src/application/commands"
-	"capital-gains/src/application/domain/models"
 	"capital-gains/src/application/ports/outbound"
 )
 
@@ -17,10 +16,5 @@ func NewRegisterSellHandler(operations outbound.Operations) *RegisterSellHandler
 }
 
 func (handler *RegisterSellHandler) Handle(command commands.RegisterSell) {
-	quantity := models.NewQuantity(command.Quantity())
-	unitCost := models.NewMonetaryValue(command.UnitCost())
-
-	sell := models.NewSell(quantity, unitCost)
-
-	handler.operations.Save(sell)
+	handler.operations.Save(newSell(command))
 }
@@ -0,0 +1,13 @@
+package inbound
+
+import "capital-gains/src/application/commands"
+
+// AmendOperation defines the input boundary responsible for registering the
+// retroactive correction of a previously registered operation.
+type AmendOperation interface {
+	// Handle registers the amendment, so the next tax diff calculation replaces
+	// the identified operation with the corrected one.
+	//
+	// [param]  command commands.AmendOperation   amendment command to be handled.
+	Handle(command commands.AmendOperation)
+}
@@ -0,0 +1,15 @@
+package inbound
+
+import "capital-gains/src/application/commands"
+
+// CalculateTaxDiff defines the input boundary responsible for recomputing the
+// taxes after the registered corrections and comparing them with the original ones.
+type CalculateTaxDiff interface {
+	// Handle recomputes the registered operations with and without the registered
+	// corrections, producing the tax diff for the current input lifecycle.
+	//
+	// [param]  command commands.CalculateTaxDiff   use case command to be handled.
+	// [return] error                               when a correction cannot be applied, such as
+	//                                              one of an operation no longer held.
+	Handle(command commands.CalculateTaxDiff) error
+}
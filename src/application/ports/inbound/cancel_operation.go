@@ -0,0 +1,13 @@
+package inbound
+
+import "capital-gains/src/application/commands"
+
+// CancelOperation defines the input boundary responsible for registering the
+// retroactive cancellation of a previously registered operation.
+type CancelOperation interface {
+	// Handle registers the cancellation, so the next tax diff calculation leaves
+	// the identified operation out.
+	//
+	// [param]  command commands.CancelOperation   cancellation command to be handled.
+	Handle(command commands.CancelOperation)
+}
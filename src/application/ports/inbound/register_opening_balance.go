@@ -0,0 +1,13 @@
+package inbound
+
+import "capital-gains/src/application/commands"
+
+// RegisterOpeningBalance defines the input boundary responsible for seeding
+// the portfolio state with a balance carried over from a previous history.
+type RegisterOpeningBalance interface {
+	// Handle registers the opening balance, so the next capital gain calculation
+	// starts from the provided position instead of an empty one.
+	//
+	// [param]  command commands.RegisterOpeningBalance   opening balance command to be handled.
+	Handle(command commands.RegisterOpeningBalance)
+}
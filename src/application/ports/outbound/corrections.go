@@ -0,0 +1,18 @@
+package outbound
+
+import "capital-gains/src/application/domain/models"
+
+// Corrections represents the output boundary for storing the retroactive
+// corrections registered in a single calculation lifecycle.
+type Corrections interface {
+	// Save persists a new correction in the current calculation context.
+	//
+	// [param]  correction models.Correction      instance to be stored.
+	Save(correction models.Correction)
+
+	// FindAll returns all stored corrections in registration order and clears
+	// the storage, so a subsequent call returns an empty list.
+	//
+	// [return] []models.Correction            list of stored corrections.
+	FindAll() []models.Correction
+}
@@ -6,12 +6,15 @@ import "capital-gains/src/application/domain/models"
 // that will be used in a single capital gain calculation lifecycle.
 type Operations interface {
 	// Save persists a new market operation in the current calculation context.
+	// An operation whose id was already saved in the same context is ignored,
+	// even once read by FindAll, so ingesting the same operations twice does not
+	// duplicate them, whether at once or in an incremental calculation.
 	//
 	// [param]  operation models.Operation      instance to be stored.
 	Save(operation models.Operation)
 
 	// FindAll returns all stored Operation aggregates and clears the storage,
-	// so a subsequent call returns an empty list.
+	// so a subsequent call returns an empty list. The ids already saved are kept.
 	//
 	// [return] []models.Operation            list of stored aggregates.
 	FindAll() []models.Operation
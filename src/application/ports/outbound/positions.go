@@ -0,0 +1,19 @@
+package outbound
+
+import "capital-gains/src/application/domain/models"
+
+// Positions represents the output boundary for storing the positions the accounts
+// of a capital gain calculation lifecycle start from.
+type Positions interface {
+	// Save persists the holdings the next calculation of an account must start from.
+	//
+	// [param]  account  string            account the holdings belong to.
+	// [param]  holdings models.Holdings   instance to be stored.
+	Save(account string, holdings models.Holdings)
+
+	// FindAll returns the stored holdings keyed by account and clears the storage,
+	// so a subsequent call returns an empty map.
+	//
+	// [return] map[string]models.Holdings   stored holdings keyed by account.
+	FindAll() map[string]models.Holdings
+}
@@ -0,0 +1,18 @@
+package outbound
+
+import "capital-gains/src/application/domain/models"
+
+// TaxDiffs defines the output boundary responsible for storing and retrieving
+// the tax diffs produced when corrections are recomputed.
+type TaxDiffs interface {
+	// Save persists a tax diff for the current calculation lifecycle.
+	//
+	// [param]  taxDiff models.TaxDiff   tax diff to be stored.
+	Save(taxDiff models.TaxDiff)
+
+	// FindAll returns all stored tax diffs and clears the storage,
+	// so a subsequent call returns an empty list.
+	//
+	// [return] []models.TaxDiff            list of stored tax diffs.
+	FindAll() []models.TaxDiff
+}
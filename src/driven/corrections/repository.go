@@ -0,0 +1,31 @@
+package corrections
+
+import (
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/application/ports/outbound"
+)
+
+var _ outbound.Corrections = (*Repository)(nil)
+
+type Repository struct {
+	corrections []models.Correction
+}
+
+func NewRepository() *Repository {
+	return &Repository{
+		corrections: make([]models.Correction, 0),
+	}
+}
+
+func (repository *Repository) Save(correction models.Correction) {
+	repository.corrections = append(repository.corrections, correction)
+}
+
+func (repository *Repository) FindAll() []models.Correction {
+	corrections := make([]models.Correction, len(repository.corrections))
+	copy(corrections, repository.corrections)
+
+	repository.corrections = make([]models.Correction, 0)
+
+	return corrections
+}
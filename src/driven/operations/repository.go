@@ -8,16 +8,28 @@ import (
 var _ outbound.Operations = (*Repository)(nil)
 
 type Repository struct {
-	operations []models.Operation
+	operations  []models.Operation
+	identifiers map[string]struct{}
 }
 
 func NewRepository() *Repository {
 	return &Repository{
-		operations: make([]models.Operation, 0),
+		operations:  make([]models.Operation, 0),
+		identifiers: make(map[string]struct{}),
 	}
 }
 
 func (repository *Repository) Save(operation models.Operation) {
+	metadata := operation.Metadata()
+
+	if metadata.HasID() {
+		if _, alreadySaved := repository.identifiers[metadata.ID()]; alreadySaved {
+			return
+		}
+
+		repository.identifiers[metadata.ID()] = struct{}{}
+	}
+
 	repository.operations = append(repository.operations, operation)
 }
 
@@ -0,0 +1,30 @@
+package positions
+
+import (
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/application/ports/outbound"
+)
+
+var _ outbound.Positions = (*Repository)(nil)
+
+type Repository struct {
+	positions map[string]models.Holdings
+}
+
+func NewRepository() *Repository {
+	return &Repository{
+		positions: make(map[string]models.Holdings),
+	}
+}
+
+func (repository *Repository) Save(account string, holdings models.Holdings) {
+	repository.positions[account] = holdings
+}
+
+func (repository *Repository) FindAll() map[string]models.Holdings {
+	positions := repository.positions
+
+	repository.positions = make(map[string]models.Holdings)
+
+	return positions
+}
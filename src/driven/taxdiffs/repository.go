@@ -0,0 +1,31 @@
+package taxdiffs
+
+import (
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/application/ports/outbound"
+)
+
+var _ outbound.TaxDiffs = (*Repository)(nil)
+
+type Repository struct {
+	taxDiffs []models.TaxDiff
+}
+
+func NewRepository() *Repository {
+	return &Repository{
+		taxDiffs: make([]models.TaxDiff, 0),
+	}
+}
+
+func (repository *Repository) Save(taxDiff models.TaxDiff) {
+	repository.taxDiffs = append(repository.taxDiffs, taxDiff)
+}
+
+func (repository *Repository) FindAll() []models.TaxDiff {
+	taxDiffs := make([]models.TaxDiff, len(repository.taxDiffs))
+	copy(taxDiffs, repository.taxDiffs)
+
+	repository.taxDiffs = make([]models.TaxDiff, 0)
+
+	return taxDiffs
+}
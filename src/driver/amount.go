@@ -0,0 +1,10 @@
+package driver
+
+import "fmt"
+
+// Amount is a monetary value serialized with exactly two decimal places, as in the tax output.
+type Amount float64
+
+func (amount Amount) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%.2f", float64(amount))), nil
+}
@@ -0,0 +1,28 @@
+//go:build js && wasm
+
+package browser
+
+import "syscall/js"
+
+// Expose makes the calculator callable from JavaScript as capitalGains.calculate(json) and
+// capitalGains.explain(json), each taking the JSON value of an input line as a string and returning
+// the output as a JSON string.
+func Expose(calculator *Calculator) {
+	js.Global().Set("capitalGains", js.ValueOf(map[string]any{
+		"calculate": js.FuncOf(bind(calculator.Calculate)),
+		"explain":   js.FuncOf(bind(calculator.Explain)),
+	}))
+}
+
+// bind adapts a calculation to a JavaScript function, reading anything but a string as no input.
+func bind(calculate func(payload string) string) func(js.Value, []js.Value) any {
+	return func(_ js.Value, arguments []js.Value) any {
+		payload := ""
+
+		if len(arguments) > 0 && arguments[0].Type() == js.TypeString {
+			payload = arguments[0].String()
+		}
+
+		return calculate(payload)
+	}
+}
@@ -0,0 +1,62 @@
+package browser
+
+import (
+	"capital-gains/src/application/commands"
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/commandbus"
+	"capital-gains/src/driver/parsers"
+)
+
+// Calculator calculates the simulations given by a web page, each one within a unit of work of its
+// own, and returns the same output the console writes for them: their taxes or their explanation,
+// their tax diff report when they have corrections, or their validation errors.
+type Calculator struct {
+	unitsOfWork commandbus.UnitOfWorkFactory
+	parser      *parsers.OperationsParser
+}
+
+func NewCalculator(unitsOfWork commandbus.UnitOfWorkFactory) *Calculator {
+	return &Calculator{
+		unitsOfWork: unitsOfWork,
+		parser:      parsers.NewOperationsParser(),
+	}
+}
+
+// Calculate returns the taxes of the simulation given as the JSON value of an input line.
+func (calculator *Calculator) Calculate(payload string) string {
+	return calculator.calculate(payload, func(capitalGains []models.CapitalGain) string {
+		return driver.NewResponse(capitalGains).ToString()
+	})
+}
+
+// Explain returns the step-by-step calculation of the simulation given as the JSON value of an input line.
+func (calculator *Calculator) Explain(payload string) string {
+	return calculator.calculate(payload, func(capitalGains []models.CapitalGain) string {
+		return driver.NewExplanation(capitalGains).ToString()
+	})
+}
+
+func (calculator *Calculator) calculate(payload string, render func([]models.CapitalGain) string) string {
+	simulation, ok := calculator.parser.Parse(payload)
+
+	if !ok {
+		return parsers.MalformedInput(payload, "expected a JSON array of operations or an object with operations").ToString()
+	}
+
+	if validationErrors := simulation.Validate(); len(validationErrors) > 0 {
+		return validationErrors.ToString()
+	}
+
+	calculation, err := calculator.unitsOfWork().Calculate(simulation, commands.NewCalculateCapitalGain())
+
+	if err != nil {
+		return driver.ValidationErrors{driver.NewValidationError(err)}.ToString()
+	}
+
+	if calculation.IsCorrected() {
+		return driver.NewTaxDiffReport(calculation.TaxDiffs()).ToString()
+	}
+
+	return render(calculation.CapitalGains())
+}
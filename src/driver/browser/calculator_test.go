@@ -0,0 +1,76 @@
+package browser_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driver/browser"
+	"capital-gains/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculatorGivenOperationsWhenCalculatedThenTaxesAreReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a buy and a taxed sell, as given by the page
+	payload := `[{"operation":"buy","unit-cost":10.00,"quantity":10000},{"operation":"sell","unit-cost":20.00,"quantity":5000}]`
+
+	// When I calculate them
+	output := browser.NewCalculator(test.NewUnitOfWork).Calculate(payload)
+
+	// Then I expect the tax array, as the console writes it
+	assert.Equal(t, `[{"tax":0.00},{"tax":10000.00}]`, output)
+}
+
+func TestCalculatorGivenOperationsWhenExplainedThenTheCalculationIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a single buy, as given by the page
+	payload := `{"operations":[{"operation":"buy","unit-cost":10.00,"quantity":100}]}`
+
+	// When I explain it
+	output := browser.NewCalculator(test.NewUnitOfWork).Explain(payload)
+
+	// Then I expect its step-by-step calculation
+	expected := `{"operations":[{"index":0,"operation":"buy","quantity":100,"unit-cost":10.00,"applied-at":0,` +
+		`"gain":0.00,"deducted-loss":0.00,"tax":0.00,"position":{"quantity":100,"average-unit-cost":10.00,` +
+		`"accumulated-loss":0.00}}],"reordered":[]}`
+	assert.Equal(t, expected, output)
+}
+
+func TestCalculatorGivenInvalidInputWhenCalculatedThenValidationErrorsAreReturned(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		payload  string
+		expected string
+	}{
+		{
+			name:     "not JSON",
+			payload:  `[{"operation":`,
+			expected: `{"errors":[{"code":"malformed-input","message":"malformed input: not valid JSON"}]}`,
+		},
+		{
+			name:    "unsupported operation",
+			payload: `[{"operation":"hold","unit-cost":10.00,"quantity":100}]`,
+			expected: `{"errors":[{"index":0,"field":"operation","code":"unsupported-operation",` +
+				`"message":"unsupported operation: \"hold\" is neither buy nor sell"}]}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given input that cannot be calculated
+			payload := testCase.payload
+
+			// When I calculate it
+			output := browser.NewCalculator(test.NewUnitOfWork).Calculate(payload)
+
+			// Then I expect its validation errors in place of the taxes
+			assert.Equal(t, testCase.expected, output)
+		})
+	}
+}
@@ -6,32 +6,55 @@ import (
 )
 
 type CommandBus struct {
-	registerBuy          inbound.RegisterBuy
-	registerSell         inbound.RegisterSell
-	calculateCapitalGain inbound.CalculateCapitalGain
+	registerBuy            inbound.RegisterBuy
+	registerSell           inbound.RegisterSell
+	registerOpeningBalance inbound.RegisterOpeningBalance
+	amendOperation         inbound.AmendOperation
+	cancelOperation        inbound.CancelOperation
+	calculateCapitalGain   inbound.CalculateCapitalGain
+	calculateTaxDiff       inbound.CalculateTaxDiff
 }
 
 func NewCommandBus(
 	registerBuy inbound.RegisterBuy,
 	registerSell inbound.RegisterSell,
+	registerOpeningBalance inbound.RegisterOpeningBalance,
+	amendOperation inbound.AmendOperation,
+	cancelOperation inbound.CancelOperation,
 	calculateCapitalGain inbound.CalculateCapitalGain,
+	calculateTaxDiff inbound.CalculateTaxDiff,
 ) *CommandBus {
 	return &CommandBus{
-		registerBuy:          registerBuy,
-		registerSell:         registerSell,
-		calculateCapitalGain: calculateCapitalGain,
+		registerBuy:            registerBuy,
+		registerSell:           registerSell,
+		registerOpeningBalance: registerOpeningBalance,
+		amendOperation:         amendOperation,
+		cancelOperation:        cancelOperation,
+		calculateCapitalGain:   calculateCapitalGain,
+		calculateTaxDiff:       calculateTaxDiff,
 	}
 }
 
-func (commandBus *CommandBus) Dispatch(command commands.Command) {
+// Dispatch hands the command to its handler, returning the error of a handler that can fail.
+func (commandBus *CommandBus) Dispatch(command commands.Command) error {
 	switch typedCommand := command.(type) {
 	case commands.RegisterBuy:
 		commandBus.registerBuy.Handle(typedCommand)
 	case commands.RegisterSell:
 		commandBus.registerSell.Handle(typedCommand)
+	case commands.RegisterOpeningBalance:
+		commandBus.registerOpeningBalance.Handle(typedCommand)
+	case commands.AmendOperation:
+		commandBus.amendOperation.Handle(typedCommand)
+	case commands.CancelOperation:
+		commandBus.cancelOperation.Handle(typedCommand)
 	case commands.CalculateCapitalGain:
 		commandBus.calculateCapitalGain.Handle(typedCommand)
+	case commands.CalculateTaxDiff:
+		return commandBus.calculateTaxDiff.Handle(typedCommand)
 	default:
 		panic("unsupported command")
 	}
+
+	return nil
 }
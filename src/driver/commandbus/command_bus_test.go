@@ -12,102 +12,200 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+type commandBusFixture struct {
+	registerBuyHandler            *test.RegisterBuyHandlerMock
+	registerSellHandler           *test.RegisterSellHandlerMock
+	registerOpeningBalanceHandler *test.RegisterOpeningBalanceHandlerMock
+	amendOperationHandler         *test.AmendOperationHandlerMock
+	cancelOperationHandler        *test.CancelOperationHandlerMock
+	calculateCapitalGainHandler   *test.CalculateCapitalGainHandlerMock
+	calculateTaxDiffHandler       *test.CalculateTaxDiffHandlerMock
+	commandBus                    *commandbus.CommandBus
+}
+
+func newCommandBusFixture() commandBusFixture {
+	fixture := commandBusFixture{
+		registerBuyHandler:            test.NewRegisterBuyHandlerMock(),
+		registerSellHandler:           test.NewRegisterSellHandlerMock(),
+		registerOpeningBalanceHandler: test.NewRegisterOpeningBalanceHandlerMock(),
+		amendOperationHandler:         test.NewAmendOperationHandlerMock(),
+		cancelOperationHandler:        test.NewCancelOperationHandlerMock(),
+		calculateCapitalGainHandler:   test.NewCalculateCapitalGainHandlerMock(),
+		calculateTaxDiffHandler:       test.NewCalculateTaxDiffHandlerMock(),
+	}
+
+	fixture.commandBus = commandbus.NewCommandBus(
+		fixture.registerBuyHandler,
+		fixture.registerSellHandler,
+		fixture.registerOpeningBalanceHandler,
+		fixture.amendOperationHandler,
+		fixture.cancelOperationHandler,
+		fixture.calculateCapitalGainHandler,
+		fixture.calculateTaxDiffHandler,
+	)
+
+	return fixture
+}
+
+// calls returns the number of calls received by each handler, keyed by the handled command.
+func (fixture commandBusFixture) calls() map[string]int {
+	return map[string]int{
+		"RegisterBuy":            fixture.registerBuyHandler.Calls(),
+		"RegisterSell":           fixture.registerSellHandler.Calls(),
+		"RegisterOpeningBalance": fixture.registerOpeningBalanceHandler.Calls(),
+		"AmendOperation":         fixture.amendOperationHandler.Calls(),
+		"CancelOperation":        fixture.cancelOperationHandler.Calls(),
+		"CalculateCapitalGain":   fixture.calculateCapitalGainHandler.Calls(),
+		"CalculateTaxDiff":       fixture.calculateTaxDiffHandler.Calls(),
+	}
+}
+
+// onlyCalled returns the expected calls when only the handler of the given command was invoked once.
+func onlyCalled(command string) map[string]int {
+	expected := newCommandBusFixture().calls()
+	expected[command] = 1
+
+	return expected
+}
+
 func TestCommandBusDispatchGivenRegisterBuyCommandWhenDispatchThenOnlyRegisterBuyHandlerIsInvoked(t *testing.T) {
 	t.Parallel()
 
 	// Given a command bus with all handlers
-	registerBuyHandler := test.NewRegisterBuyHandlerMock()
-	registerSellHandler := test.NewRegisterSellHandlerMock()
-	calculateCapitalGainHandler := test.NewCalculateCapitalGainHandlerMock()
-	commandBus := commandbus.NewCommandBus(
-		registerBuyHandler,
-		registerSellHandler,
-		calculateCapitalGainHandler,
-	)
+	fixture := newCommandBusFixture()
 
 	// And a register buy command
 	registerBuyCommand := commands.NewRegisterBuy(100, 10.00)
 
 	// When I dispatch the register buy command
-	commandBus.Dispatch(registerBuyCommand)
+	assert.NoError(t, fixture.commandBus.Dispatch(registerBuyCommand))
 
 	// Then I expect the register buy handler to be called once with the dispatched command
-	assert.Equal(t, 1, registerBuyHandler.Calls())
-	assert.Equal(t, registerBuyCommand, registerBuyHandler.FirstReceived())
+	assert.Equal(t, registerBuyCommand, fixture.registerBuyHandler.FirstReceived())
 
 	// And I expect the other handlers not to be called
-	assert.Equal(t, 0, registerSellHandler.Calls())
-	assert.Equal(t, 0, calculateCapitalGainHandler.Calls())
+	assert.Equal(t, onlyCalled("RegisterBuy"), fixture.calls())
 }
 
 func TestCommandBusDispatchGivenRegisterSellCommandWhenDispatchThenOnlyRegisterSellHandlerIsInvoked(t *testing.T) {
 	t.Parallel()
 
 	// Given a command bus with all handlers
-	registerBuyHandler := test.NewRegisterBuyHandlerMock()
-	registerSellHandler := test.NewRegisterSellHandlerMock()
-	calculateCapitalGainHandler := test.NewCalculateCapitalGainHandlerMock()
-	commandBus := commandbus.NewCommandBus(
-		registerBuyHandler,
-		registerSellHandler,
-		calculateCapitalGainHandler,
-	)
+	fixture := newCommandBusFixture()
 
 	// And a register sell command
 	registerSellCommand := commands.NewRegisterSell(100, 15.00)
 
 	// When I dispatch the register sell command
-	commandBus.Dispatch(registerSellCommand)
+	assert.NoError(t, fixture.commandBus.Dispatch(registerSellCommand))
 
 	// Then I expect the register sell handler to be called once with the dispatched command
-	assert.Equal(t, 1, registerSellHandler.Calls())
-	assert.Equal(t, registerSellCommand, registerSellHandler.FirstReceived())
+	assert.Equal(t, registerSellCommand, fixture.registerSellHandler.FirstReceived())
+
+	// And I expect the other handlers not to be called
+	assert.Equal(t, onlyCalled("RegisterSell"), fixture.calls())
+}
+
+func TestCommandBusDispatchGivenRegisterOpeningBalanceCommandWhenDispatchThenOnlyRegisterOpeningBalanceHandlerIsInvoked(t *testing.T) {
+	t.Parallel()
+
+	// Given a command bus with all handlers
+	fixture := newCommandBusFixture()
+
+	// And a register opening balance command
+	registerOpeningBalanceCommand := commands.NewRegisterOpeningBalance(100, 10.00, 500.00)
+
+	// When I dispatch the register opening balance command
+	assert.NoError(t, fixture.commandBus.Dispatch(registerOpeningBalanceCommand))
+
+	// Then I expect the register opening balance handler to be called once with the dispatched command
+	assert.Equal(t, registerOpeningBalanceCommand, fixture.registerOpeningBalanceHandler.FirstReceived())
 
 	// And I expect the other handlers not to be called
-	assert.Equal(t, 0, registerBuyHandler.Calls())
-	assert.Equal(t, 0, calculateCapitalGainHandler.Calls())
+	assert.Equal(t, onlyCalled("RegisterOpeningBalance"), fixture.calls())
+}
+
+func TestCommandBusDispatchGivenAmendOperationCommandWhenDispatchThenOnlyAmendOperationHandlerIsInvoked(t *testing.T) {
+	t.Parallel()
+
+	// Given a command bus with all handlers
+	fixture := newCommandBusFixture()
+
+	// And an amend operation command
+	amendOperationCommand := commands.NewAmendOperation("trade-1", commands.NewRegisterBuy(100, 10.00))
+
+	// When I dispatch the amend operation command
+	assert.NoError(t, fixture.commandBus.Dispatch(amendOperationCommand))
+
+	// Then I expect the amend operation handler to be called once with the dispatched command
+	assert.Equal(t, amendOperationCommand, fixture.amendOperationHandler.FirstReceived())
+
+	// And I expect the other handlers not to be called
+	assert.Equal(t, onlyCalled("AmendOperation"), fixture.calls())
+}
+
+func TestCommandBusDispatchGivenCancelOperationCommandWhenDispatchThenOnlyCancelOperationHandlerIsInvoked(t *testing.T) {
+	t.Parallel()
+
+	// Given a command bus with all handlers
+	fixture := newCommandBusFixture()
+
+	// And a cancel operation command
+	cancelOperationCommand := commands.NewCancelOperation("trade-1")
+
+	// When I dispatch the cancel operation command
+	assert.NoError(t, fixture.commandBus.Dispatch(cancelOperationCommand))
+
+	// Then I expect the cancel operation handler to be called once with the dispatched command
+	assert.Equal(t, cancelOperationCommand, fixture.cancelOperationHandler.FirstReceived())
+
+	// And I expect the other handlers not to be called
+	assert.Equal(t, onlyCalled("CancelOperation"), fixture.calls())
 }
 
 func TestCommandBusDispatchGivenCalculateCapitalGainCommandWhenDispatchThenOnlyCalculateCapitalGainHandlerIsInvoked(t *testing.T) {
 	t.Parallel()
 
 	// Given a command bus with all handlers
-	registerBuyHandler := test.NewRegisterBuyHandlerMock()
-	registerSellHandler := test.NewRegisterSellHandlerMock()
-	calculateCapitalGainHandler := test.NewCalculateCapitalGainHandlerMock()
-	commandBus := commandbus.NewCommandBus(
-		registerBuyHandler,
-		registerSellHandler,
-		calculateCapitalGainHandler,
-	)
+	fixture := newCommandBusFixture()
 
 	// And a calculate capital gain command
 	calculateCapitalGainCommand := commands.NewCalculateCapitalGain()
 
 	// When I dispatch the calculate capital gain command
-	commandBus.Dispatch(calculateCapitalGainCommand)
+	assert.NoError(t, fixture.commandBus.Dispatch(calculateCapitalGainCommand))
 
 	// Then I expect the calculate capital gain handler to be called once with the dispatched command
-	assert.Equal(t, 1, calculateCapitalGainHandler.Calls())
-	assert.Equal(t, calculateCapitalGainCommand, calculateCapitalGainHandler.FirstReceived())
+	assert.Equal(t, calculateCapitalGainCommand, fixture.calculateCapitalGainHandler.FirstReceived())
 
 	// And I expect the other handlers not to be called
-	assert.Equal(t, 0, registerBuyHandler.Calls())
-	assert.Equal(t, 0, registerSellHandler.Calls())
+	assert.Equal(t, onlyCalled("CalculateCapitalGain"), fixture.calls())
+}
+
+func TestCommandBusDispatchGivenCalculateTaxDiffCommandWhenDispatchThenOnlyCalculateTaxDiffHandlerIsInvoked(t *testing.T) {
+	t.Parallel()
+
+	// Given a command bus with all handlers
+	fixture := newCommandBusFixture()
+
+	// And a calculate tax diff command
+	calculateTaxDiffCommand := commands.NewCalculateTaxDiff()
+
+	// When I dispatch the calculate tax diff command
+	assert.NoError(t, fixture.commandBus.Dispatch(calculateTaxDiffCommand))
+
+	// Then I expect the calculate tax diff handler to be called once with the dispatched command
+	assert.Equal(t, calculateTaxDiffCommand, fixture.calculateTaxDiffHandler.FirstReceived())
+
+	// And I expect the other handlers not to be called
+	assert.Equal(t, onlyCalled("CalculateTaxDiff"), fixture.calls())
 }
 
 func TestCommandBusDispatchGivenUnsupportedCommandWhenDispatchThenPanics(t *testing.T) {
 	t.Parallel()
 
 	// Given a command bus with all handlers
-	registerBuyHandler := test.NewRegisterBuyHandlerMock()
-	registerSellHandler := test.NewRegisterSellHandlerMock()
-	calculateCapitalGainHandler := test.NewCalculateCapitalGainHandlerMock()
-	commandBus := commandbus.NewCommandBus(
-		registerBuyHandler,
-		registerSellHandler,
-		calculateCapitalGainHandler,
-	)
+	fixture := newCommandBusFixture()
 
 	// And an unsupported command
 	var unsupportedCommand commands.Command = nil
@@ -115,11 +213,9 @@ func TestCommandBusDispatchGivenUnsupportedCommandWhenDispatchThenPanics(t *test
 	// When I dispatch the unsupported command
 	// Then I expect the application to panic
 	assert.Panics(t, func() {
-		commandBus.Dispatch(unsupportedCommand)
+		_ = fixture.commandBus.Dispatch(unsupportedCommand)
 	})
 
 	// And I expect no handler to be invoked
-	assert.Equal(t, 0, registerBuyHandler.Calls())
-	assert.Equal(t, 0, registerSellHandler.Calls())
-	assert.Equal(t, 0, calculateCapitalGainHandler.Calls())
+	assert.Equal(t, newCommandBusFixture().calls(), fixture.calls())
 }
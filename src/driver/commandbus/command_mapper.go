@@ -13,13 +13,37 @@ func NewCommandMapper(request driver.Request) *CommandMapper {
 	return &CommandMapper{request: request}
 }
 
-func (mapper *CommandMapper) Map() []commands.Command {
+// Map returns the commands of the opening balances, operations and corrections of the request, in
+// that order, or the error of the first one that has no command.
+func (mapper *CommandMapper) Map() ([]commands.Command, error) {
 	operations := mapper.request.Operations()
-	commandsToHandle := make([]commands.Command, len(operations))
+	corrections := mapper.request.Corrections()
+	openingBalances := mapper.request.OpeningBalances()
+	commandsToHandle := make([]commands.Command, 0, len(openingBalances)+len(operations)+len(corrections))
 
-	for index, operation := range operations {
-		commandsToHandle[index] = operation.ToCommand()
+	for _, openingBalance := range openingBalances {
+		commandsToHandle = append(commandsToHandle, openingBalance.ToCommand())
 	}
 
-	return commandsToHandle
+	for _, operation := range operations {
+		command, err := operation.ToCommand()
+
+		if err != nil {
+			return nil, err
+		}
+
+		commandsToHandle = append(commandsToHandle, command)
+	}
+
+	for _, correction := range corrections {
+		command, err := correction.ToCommand()
+
+		if err != nil {
+			return nil, err
+		}
+
+		commandsToHandle = append(commandsToHandle, command)
+	}
+
+	return commandsToHandle, nil
 }
@@ -3,12 +3,13 @@ package commandbus_test
 import (
 	"testing"
 
+	"capital-gains/src/driver"
 	"capital-gains/src/driver/commandbus"
 
+	"capital-gains/src/driver/parsers"
 	"capital-gains/test"
 
 	"capital-gains/src/application/commands"
-	"capital-gains/src/driver/console"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -25,7 +26,7 @@ func TestCommandMapperMapGivenRequestWithOperationsWhenMapThenReturnsCommandsInS
 	payloadJSON := test.ToJson(payload)
 
 	// And I parse the payload into a request
-	parser := console.NewOperationsParser()
+	parser := parsers.NewOperationsParser()
 	request, ok := parser.Parse(payloadJSON)
 	assert.True(t, ok)
 
@@ -33,16 +34,18 @@ func TestCommandMapperMapGivenRequestWithOperationsWhenMapThenReturnsCommandsInS
 	mapper := commandbus.NewCommandMapper(request)
 
 	// When I map the request operations into commands
-	mappedCommands := mapper.Map()
+	mappedCommands, err := mapper.Map()
+	assert.NoError(t, err)
 
 	// Then I expect one command per operation
 	assert.Len(t, mappedCommands, 3)
 
 	// And I expect the mapped commands to match each operation command conversion and preserve the same order
-	operations := request.Operations()
-	assert.Equal(t, operations[0].ToCommand(), mappedCommands[0])
-	assert.Equal(t, operations[1].ToCommand(), mappedCommands[1])
-	assert.Equal(t, operations[2].ToCommand(), mappedCommands[2])
+	for index, operation := range request.Operations() {
+		command, err := operation.ToCommand()
+		assert.NoError(t, err)
+		assert.Equal(t, command, mappedCommands[index])
+	}
 
 	// And I expect the mapped command types to match the original operations (buy, sell, buy)
 	_, isFirstBuy := mappedCommands[0].(commands.RegisterBuy)
@@ -63,7 +66,7 @@ func TestCommandMapperMapGivenRequestWithNoOperationsWhenMapThenReturnsEmptyList
 	payloadJSON := test.ToJson(payload)
 
 	// And I parse the payload into a request
-	parser := console.NewOperationsParser()
+	parser := parsers.NewOperationsParser()
 	request, ok := parser.Parse(payloadJSON)
 	assert.True(t, ok)
 
@@ -71,9 +74,49 @@ func TestCommandMapperMapGivenRequestWithNoOperationsWhenMapThenReturnsEmptyList
 	mapper := commandbus.NewCommandMapper(request)
 
 	// When I map the request operations into commands
-	mappedCommands := mapper.Map()
+	mappedCommands, err := mapper.Map()
+	assert.NoError(t, err)
 
 	// Then I expect an empty command list
 	assert.NotNil(t, mappedCommands)
 	assert.Len(t, mappedCommands, 0)
 }
+
+func TestCommandMapperMapGivenCorrectionsThatCannotBeRegisteredWhenMapThenReturnsTheirError(t *testing.T) {
+	t.Parallel()
+
+	// Given requests with an amend lacking its replacement and with a correction of an unknown action
+	operations := []driver.Operation{{ID: "b1", Operation: "buy", UnitCost: 10.00, Quantity: 100}}
+	requests := map[error]driver.Request{
+		driver.ErrMissingField:          driver.NewRequest(operations).WithCorrections([]driver.Correction{{Action: "amend", ID: "b1"}}),
+		driver.ErrUnsupportedCorrection: driver.NewRequest(operations).WithCorrections([]driver.Correction{{Action: "undo", ID: "b1"}}),
+	}
+
+	for expected, request := range requests {
+		// When I map each request into commands
+		mappedCommands, err := commandbus.NewCommandMapper(request).Map()
+
+		// Then I expect the error of its correction instead of commands
+		assert.ErrorIs(t, err, expected)
+		assert.Nil(t, mappedCommands)
+	}
+}
+
+func TestCommandMapperMapGivenOperationsThatCannotBeRegisteredWhenMapThenReturnsTheirError(t *testing.T) {
+	t.Parallel()
+
+	// Given requests with an operation that is neither a buy nor a sell and with one traded on an invalid date
+	requests := map[error]driver.Request{
+		driver.ErrUnsupportedOperation: driver.NewRequest([]driver.Operation{{Operation: "hold", UnitCost: 10.00, Quantity: 100}}),
+		driver.ErrInvalidValue:         driver.NewRequest([]driver.Operation{{Operation: "buy", UnitCost: 10.00, Quantity: 100, Date: "2024-13-45"}}),
+	}
+
+	for expected, request := range requests {
+		// When I map each request into commands
+		mappedCommands, err := commandbus.NewCommandMapper(request).Map()
+
+		// Then I expect the validation error of its operation instead of a panic
+		assert.ErrorIs(t, err, expected)
+		assert.Nil(t, mappedCommands)
+	}
+}
@@ -0,0 +1,119 @@
+package commandbus
+
+import (
+	"fmt"
+
+	"capital-gains/src/application/commands"
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/application/ports/outbound"
+	"capital-gains/src/driver"
+)
+
+// UnitOfWork is the scope of a single calculation: a command bus over handlers and repositories of
+// its own, so calculations running at the same time never see each other's operations. A unit of
+// work is used by one calculation at a time and dropped along with its state once it ends.
+type UnitOfWork struct {
+	commandBus   *CommandBus
+	capitalGains outbound.CapitalGains
+	taxDiffs     outbound.TaxDiffs
+}
+
+// Calculation is the outcome of a request calculated within a unit of work: the tax diffs of its
+// corrections when it has any, its capital gains otherwise.
+type Calculation struct {
+	capitalGains []models.CapitalGain
+	taxDiffs     []models.TaxDiff
+	corrected    bool
+}
+
+// IsCorrected tells whether the request had corrections, its outcome being its tax diffs.
+func (calculation Calculation) IsCorrected() bool {
+	return calculation.corrected
+}
+
+func (calculation Calculation) CapitalGains() []models.CapitalGain {
+	return calculation.capitalGains
+}
+
+func (calculation Calculation) TaxDiffs() []models.TaxDiff {
+	return calculation.taxDiffs
+}
+
+// UnitOfWorkFactory begins the unit of work of a new calculation, sharing nothing with the previous ones.
+type UnitOfWorkFactory func() UnitOfWork
+
+func NewUnitOfWork(commandBus *CommandBus, capitalGains outbound.CapitalGains, taxDiffs outbound.TaxDiffs) UnitOfWork {
+	return UnitOfWork{
+		commandBus:   commandBus,
+		capitalGains: capitalGains,
+		taxDiffs:     taxDiffs,
+	}
+}
+
+// Dispatch handles the command within the unit of work, returning the error of a handler that can fail.
+func (unitOfWork UnitOfWork) Dispatch(command commands.Command) error {
+	return unitOfWork.commandBus.Dispatch(command)
+}
+
+// CapitalGains returns the capital gains calculated within the unit of work since the last call.
+func (unitOfWork UnitOfWork) CapitalGains() []models.CapitalGain {
+	return unitOfWork.capitalGains.FindAll()
+}
+
+// TaxDiffs returns the tax diffs calculated within the unit of work since the last call.
+func (unitOfWork UnitOfWork) TaxDiffs() []models.TaxDiff {
+	return unitOfWork.taxDiffs.FindAll()
+}
+
+// Calculate handles the commands of the request, then calculates the tax diffs of its corrections
+// when it has any, or its capital gains with the given command otherwise. A request that cannot be
+// calculated, despite being valid, returns an error wrapping driver.ErrCalculationFailed.
+func (unitOfWork UnitOfWork) Calculate(request driver.Request, command commands.CalculateCapitalGain) (Calculation, error) {
+	if err := unitOfWork.dispatchAll(request); err != nil {
+		return Calculation{}, err
+	}
+
+	if request.HasCorrections() {
+		if err := unitOfWork.Dispatch(commands.NewCalculateTaxDiff()); err != nil {
+			return Calculation{}, fmt.Errorf("%w: %w", driver.ErrCalculationFailed, err)
+		}
+
+		return Calculation{taxDiffs: unitOfWork.TaxDiffs(), corrected: true}, nil
+	}
+
+	if err := unitOfWork.Dispatch(command); err != nil {
+		return Calculation{}, fmt.Errorf("%w: %w", driver.ErrCalculationFailed, err)
+	}
+
+	return Calculation{capitalGains: unitOfWork.CapitalGains()}, nil
+}
+
+// Apply handles the commands of the request after the ones handled before it, returning the capital
+// gains of its operations calculated from the positions the previous ones left.
+func (unitOfWork UnitOfWork) Apply(request driver.Request) ([]models.CapitalGain, error) {
+	if err := unitOfWork.dispatchAll(request); err != nil {
+		return nil, err
+	}
+
+	if err := unitOfWork.Dispatch(commands.NewCalculateCapitalGain().Incrementally()); err != nil {
+		return nil, fmt.Errorf("%w: %w", driver.ErrCalculationFailed, err)
+	}
+
+	return unitOfWork.CapitalGains(), nil
+}
+
+func (unitOfWork UnitOfWork) dispatchAll(request driver.Request) error {
+	commandsToHandle, err := NewCommandMapper(request).Map()
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", driver.ErrCalculationFailed, err)
+	}
+
+	for _, command := range commandsToHandle {
+		if err := unitOfWork.Dispatch(command); err != nil {
+			return fmt.Errorf("%w: %w", driver.ErrCalculationFailed, err)
+		}
+	}
+
+	return nil
+}
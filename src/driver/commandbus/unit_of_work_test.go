@@ -0,0 +1,132 @@
+package commandbus_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"capital-gains/src/application/commands"
+	"capital-gains/src/driver"
+	"capital-gains/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWorkGivenConcurrentCalculationsWhenDispatchedThenEachOneSeesOnlyItsOwnOperations(t *testing.T) {
+	t.Parallel()
+
+	// Given many calculations selling at a price of their own
+	const calculations = 50
+
+	outputs := make([]string, calculations)
+
+	// When each one is dispatched within a unit of work of its own, at the same time
+	var waitGroup sync.WaitGroup
+
+	for index := range calculations {
+		waitGroup.Go(func() {
+			unitOfWork := test.NewUnitOfWork()
+			assert.NoError(t, unitOfWork.Dispatch(commands.NewRegisterBuy(10000, 10.00)))
+			assert.NoError(t, unitOfWork.Dispatch(commands.NewRegisterSell(5000, float64(20+index))))
+			assert.NoError(t, unitOfWork.Dispatch(commands.NewCalculateCapitalGain()))
+
+			outputs[index] = driver.NewResponse(unitOfWork.CapitalGains()).ToString()
+		})
+	}
+
+	waitGroup.Wait()
+
+	// Then I expect each one to be taxed on its own sell only
+	for index, output := range outputs {
+		assert.Equal(t, fmt.Sprintf(`[{"tax":0.00},{"tax":%d.00}]`, 1000*(10+index)), output)
+	}
+}
+
+func TestUnitOfWorkGivenCalculatedCapitalGainsWhenReadThenTheyAreNotReturnedAgain(t *testing.T) {
+	t.Parallel()
+
+	// Given a unit of work whose capital gains were calculated
+	unitOfWork := test.NewUnitOfWork()
+	assert.NoError(t, unitOfWork.Dispatch(commands.NewRegisterBuy(100, 10.00)))
+	assert.NoError(t, unitOfWork.Dispatch(commands.NewCalculateCapitalGain()))
+
+	// When I read them twice
+	first := unitOfWork.CapitalGains()
+	second := unitOfWork.CapitalGains()
+
+	// Then I expect the second read to find them released
+	assert.Len(t, first, 1)
+	assert.Empty(t, second)
+}
+
+func TestUnitOfWorkGivenRequestWhenCalculatedThenItsCapitalGainsAreTheOutcome(t *testing.T) {
+	t.Parallel()
+
+	// Given a request of a buy and a taxed sell
+	request := driver.NewRequest([]driver.Operation{
+		{Operation: "buy", UnitCost: 10.00, Quantity: 10000},
+		{Operation: "sell", UnitCost: 20.00, Quantity: 5000},
+	})
+
+	// When it is calculated within a unit of work
+	calculation, err := test.NewUnitOfWork().Calculate(request, commands.NewCalculateCapitalGain())
+	assert.NoError(t, err)
+
+	// Then I expect its capital gains, without tax diffs
+	assert.False(t, calculation.IsCorrected())
+	assert.Equal(t, `[{"tax":0.00},{"tax":10000.00}]`, driver.NewResponse(calculation.CapitalGains()).ToString())
+	assert.Empty(t, calculation.TaxDiffs())
+}
+
+func TestUnitOfWorkGivenRequestWithCorrectionsWhenCalculatedThenItsTaxDiffsAreTheOutcome(t *testing.T) {
+	t.Parallel()
+
+	// Given a request whose taxed sell is cancelled
+	request := driver.NewRequest([]driver.Operation{
+		{ID: "b1", Operation: "buy", UnitCost: 10.00, Quantity: 10000},
+		{ID: "s1", Operation: "sell", UnitCost: 20.00, Quantity: 5000},
+	}).WithCorrections([]driver.Correction{{Action: "cancel", ID: "s1"}})
+
+	// When it is calculated within a unit of work
+	calculation, err := test.NewUnitOfWork().Calculate(request, commands.NewCalculateCapitalGain())
+	assert.NoError(t, err)
+
+	// Then I expect the tax diffs of the correction rather than capital gains
+	assert.True(t, calculation.IsCorrected())
+	assert.NotEmpty(t, calculation.TaxDiffs())
+	assert.Empty(t, calculation.CapitalGains())
+}
+
+func TestUnitOfWorkGivenCorrectionOfOperationNoLongerHeldWhenCalculatedThenReturnsError(t *testing.T) {
+	t.Parallel()
+
+	// Given a request cancelling the same operation twice, which validation would have rejected
+	request := driver.NewRequest([]driver.Operation{
+		{ID: "b1", Operation: "buy", UnitCost: 10.00, Quantity: 10000},
+	}).WithCorrections([]driver.Correction{{Action: "cancel", ID: "b1"}, {Action: "cancel", ID: "b1"}})
+
+	// When it is calculated within a unit of work
+	_, err := test.NewUnitOfWork().Calculate(request, commands.NewCalculateCapitalGain())
+
+	// Then I expect the calculation to fail rather than panic
+	assert.ErrorIs(t, err, driver.ErrCalculationFailed)
+}
+
+func TestUnitOfWorkGivenAppliedBuyWhenSellIsAppliedThenItIsTaxedOnThePositionLeft(t *testing.T) {
+	t.Parallel()
+
+	// Given a unit of work a buy was applied to
+	unitOfWork := test.NewUnitOfWork()
+	bought, err := unitOfWork.Apply(driver.NewRequest([]driver.Operation{{Operation: "buy", UnitCost: 10.00, Quantity: 10000}}))
+	assert.NoError(t, err)
+
+	// When a sell is applied after it
+	sold, err := unitOfWork.Apply(driver.NewRequest([]driver.Operation{{Operation: "sell", UnitCost: 20.00, Quantity: 5000}}))
+
+	// Then I expect no error
+	assert.NoError(t, err)
+
+	// And I expect the sell alone to be returned, taxed on the buy applied before it
+	assert.Equal(t, `[{"tax":0.00}]`, driver.NewResponse(bought).ToString())
+	assert.Equal(t, `[{"tax":10000.00}]`, driver.NewResponse(sold).ToString())
+}
@@ -1,52 +1,327 @@
 package console
 
 import (
+	"errors"
+	"fmt"
+	"sync"
+
 	"capital-gains/src/application/commands"
-	"capital-gains/src/application/ports/inbound"
-	"capital-gains/src/application/ports/outbound"
 	"capital-gains/src/driver"
 	"capital-gains/src/driver/commandbus"
 )
 
+var (
+	// ErrRejectedInput is returned when part of the input was not calculated because of validation errors.
+	ErrRejectedInput = errors.New("rejected input")
+
+	// ErrSkippedInput is returned when entries of an imported file that are not operations, such as
+	// income, were left out of a calculation that otherwise went through.
+	ErrSkippedInput = errors.New("skipped input")
+)
+
+// calculation is a simulation handed to a worker, still to be parsed, along with where its output
+// is delivered.
+type calculation struct {
+	parse   func() (driver.Request, driver.ValidationErrors)
+	outcome chan<- outcome
+}
+
+// outcome is the output of a simulation, calculated apart from being written so that simulations
+// calculated at the same time are still written in the order of the input.
+type outcome struct {
+	write    func(operationsConsole *OperationsConsole)
+	rejected bool
+}
+
 type CalculateCapitalGain struct {
-	capitalGains      outbound.CapitalGains
-	commandBus        *commandbus.CommandBus
+	settings          Settings
+	unitsOfWork       commandbus.UnitOfWorkFactory
 	operationsConsole *OperationsConsole
 }
 
-func NewCalculateCapitalGain(
-	console Console,
-	registerBuy inbound.RegisterBuy,
-	registerSell inbound.RegisterSell,
-	capitalGains outbound.CapitalGains,
-	calculateCapitalGain inbound.CalculateCapitalGain,
-) *CalculateCapitalGain {
-	commandBus := commandbus.NewCommandBus(registerBuy, registerSell, calculateCapitalGain)
-	operationsConsole := NewOperationsConsole(console)
+// NewCalculateCapitalGain returns the use case calculating each simulation of the input within a
+// unit of work of its own, begun by the given factory.
+func NewCalculateCapitalGain(console Console, settings Settings, unitsOfWork commandbus.UnitOfWorkFactory) *CalculateCapitalGain {
+	operationsConsole := NewOperationsConsole(console, settings)
 
 	return &CalculateCapitalGain{
-		capitalGains:      capitalGains,
-		commandBus:        commandBus,
+		settings:          settings,
+		unitsOfWork:       unitsOfWork,
 		operationsConsole: operationsConsole,
 	}
 }
 
-func (calculateCapitalGain *CalculateCapitalGain) Handle() {
-	requests := calculateCapitalGain.operationsConsole.ReadRequests()
+// Handle calculates every simulation of the input, writing the validation errors of the ones that
+// cannot be calculated in place of their output. Once the whole input was read, it returns
+// ErrRejectedInput when any of them was rejected, joined with ErrSkippedInput when entries of an
+// imported file were skipped.
+func (calculateCapitalGain *CalculateCapitalGain) Handle() error {
+	var rejected int
+
+	switch {
+	case calculateCapitalGain.settings.Input == NDJSONInput:
+		rejected = calculateCapitalGain.handleStream()
+	case calculateCapitalGain.settings.Stream:
+		rejected = calculateCapitalGain.handleArrayStream()
+	default:
+		rejected = calculateCapitalGain.handleRequests()
+	}
+
+	calculateCapitalGain.operationsConsole.End()
+
+	var err error
+
+	if rejected > 0 {
+		err = fmt.Errorf("%w: %d of the simulations or operations had validation errors", ErrRejectedInput, rejected)
+	}
+
+	if skipped := calculateCapitalGain.operationsConsole.Skipped(); skipped != nil {
+		err = errors.Join(err, fmt.Errorf("%w: %w", ErrSkippedInput, skipped))
+	}
+
+	return err
+}
+
+// handleRequests calculates the simulations one at a time, unless several workers were given.
+func (calculateCapitalGain *CalculateCapitalGain) handleRequests() int {
+	if calculateCapitalGain.settings.Workers > 1 {
+		return calculateCapitalGain.handleRequestsConcurrently()
+	}
+
+	rejected := 0
+
+	for request, validationErrors := range calculateCapitalGain.operationsConsole.ReadRequests() {
+		rejected += calculateCapitalGain.write(calculateCapitalGain.outcomeOf(request, validationErrors))
+	}
+
+	return rejected
+}
+
+// handleRequestsConcurrently parses and calculates as many simulations at the same time as there are
+// workers, while the input is still being read, and writes their outputs in the order of the input.
+// At most twice as many outputs as workers wait to be written, so memory stays bounded however long
+// the input is.
+func (calculateCapitalGain *CalculateCapitalGain) handleRequestsConcurrently() int {
+	workers := calculateCapitalGain.settings.Workers
+	calculations := make(chan calculation)
+	pending := make(chan chan outcome, 2*workers)
+
+	var waitGroup sync.WaitGroup
+
+	for range workers {
+		waitGroup.Go(func() {
+			for calculation := range calculations {
+				calculation.outcome <- calculateCapitalGain.outcomeOf(calculation.parse())
+			}
+		})
+	}
+
+	go calculateCapitalGain.dispatchRequests(calculations, pending)
+
+	rejected := 0
+
+	for next := range pending {
+		rejected += calculateCapitalGain.write(<-next)
+	}
+
+	waitGroup.Wait()
+
+	return rejected
+}
+
+// dispatchRequests reads the simulations of the input, queueing where the output of each one will be
+// delivered, in the order of the input, before handing it to the workers.
+func (calculateCapitalGain *CalculateCapitalGain) dispatchRequests(calculations chan<- calculation, pending chan<- chan outcome) {
+	defer close(pending)
+	defer close(calculations)
 
-	for _, request := range requests {
-		commandFactory := commandbus.NewCommandMapper(request)
-		commandsToHandle := commandFactory.Map()
+	for parse := range calculateCapitalGain.operationsConsole.readUnparsedRequests() {
+		next := make(chan outcome, 1)
+		pending <- next
+		calculations <- calculation{parse: parse, outcome: next}
+	}
+}
+
+// write writes the output of a simulation, returning 1 when it was rejected and 0 otherwise.
+func (calculateCapitalGain *CalculateCapitalGain) write(outcome outcome) int {
+	outcome.write(calculateCapitalGain.operationsConsole)
 
-		for _, command := range commandsToHandle {
-			calculateCapitalGain.commandBus.Dispatch(command)
+	if outcome.rejected {
+		return 1
+	}
+
+	return 0
+}
+
+// outcomeOf calculates the simulation within a unit of work of its own, or rejects it with its
+// validation errors or the error keeping it from being calculated, without writing anything yet.
+func (calculateCapitalGain *CalculateCapitalGain) outcomeOf(request driver.Request, validationErrors driver.ValidationErrors) outcome {
+	if len(validationErrors) > 0 {
+		return outcome{
+			write: func(operationsConsole *OperationsConsole) {
+				operationsConsole.WriteValidationErrors(validationErrors)
+			},
+			rejected: true,
 		}
+	}
 
-		calculateCapitalGain.commandBus.Dispatch(commands.NewCalculateCapitalGain())
+	calculation, err := calculateCapitalGain.unitsOfWork().Calculate(request, calculateCapitalGain.calculateCommand())
 
-		taxes := calculateCapitalGain.capitalGains.FindAll()
-		response := driver.NewResponse(taxes)
+	if err != nil {
+		return calculateCapitalGain.outcomeOf(driver.Request{}, driver.ValidationErrors{driver.NewValidationError(err)})
+	}
+
+	return outcome{write: calculateCapitalGain.writerOf(calculation)}
+}
 
-		calculateCapitalGain.operationsConsole.WriteResponse(response)
+// writerOf returns how to write the output of the calculation: its tax diff report when it has
+// corrections, its explanation, its report or its taxes otherwise.
+func (calculateCapitalGain *CalculateCapitalGain) writerOf(calculation commandbus.Calculation) func(*OperationsConsole) {
+	if calculation.IsCorrected() {
+		report := driver.NewTaxDiffReport(calculation.TaxDiffs())
+
+		return func(operationsConsole *OperationsConsole) { operationsConsole.WriteTaxDiffReport(report) }
+	}
+
+	taxes := calculation.CapitalGains()
+
+	if calculateCapitalGain.settings.Explain {
+		explanation := driver.NewExplanation(taxes)
+
+		return func(operationsConsole *OperationsConsole) { operationsConsole.WriteExplanation(explanation) }
 	}
+
+	if calculateCapitalGain.settings.Report {
+		statement := driver.NewStatement(taxes)
+
+		return func(operationsConsole *OperationsConsole) { operationsConsole.WriteReport(statement) }
+	}
+
+	response := driver.NewResponse(taxes)
+
+	if calculateCapitalGain.settings.Summary {
+		response = response.WithSummary()
+	}
+
+	return func(operationsConsole *OperationsConsole) { operationsConsole.WriteResponse(response) }
+}
+
+// handleStream calculates each operation as soon as its line arrives, carrying the positions
+// over from one operation to the next within the unit of work of the whole input, and writes its
+// tax before reading the next line.
+func (calculateCapitalGain *CalculateCapitalGain) handleStream() int {
+	unitOfWork := calculateCapitalGain.unitsOfWork()
+	rejected := 0
+
+	for operation, validationErrors := range calculateCapitalGain.operationsConsole.ReadOperations() {
+		var taxes []driver.Tax
+
+		if len(validationErrors) == 0 {
+			taxes, validationErrors = calculateIncrementally(unitOfWork, operation)
+		}
+
+		if len(validationErrors) > 0 {
+			calculateCapitalGain.operationsConsole.WriteValidationErrors(validationErrors)
+			rejected++
+			continue
+		}
+
+		for _, tax := range taxes {
+			calculateCapitalGain.operationsConsole.WriteTax(tax)
+		}
+	}
+
+	return rejected
+}
+
+// handleArrayStream calculates each operation of every top-level array as soon as it is decoded,
+// writing the output array element by element, each array being an independent simulation. An
+// invalid operation gets its validation errors as its element, while broken input ends the reading.
+func (calculateCapitalGain *CalculateCapitalGain) handleArrayStream() int {
+	stream := calculateCapitalGain.operationsConsole.StreamOperations()
+	rejected := 0
+
+	for {
+		ok, validationErrors := stream.NextArray()
+
+		if len(validationErrors) > 0 {
+			calculateCapitalGain.operationsConsole.WriteValidationErrors(validationErrors)
+			return rejected + 1
+		}
+
+		if !ok {
+			return rejected
+		}
+
+		invalid, broken := calculateCapitalGain.streamArray(stream)
+		rejected += invalid
+
+		if broken {
+			return rejected + 1
+		}
+	}
+}
+
+// streamArray writes the output array of the current input array, calculated within a unit of work
+// of its own, returning how many of its operations were invalid and whether the input broke before
+// the array ended.
+func (calculateCapitalGain *CalculateCapitalGain) streamArray(stream *OperationsStream) (int, bool) {
+	calculateCapitalGain.operationsConsole.WriteArrayStart()
+	unitOfWork := calculateCapitalGain.unitsOfWork()
+	written, invalid := 0, 0
+
+	for {
+		operation, ok, validationErrors := stream.NextOperation()
+		failed := len(validationErrors) > 0
+
+		if failed && !ok {
+			calculateCapitalGain.operationsConsole.WriteArrayEnd()
+			calculateCapitalGain.operationsConsole.WriteValidationErrors(validationErrors)
+			return invalid, true
+		}
+
+		if !ok {
+			calculateCapitalGain.operationsConsole.WriteArrayEnd()
+			return invalid, false
+		}
+
+		var taxes []driver.Tax
+
+		if !failed {
+			taxes, validationErrors = calculateIncrementally(unitOfWork, operation)
+		}
+
+		if len(validationErrors) > 0 {
+			calculateCapitalGain.operationsConsole.WriteArrayInvalidElement(written, validationErrors)
+			written++
+			invalid++
+			continue
+		}
+
+		for _, tax := range taxes {
+			calculateCapitalGain.operationsConsole.WriteArrayElement(written, tax)
+			written++
+		}
+	}
+}
+
+// calculateIncrementally calculates the operation from the positions left by the previous ones of the unit of work.
+func calculateIncrementally(unitOfWork commandbus.UnitOfWork, operation driver.Operation) ([]driver.Tax, driver.ValidationErrors) {
+	capitalGains, err := unitOfWork.Apply(driver.NewRequest([]driver.Operation{operation}))
+
+	if err != nil {
+		return nil, driver.ValidationErrors{driver.NewValidationError(err)}
+	}
+
+	return driver.NewStreamedTaxes(capitalGains), nil
+}
+
+func (calculateCapitalGain *CalculateCapitalGain) calculateCommand() commands.CalculateCapitalGain {
+	command := commands.NewCalculateCapitalGain()
+
+	if calculateCapitalGain.settings.Chronological {
+		return command.Chronologically()
+	}
+
+	return command
 }
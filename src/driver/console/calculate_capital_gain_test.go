@@ -1,18 +1,25 @@
 package console_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
-	"capital-gains/src/application/handlers"
-	"capital-gains/src/driven/capitalgains"
-	"capital-gains/src/driven/operations"
 	"capital-gains/src/driver"
 	"capital-gains/src/driver/console"
+	"capital-gains/src/driver/formatters"
+	"capital-gains/src/driver/locales"
 	"capital-gains/test"
 
 	"github.com/stretchr/testify/assert"
 )
 
+// newCalculateCapitalGain wires the calculate capital gain console use case with in-memory repositories
+// of its own for each simulation, and the given settings.
+func newCalculateCapitalGain(defaultConsole console.Console, settings console.Settings) *console.CalculateCapitalGain {
+	return console.NewCalculateCapitalGain(defaultConsole, settings, test.NewUnitOfWork)
+}
+
 func TestCalculateCapitalGainPrintsExpectedTaxesForSingleInput(t *testing.T) {
 	t.Parallel()
 
@@ -31,19 +38,8 @@ func TestCalculateCapitalGainPrintsExpectedTaxesForSingleInput(t *testing.T) {
 	defaultConsole := test.NewConsoleMock([]string{test.ToJson(payload)})
 
 	// When processing these operations to calculate taxes
-	operationsRepository := operations.NewRepository()
-	capitalGainsRepository := capitalgains.NewRepository()
-	calculateCapitalGains := console.NewCalculateCapitalGain(
-		defaultConsole,
-		handlers.NewRegisterBuyHandler(operationsRepository),
-		handlers.NewRegisterSellHandler(operationsRepository),
-		capitalGainsRepository,
-		handlers.NewCalculateCapitalGainHandler(
-			operationsRepository,
-			capitalGainsRepository,
-		),
-	)
-	calculateCapitalGains.Handle()
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
 
 	// Then I expect the result to be written in a single output line
 	assert.Len(t, defaultConsole.WrittenLines(), 1)
@@ -84,19 +80,8 @@ func TestCalculateCapitalGainPrintsExpectedTaxesForMultipleInputs(t *testing.T)
 	})
 
 	// When processing these operations to calculate taxes
-	operationsRepository := operations.NewRepository()
-	capitalGainsRepository := capitalgains.NewRepository()
-	calculateCapitalGains := console.NewCalculateCapitalGain(
-		defaultConsole,
-		handlers.NewRegisterBuyHandler(operationsRepository),
-		handlers.NewRegisterSellHandler(operationsRepository),
-		capitalGainsRepository,
-		handlers.NewCalculateCapitalGainHandler(
-			operationsRepository,
-			capitalGainsRepository,
-		),
-	)
-	calculateCapitalGains.Handle()
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
 
 	// Then I expect the result to be written in two output lines (one per input line)
 	assert.Len(t, defaultConsole.WrittenLines(), 2)
@@ -139,19 +124,8 @@ func TestCalculateCapitalGainIgnoresTrailingEmptyLine(t *testing.T) {
 	})
 
 	// When processing these operations to calculate taxes
-	operationsRepository := operations.NewRepository()
-	capitalGainsRepository := capitalgains.NewRepository()
-	calculateCapitalGains := console.NewCalculateCapitalGain(
-		defaultConsole,
-		handlers.NewRegisterBuyHandler(operationsRepository),
-		handlers.NewRegisterSellHandler(operationsRepository),
-		capitalGainsRepository,
-		handlers.NewCalculateCapitalGainHandler(
-			operationsRepository,
-			capitalGainsRepository,
-		),
-	)
-	calculateCapitalGains.Handle()
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
 
 	// Then I expect the result to be written in two output lines (one per input line)
 	assert.Len(t, defaultConsole.WrittenLines(), 2)
@@ -172,28 +146,1059 @@ func TestCalculateCapitalGainIgnoresTrailingEmptyLine(t *testing.T) {
 	assert.Equal(t, test.ToJson(secondExpected), defaultConsole.GetByIndex(1))
 }
 
-func TestCalculateCapitalGainPanicsWhenInputIsNotValidJson(t *testing.T) {
+func TestCalculateCapitalGainWritesErrorWhenInputIsNotValidJson(t *testing.T) {
 	t.Parallel()
 
 	// Given an input line that cannot be parsed as a JSON list of operations
 	defaultConsole := test.NewConsoleMock([]string{"this is not json"})
 
 	// When handling the input
-	operationsRepository := operations.NewRepository()
-	capitalGainsRepository := capitalgains.NewRepository()
-	calculateCapitalGains := console.NewCalculateCapitalGain(
-		defaultConsole,
-		handlers.NewRegisterBuyHandler(operationsRepository),
-		handlers.NewRegisterSellHandler(operationsRepository),
-		capitalGainsRepository,
-		handlers.NewCalculateCapitalGainHandler(
-			operationsRepository,
-			capitalGainsRepository,
-		),
-	)
-
-	// Then I expect the application to panic
-	assert.Panics(t, func() {
-		calculateCapitalGains.Handle()
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect an error object for the line, read as a CSV header since it is not JSON, and the
+	// input to be reported as rejected
+	expected := `{"errors":[{"line":1,"code":"malformed-input","message":"malformed input: missing column \"unit-cost\""}]}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainWritesEveryValidationErrorOfALineAndContinues(t *testing.T) {
+	t.Parallel()
+
+	// Given a line with an unknown operation and a non-positive quantity, between two valid lines
+	defaultConsole := test.NewConsoleMock([]string{
+		`[{"operation":"buy","unit-cost":10.00,"quantity":100}]`,
+		`[{"operation":"buy","unit-cost":10.00,"quantity":100},{"operation":"hold","unit-cost":-1.00,"quantity":0}]`,
+		`[{"operation":"buy","unit-cost":10.00,"quantity":100}]`,
+	})
+
+	// When processing these inputs to calculate taxes
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect every problem of the invalid line located by line, operation index and field
+	expected := []string{
+		`[{"tax":0.00}]`,
+		`{"errors":[` +
+			`{"line":2,"index":1,"field":"operation","code":"unsupported-operation","message":"unsupported operation: \"hold\" is neither buy nor sell"},` +
+			`{"line":2,"index":1,"field":"quantity","code":"invalid-value","message":"invalid value: must be greater than zero"},` +
+			`{"line":2,"index":1,"field":"unit-cost","code":"invalid-value","message":"invalid value: must not be negative"}]}`,
+		`[{"tax":0.00}]`,
+	}
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
+
+	// And the input to be reported as rejected
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainWritesErrorsOfOpeningBalancesAndCorrections(t *testing.T) {
+	t.Parallel()
+
+	// Given a document with a negative opening balance and corrections that cannot be applied
+	document := map[string]any{
+		"opening-balance": map[string]any{"quantity": -10, "average-unit-cost": 10.00, "accumulated-loss": 0.00},
+		"operations": []map[string]any{
+			{"id": "buy-1", "operation": "buy", "unit-cost": 10.00, "quantity": 100},
+		},
+		"corrections": []map[string]any{
+			{"action": "amend", "id": "buy-1", "replacement": map[string]any{"operation": "buy", "unit-cost": 10.00}},
+			{"action": "cancel", "id": "buy-2"},
+		},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(document)})
+
+	// When processing the input
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect each problem located by the path of its field
+	expected := `{"errors":[` +
+		`{"line":1,"field":"opening-balances[0].quantity","code":"invalid-value","message":"invalid value: must not be negative"},` +
+		`{"line":1,"field":"corrections[0].replacement.quantity","code":"invalid-value","message":"invalid value: must be greater than zero"},` +
+		`{"line":1,"field":"corrections[1].id","code":"unknown-operation-id","message":"unknown operation id: no operation has the id \"buy-2\""}]}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainWritesErrorWhenAnOperationIsCorrectedTwice(t *testing.T) {
+	t.Parallel()
+
+	// Given lines correcting the same operation twice: cancelled twice, and amended after a cancel
+	operations := []map[string]any{
+		{"id": "s", "operation": "buy", "unit-cost": 10.00, "quantity": 100},
+	}
+	cancelledTwice := map[string]any{
+		"operations": operations,
+		"corrections": []map[string]any{
+			{"action": "cancel", "id": "s"},
+			{"action": "cancel", "id": "s"},
+		},
+	}
+	amendedAfterCancel := map[string]any{
+		"operations": operations,
+		"corrections": []map[string]any{
+			{"action": "cancel", "id": "s"},
+			{"action": "amend", "id": "s", "replacement": map[string]any{"operation": "buy", "unit-cost": 12.00, "quantity": 100}},
+		},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(cancelledTwice), test.ToJson(amendedAfterCancel)})
+
+	// When processing the input
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect the second correction of each line to be rejected instead of calculated
+	for index := range 2 {
+		expected := fmt.Sprintf(`{"errors":[`+
+			`{"line":%d,"field":"corrections[1].id","code":"invalid-value","message":"invalid value: the operation \"s\" is already corrected by corrections[0]"}]}`, index+1)
+		assert.Equal(t, expected, defaultConsole.GetByIndex(index))
+	}
+
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainReturnsNoErrorWhenEveryLineIsValid(t *testing.T) {
+	t.Parallel()
+
+	// Given a valid input line
+	defaultConsole := test.NewConsoleMock([]string{`[{"operation":"buy","unit-cost":10.00,"quantity":100}]`})
+
+	// When processing the input
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect no error
+	assert.NoError(t, err)
+}
+
+func TestCalculateCapitalGainStartsFromOpeningBalanceOnlyForTheLineThatDeclaresIt(t *testing.T) {
+	t.Parallel()
+
+	// Given a first input line starting from an opening balance with an accumulated loss
+	firstDocument := map[string]any{
+		"opening-balance": map[string]any{"quantity": 10000, "average-unit-cost": 10.00, "accumulated-loss": 5000.00},
+		"operations": []map[string]any{
+			{"operation": "sell", "unit-cost": 20.00, "quantity": 5000},
+		},
+	}
+
+	// And a second input line without a header, selling shares it does not hold yet
+	secondOperations := []map[string]any{
+		{"operation": "buy", "unit-cost": 10.00, "quantity": 5000},
+		{"operation": "sell", "unit-cost": 20.00, "quantity": 5000},
+	}
+
+	defaultConsole := test.NewConsoleMock([]string{
+		test.ToJson(firstDocument),
+		test.ToJson(secondOperations),
+	})
+
+	// When processing these operations to calculate taxes
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the result to be written in two output lines (one per input line)
+	assert.Len(t, defaultConsole.WrittenLines(), 2)
+
+	// And I expect the first line to deduct the opening accumulated loss from the profit
+	firstExpected := []driver.Tax{
+		driver.NewTax(9000.00),
+	}
+
+	// And I expect the second line to start from an empty position
+	secondExpected := []driver.Tax{
+		driver.NewTax(0.00),
+		driver.NewTax(10000.00),
+	}
+
+	assert.Equal(t, test.ToJson(firstExpected), defaultConsole.GetByIndex(0))
+	assert.Equal(t, test.ToJson(secondExpected), defaultConsole.GetByIndex(1))
+}
+
+func TestCalculateCapitalGainPrintsTaxDiffReportForLineWithCorrections(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line with dated and identified operations and the amendment of a sale
+	document := map[string]any{
+		"operations": []map[string]any{
+			{"id": "buy-1", "date": "2024-01-10", "operation": "buy", "unit-cost": 10.00, "quantity": 10000},
+			{"id": "sell-1", "date": "2024-02-05", "operation": "sell", "unit-cost": 20.00, "quantity": 5000},
+		},
+		"corrections": []map[string]any{
+			{
+				"action":      "amend",
+				"id":          "sell-1",
+				"replacement": map[string]any{"date": "2024-02-05", "operation": "sell", "unit-cost": 15.00, "quantity": 5000},
+			},
+		},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(document)})
+
+	// When processing the input
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect a single output line with the tax diff report
+	assert.Len(t, defaultConsole.WrittenLines(), 1)
+
+	expected := `{"operations":[{"index":1,"id":"sell-1","month":"2024-02","status":"amended",` +
+		`"tax-before":10000.00,"tax-after":5000.00,"difference":-5000.00}],` +
+		`"months":[{"month":"2024-02","tax-before":10000.00,"tax-after":5000.00,"difference":-5000.00}]}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainEchoesOperationIDsAndIgnoresDuplicatedOperations(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line with identified operations, where the same file was imported twice
+	importedOperations := []map[string]any{
+		{"id": "trade-1", "operation": "buy", "unit-cost": 10.00, "quantity": 10000},
+		{"id": "trade-2", "operation": "sell", "unit-cost": 20.00, "quantity": 5000},
+	}
+	payload := append(importedOperations, importedOperations...)
+
+	// And an operation without id, which is never deduplicated
+	payload = append(payload, map[string]any{"operation": "sell", "unit-cost": 20.00, "quantity": 1000})
+
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(payload)})
+
+	// When processing these operations to calculate taxes
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect one output element per distinct operation, echoing its id
+	expectedTaxes := []driver.Tax{
+		driver.NewTax(0.00).WithID("trade-1"),
+		driver.NewTax(10000.00).WithID("trade-2"),
+		driver.NewTax(0.00),
+	}
+	assert.Equal(t, test.ToJson(expectedTaxes), defaultConsole.GetByIndex(0))
+	assert.Equal(t, `[{"id":"trade-1","tax":0.00},{"id":"trade-2","tax":10000.00},{"tax":0.00}]`, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainExplainsChronologicallyReorderedOperations(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line where a sell was given before the buy it depends on
+	payload := []map[string]any{
+		{"id": "sell-1", "date": "2024-02-05", "operation": "sell", "unit-cost": 20.00, "quantity": 5000},
+		{"id": "buy-1", "date": "2024-01-10", "operation": "buy", "unit-cost": 10.00, "quantity": 10000},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(payload)})
+
+	// When processing the input chronologically with explain output
+	settings := console.Settings{Chronological: true, Explain: true}
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, settings)
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the explanation of each operation in input order, and the reordering to be reported
+	expected := `{"operations":[` +
+		`{"index":0,"id":"sell-1","date":"2024-02-05","operation":"sell","quantity":5000,"unit-cost":20.00,` +
+		`"applied-at":1,"gain":50000.00,"deducted-loss":0.00,"tax":10000.00,` +
+		`"position":{"quantity":5000,"average-unit-cost":10.00,"accumulated-loss":0.00}},` +
+		`{"index":1,"id":"buy-1","date":"2024-01-10","operation":"buy","quantity":10000,"unit-cost":10.00,` +
+		`"applied-at":0,"gain":0.00,"deducted-loss":0.00,"tax":0.00,` +
+		`"position":{"quantity":10000,"average-unit-cost":10.00,"accumulated-loss":0.00}}],` +
+		`"reordered":[{"index":1,"id":"buy-1","applied-at":0},{"index":0,"id":"sell-1","applied-at":1}]}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainExplainsTimeOfOperationsTradedAtMidnight(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line whose operation was traded at midnight
+	defaultConsole := test.NewConsoleMock([]string{
+		`[{"date":"2024-01-10","time":"00:00:00","operation":"buy","unit-cost":10.00,"quantity":100}]`,
+	})
+
+	// When processing the input with explain output
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Explain: true})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the time of the operation to be kept along with its date
+	assert.Contains(t, defaultConsole.GetByIndex(0), `"date":"2024-01-10 00:00:00"`)
+}
+
+func TestCalculateCapitalGainReportsTaxesPerMonth(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line with sells in two months, one of them with a loss
+	payload := []map[string]any{
+		{"date": "2024-01-10", "operation": "buy", "unit-cost": 10.00, "quantity": 10000},
+		{"date": "2024-01-20", "operation": "sell", "unit-cost": 5.00, "quantity": 5000},
+		{"date": "2024-02-05", "operation": "sell", "unit-cost": 20.00, "quantity": 5000},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(payload)})
+
+	// When processing these operations with report output
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Report: true})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the proceeds, the realized gain and the tax of each month, along with the total tax
+	expected := `{"months":[` +
+		`{"month":"2024-01","proceeds":25000.00,"gain":-25000.00,"tax":0.00},` +
+		`{"month":"2024-02","proceeds":100000.00,"gain":50000.00,"tax":5000.00}],` +
+		`"total-tax":5000.00}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainWritesTheReportsAsASingleHTMLDocument(t *testing.T) {
+	t.Parallel()
+
+	// Given two input lines
+	payload := []map[string]any{
+		{"date": "2024-01-10", "operation": "buy", "unit-cost": 10.00, "quantity": 10000},
+		{"date": "2024-02-05", "operation": "sell", "unit-cost": 20.00, "quantity": 5000},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(payload), test.ToJson(payload)})
+
+	// When processing them with report output in HTML
+	settings := console.Settings{Report: true, Output: formatters.HTMLFormat}
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, settings)
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect a section per line, within a document closed once every line was written
+	lines := defaultConsole.WrittenLines()
+
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "<!DOCTYPE html>"))
+	assert.Contains(t, lines[0], `<p class="total">Total tax: 10000.00</p>`)
+	assert.True(t, strings.HasPrefix(lines[1], "<section>\n<h2>Simulation 2</h2>"))
+	assert.Equal(t, "</main>\n</body>\n</html>", lines[2])
+}
+
+func TestCalculateCapitalGainWritesTaxesInTheSelectedFormat(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line with a buy and a sell
+	payload := []map[string]any{
+		{"operation": "buy", "unit-cost": 10.00, "quantity": 10000},
+		{"operation": "sell", "unit-cost": 20.00, "quantity": 5000},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(payload)})
+
+	// When processing these operations with Markdown output and the operation column
+	settings := console.Settings{Output: formatters.MarkdownFormat, Columns: []formatters.Column{formatters.OperationColumn}}
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, settings)
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the taxes to be rendered as a Markdown table
+	expected := "| Operation |      Tax |\n" +
+		"| :-------- | -------: |\n" +
+		"| buy       |     0.00 |\n" +
+		"| sell      | 10000.00 |"
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainWrapsTaxesAlongWithTheSummary(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line with a taxed sell and a sell at a loss
+	payload := []map[string]any{
+		{"operation": "buy", "unit-cost": 10.00, "quantity": 10000},
+		{"operation": "sell", "unit-cost": 20.00, "quantity": 5000},
+		{"operation": "sell", "unit-cost": 5.00, "quantity": 2000},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(payload)})
+
+	// When processing these operations with the summary
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Summary: true})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the taxes to be wrapped along with the totals and the final position
+	expected := `{"taxes":[{"tax":0.00},{"tax":10000.00},{"tax":0.00}],` +
+		`"summary":{"total-tax":10000.00,"total-proceeds":110000.00,"realized-gain":50000.00,"realized-loss":10000.00,` +
+		`"accumulated-loss":10000.00,"positions":[{"quantity":3000,"average-unit-cost":10.00,"accumulated-loss":10000.00}]}}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainSummarizesEveryAccount(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line with the operations of two accounts bought at different costs
+	payload := []map[string]any{
+		{"account": "alice", "operation": "buy", "unit-cost": 10.00, "quantity": 100},
+		{"account": "bob", "operation": "buy", "unit-cost": 20.00, "quantity": 300},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(payload)})
+
+	// When processing these operations with the summary
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Summary: true})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the grouped taxes to be wrapped along with the final position of each account
+	expected := `{"taxes":{"accounts":[` +
+		`{"account":"alice","taxes":[{"tax":0.00}],` +
+		`"summary":{"total-tax":0.00,"quantity":100,"average-unit-cost":10.00,"accumulated-loss":0.00}},` +
+		`{"account":"bob","taxes":[{"tax":0.00}],` +
+		`"summary":{"total-tax":0.00,"quantity":300,"average-unit-cost":20.00,"accumulated-loss":0.00}}]},` +
+		`"summary":{"total-tax":0.00,"total-proceeds":0.00,"realized-gain":0.00,"realized-loss":0.00,"accumulated-loss":0.00,` +
+		`"positions":[{"account":"alice","quantity":100,"average-unit-cost":10.00,"accumulated-loss":0.00},` +
+		`{"account":"bob","quantity":300,"average-unit-cost":20.00,"accumulated-loss":0.00}]}}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainSummarizesEveryTicker(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line buying two tickers at different costs
+	payload := []map[string]any{
+		{"ticker": "PETR4", "operation": "buy", "unit-cost": 10.00, "quantity": 100},
+		{"ticker": "VALE3", "operation": "buy", "unit-cost": 20.00, "quantity": 300},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(payload)})
+
+	// When processing these operations with the summary
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Summary: true})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the taxes to be wrapped along with the final position of each ticker, kept apart
+	expected := `{"taxes":[{"tax":0.00},{"tax":0.00}],` +
+		`"summary":{"total-tax":0.00,"total-proceeds":0.00,"realized-gain":0.00,"realized-loss":0.00,"accumulated-loss":0.00,` +
+		`"positions":[{"ticker":"PETR4","quantity":100,"average-unit-cost":10.00,"accumulated-loss":0.00},` +
+		`{"ticker":"VALE3","quantity":300,"average-unit-cost":20.00,"accumulated-loss":0.00}]}}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainWritesErrorWhenChronologicalOrderIsAmbiguous(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line with a buy and a sell on the same day and no sequence, followed by a valid line
+	payload := []map[string]any{
+		{"date": "2024-01-10", "operation": "buy", "unit-cost": 10.00, "quantity": 100},
+		{"date": "2024-01-10", "operation": "sell", "unit-cost": 20.00, "quantity": 100},
+	}
+	defaultConsole := test.NewConsoleMock([]string{
+		test.ToJson(payload),
+		`[{"date":"2024-01-10","operation":"buy","unit-cost":10.00,"quantity":100}]`,
+	})
+
+	// When processing the input chronologically
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Chronological: true})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect an error for each operation that cannot be ordered, and the next line to be calculated
+	expected := []string{
+		`{"errors":[{"line":1,"index":0,"field":"sequence","code":"missing-field",` +
+			`"message":"missing field: required to order the buys and sells traded at the same moment"},` +
+			`{"line":1,"index":1,"field":"sequence","code":"missing-field",` +
+			`"message":"missing field: required to order the buys and sells traded at the same moment"}]}`,
+		`[{"tax":0.00}]`,
+	}
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainGroupsTaxesByAccount(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line interleaving the operations of two accounts, one of them with an opening balance
+	payload := map[string]any{
+		"opening-balances": []map[string]any{
+			{"account": "bob", "quantity": 100, "average-unit-cost": 10.00, "accumulated-loss": 0.00},
+		},
+		"operations": []map[string]any{
+			{"account": "alice", "operation": "buy", "unit-cost": 10.00, "quantity": 10000},
+			{"account": "bob", "operation": "sell", "unit-cost": 5.00, "quantity": 100},
+			{"account": "alice", "operation": "sell", "unit-cost": 20.00, "quantity": 5000},
+		},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(payload)})
+
+	// When processing these operations to calculate taxes
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the taxes of each account to be computed independently and grouped with a summary
+	expected := `{"accounts":[` +
+		`{"account":"alice","taxes":[{"tax":0.00},{"tax":10000.00}],` +
+		`"summary":{"total-tax":10000.00,"quantity":5000,"average-unit-cost":10.00,"accumulated-loss":0.00}},` +
+		`{"account":"bob","taxes":[{"tax":0.00}],` +
+		`"summary":{"total-tax":0.00,"quantity":0,"average-unit-cost":0.00,"accumulated-loss":500.00}}]}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainAppliesDocumentAccountToOperationsWithoutOne(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line whose header sets the default account of its operations
+	payload := map[string]any{
+		"account": "alice",
+		"operations": []map[string]any{
+			{"operation": "buy", "unit-cost": 10.00, "quantity": 100},
+			{"account": "bob", "operation": "buy", "unit-cost": 20.00, "quantity": 50},
+		},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(payload)})
+
+	// When processing these operations to calculate taxes
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the operation without an account to belong to the default one
+	expected := `{"accounts":[` +
+		`{"account":"alice","taxes":[{"tax":0.00}],` +
+		`"summary":{"total-tax":0.00,"quantity":100,"average-unit-cost":10.00,"accumulated-loss":0.00}},` +
+		`{"account":"bob","taxes":[{"tax":0.00}],` +
+		`"summary":{"total-tax":0.00,"quantity":50,"average-unit-cost":20.00,"accumulated-loss":0.00}}]}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainReadsCSVInput(t *testing.T) {
+	t.Parallel()
+
+	// Given a CSV export with a header row and one operation per row
+	defaultConsole := test.NewConsoleMock([]string{
+		"operation,unit-cost,quantity,fees",
+		"buy,10.00,10000,100.00",
+		"sell,20.00,5000,50.00",
+	})
+
+	// When processing the input with the format detected automatically
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the taxes of the operations, the fees leaving them unchanged
+	expectedTaxes := []driver.Tax{
+		driver.NewTax(0.00),
+		driver.NewTax(10000.00),
+	}
+	assert.Equal(t, test.ToJson(expectedTaxes), defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainKeepsAPositionPerTicker(t *testing.T) {
+	t.Parallel()
+
+	// Given a CSV export buying two tickers at different prices and selling one of them
+	defaultConsole := test.NewConsoleMock([]string{
+		"ticker,operation,unit-cost,quantity",
+		"PETR4,buy,10.00,10000",
+		"VALE3,buy,100.00,10000",
+		"PETR4,sell,20.00,5000",
+	})
+
+	// When processing the input
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the sell to be taxed from the average cost of its own ticker, in the tax array
+	assert.Equal(t, `[{"tax":0.00},{"tax":0.00},{"tax":10000.00}]`, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainKeepsAPositionPerTickerOfEachAccount(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line where an account trades two tickers, selling one at a loss and the other at a gain
+	payload := []map[string]any{
+		{"account": "alice", "ticker": "PETR4", "operation": "buy", "unit-cost": 10.00, "quantity": 10000},
+		{"account": "alice", "ticker": "VALE3", "operation": "buy", "unit-cost": 100.00, "quantity": 10000},
+		{"account": "alice", "ticker": "VALE3", "operation": "sell", "unit-cost": 90.00, "quantity": 5000},
+		{"account": "alice", "ticker": "PETR4", "operation": "sell", "unit-cost": 20.00, "quantity": 5000},
+	}
+	defaultConsole := test.NewConsoleMock([]string{test.ToJson(payload)})
+
+	// When processing the input
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect a position per ticker of the account, the loss on VALE3 deducted from the gain on PETR4
+	expected := `{"accounts":[{"account":"alice","taxes":[{"tax":0.00},{"tax":0.00},{"tax":0.00},{"tax":0.00}],` +
+		`"summary":{"total-tax":0.00,"quantity":0,"average-unit-cost":0.00,"tickers":[` +
+		`{"ticker":"PETR4","quantity":5000,"average-unit-cost":10.00},` +
+		`{"ticker":"VALE3","quantity":5000,"average-unit-cost":100.00}],"accumulated-loss":0.00}}]}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainReadsAmountsWrittenInTheSelectedLocale(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line whose amounts are written as text in Brazilian Portuguese
+	defaultConsole := test.NewConsoleMock([]string{
+		`[{"operation":"buy","unit-cost":"R$ 10,00","quantity":"10.000"},` +
+			`{"operation":"sell","unit-cost":"20,00","quantity":5000}]`,
+	})
+
+	// When processing these operations in the pt-BR locale
+	settings := console.Settings{Locale: locales.BrazilianPortuguese()}
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, settings)
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the taxes in the dot-decimal notation of the JSON contract
+	expectedTaxes := []driver.Tax{
+		driver.NewTax(0.00),
+		driver.NewTax(10000.00),
+	}
+	assert.Equal(t, test.ToJson(expectedTaxes), defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainWritesErrorForAmountNotWrittenInTheSelectedLocale(t *testing.T) {
+	t.Parallel()
+
+	// Given an input line whose second unit cost is written in dot-decimal notation as text
+	defaultConsole := test.NewConsoleMock([]string{
+		`{"opening-balances":[{"quantity":100,"average-unit-cost":"1,5","accumulated-loss":"abc"}],` +
+			`"operations":[{"operation":"buy","unit-cost":"10,00","quantity":100},{"operation":"buy","unit-cost":"10.5","quantity":100}]}`,
+	})
+
+	// When processing these operations in the pt-BR locale
+	settings := console.Settings{Locale: locales.BrazilianPortuguese()}
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, settings)
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect an error on each amount that is not a number in that locale, naming the locale
+	expected := `{"errors":[` +
+		`{"line":1,"field":"opening-balances[0].accumulated-loss","code":"invalid-value",` +
+		`"message":"invalid value: invalid number: \"abc\" is not a number as written in pt-BR"},` +
+		`{"line":1,"index":1,"field":"unit-cost","code":"invalid-value",` +
+		`"message":"invalid value: invalid number: \"10.5\" is not grouped in thousands as in pt-BR"}]}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainReadsCSVInputExportedInTheSelectedLocale(t *testing.T) {
+	t.Parallel()
+
+	// Given a CSV export of a Brazilian spreadsheet, separated by semicolons and with decimal commas
+	defaultConsole := test.NewConsoleMock([]string{
+		"operation;unit-cost;quantity",
+		"buy;10,00;10.000",
+		"sell;20,00;5.000",
+	})
+
+	// When processing the input as CSV in the pt-BR locale
+	settings := console.Settings{Input: console.CSVInput, Locale: locales.BrazilianPortuguese()}
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, settings)
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the taxes of the operations
+	expectedTaxes := []driver.Tax{
+		driver.NewTax(0.00),
+		driver.NewTax(10000.00),
+	}
+	assert.Equal(t, test.ToJson(expectedTaxes), defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainWritesErrorWithRowNumberForInvalidCSVInput(t *testing.T) {
+	t.Parallel()
+
+	// Given a CSV export whose second operation row has an invalid unit cost
+	defaultConsole := test.NewConsoleMock([]string{
+		"operation,unit-cost,quantity",
+		"buy,10.00,100",
+		"sell,ten,100",
+	})
+
+	// When processing the input as CSV
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Input: console.CSVInput})
+
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect an error object locating the row
+	expected := `{"errors":[{"line":3,"code":"malformed-input",` +
+		`"message":"malformed input: invalid value for \"unit-cost\": \"ten\" is not a decimal"}]}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainReadsB3Statement(t *testing.T) {
+	t.Parallel()
+
+	// Given a B3 trade statement with a fractional-market buy and a later sell of the same asset
+	defaultConsole := test.NewConsoleMock([]string{
+		"Data do Negócio;Tipo de Movimentação;Mercado;Código de Negociação;Quantidade;Preço",
+		"05/02/2024;Venda;Mercado à Vista;PETR4;1000;R$ 30,00",
+		"10/01/2024;Compra;Mercado à Vista;PETR4;990;R$ 10,00",
+		"10/01/2024;Compra;Mercado Fracionário;PETR4F;10;R$ 10,00",
+	})
+
+	// When processing the statement
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Input: console.B3Input})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the consolidated buy and the sell to be computed for the asset
+	assert.Equal(t, `[{"tax":0.00},{"tax":4000.00}]`, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainDetectsOFXStatement(t *testing.T) {
+	t.Parallel()
+
+	// Given an OFX statement with a buy and a taxable sell, without a security list
+	defaultConsole := test.NewConsoleMock([]string{
+		"OFXHEADER:100",
+		"DATA:OFXSGML",
+		"",
+		"<OFX><INVTRANLIST>",
+		"<BUYSTOCK><INVBUY><INVTRAN><FITID>1<DTTRADE>20240110</INVTRAN>",
+		"<SECID><UNIQUEID>PETR4</SECID><UNITS>10000<UNITPRICE>10.00</INVBUY></BUYSTOCK>",
+		"<SELLSTOCK><INVSELL><INVTRAN><FITID>2<DTTRADE>20240205</INVTRAN>",
+		"<SECID><UNIQUEID>PETR4</SECID><UNITS>-5000<UNITPRICE>20.00</INVSELL></SELLSTOCK>",
+		"</INVTRANLIST></OFX>",
+	})
+
+	// When processing the input with the format detected automatically
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the transactions to be computed, echoing their ids
+	assert.Equal(t, `[{"id":"1","tax":0.00},{"id":"2","tax":10000.00}]`, defaultConsole.GetByIndex(0))
+}
+
+func TestCalculateCapitalGainCalculatesOFXStatementSkippingOtherTransactions(t *testing.T) {
+	t.Parallel()
+
+	// Given an OFX statement with income received between a buy and a taxable sell
+	defaultConsole := test.NewConsoleMock([]string{
+		"<OFX><INVTRANLIST>",
+		"<BUYSTOCK><INVBUY><INVTRAN><FITID>1<DTTRADE>20240110</INVTRAN>",
+		"<SECID><UNIQUEID>PETR4</SECID><UNITS>10000<UNITPRICE>10.00</INVBUY></BUYSTOCK>",
+		"<INCOME><INVTRAN><FITID>2<DTTRADE>20240115</INVTRAN><INCOMETYPE>DIV<TOTAL>10.00</INCOME>",
+		"<SELLSTOCK><INVSELL><INVTRAN><FITID>3<DTTRADE>20240205</INVTRAN>",
+		"<SECID><UNIQUEID>PETR4</SECID><UNITS>-5000<UNITPRICE>20.00</INVSELL></SELLSTOCK>",
+		"</INVTRANLIST></OFX>",
+	})
+
+	// When processing the input with the format detected automatically
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect the buy and the sell to be computed
+	assert.Equal(t, `[{"id":"1","tax":0.00},{"id":"3","tax":10000.00}]`, defaultConsole.GetByIndex(0))
+
+	// And the income to be reported as skipped, without rejecting the input
+	assert.ErrorIs(t, err, console.ErrSkippedInput)
+	assert.NotErrorIs(t, err, console.ErrRejectedInput)
+	assert.EqualError(t, err, "skipped input: line 1: unsupported transaction: INCOME (2)")
+}
+
+func TestCalculateCapitalGainStreamsOneTaxPerNDJSONLine(t *testing.T) {
+	t.Parallel()
+
+	// Given an NDJSON input with one operation per line, across two accounts
+	defaultConsole := test.NewConsoleMock([]string{
+		`{"id":"a-1","account":"alice","operation":"buy","unit-cost":10.00,"quantity":10000}`,
+		`{"id":"b-1","account":"bob","operation":"buy","unit-cost":10.00,"quantity":100}`,
+		``,
+		`{"id":"a-2","account":"alice","operation":"sell","unit-cost":20.00,"quantity":5000}`,
+		`{"operation":"buy","unit-cost":5.00,"quantity":10}`,
 	})
+
+	// When processing the input as a stream
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Input: console.NDJSONInput})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect one output line per operation, calculated from the position of its account
+	expected := []string{
+		`{"id":"a-1","account":"alice","tax":0.00}`,
+		`{"id":"b-1","account":"bob","tax":0.00}`,
+		`{"id":"a-2","account":"alice","tax":10000.00}`,
+		`{"tax":0.00}`,
+	}
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
+}
+
+func TestCalculateCapitalGainIgnoresNDJSONLinesRepeatingAnID(t *testing.T) {
+	t.Parallel()
+
+	// Given an NDJSON input where the same identified buy arrives twice before a sell
+	defaultConsole := test.NewConsoleMock([]string{
+		`{"id":"t1","operation":"buy","unit-cost":10.00,"quantity":10000}`,
+		`{"id":"t1","operation":"buy","unit-cost":10.00,"quantity":10000}`,
+		`{"id":"t2","operation":"sell","unit-cost":20.00,"quantity":5000}`,
+	})
+
+	// When processing the input as a stream
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Input: console.NDJSONInput})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the repeated buy to be ignored, as it is within an input line, without output
+	expected := []string{
+		`{"id":"t1","tax":0.00}`,
+		`{"id":"t2","tax":10000.00}`,
+	}
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
+}
+
+func TestCalculateCapitalGainWritesErrorWhenNDJSONLineIsNotAnOperation(t *testing.T) {
+	t.Parallel()
+
+	// Given an NDJSON input whose second line is an array rather than an operation object
+	defaultConsole := test.NewConsoleMock([]string{
+		`{"operation":"buy","unit-cost":10.00,"quantity":100}`,
+		`[{"operation":"sell","unit-cost":20.00,"quantity":100}]`,
+		`{"operation":"sell","unit-cost":20.00,"quantity":100}`,
+	})
+
+	// When processing the input as a stream
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Input: console.NDJSONInput})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect an error object in place of the tax of the second line, and the next line calculated
+	expected := []string{
+		`{"tax":0.00}`,
+		`{"errors":[{"line":2,"code":"malformed-input","message":"malformed input: expected a JSON operation object"}]}`,
+		`{"tax":0.00}`,
+	}
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainStreamsOutputArrayOfEachInputArray(t *testing.T) {
+	t.Parallel()
+
+	// Given two minified arrays, the first one split across lines, and an empty array
+	defaultConsole := test.NewConsoleMock([]string{
+		`[{"operation":"buy","unit-cost":10.00,"quantity":10000},`,
+		`{"id":"sell-1","operation":"sell","unit-cost":20.00,"quantity":5000}]`,
+		`[{"operation":"buy","unit-cost":10.00,"quantity":100},{"operation":"sell","unit-cost":5.00,"quantity":100}]`,
+		`[]`,
+	})
+
+	// When processing the input as a stream
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Stream: true})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect one output array per input array, each one an independent simulation
+	expected := []string{
+		`[{"tax":0.00},{"id":"sell-1","tax":10000.00}]`,
+		`[{"tax":0.00},{"tax":0.00}]`,
+		`[]`,
+	}
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
+}
+
+func TestCalculateCapitalGainIgnoresStreamedOperationsRepeatingAnIDOfTheirArray(t *testing.T) {
+	t.Parallel()
+
+	// Given two arrays where the same identified buy appears twice in the first one
+	defaultConsole := test.NewConsoleMock([]string{
+		`[{"id":"t1","operation":"buy","unit-cost":10.00,"quantity":100},{"id":"t1","operation":"buy","unit-cost":10.00,"quantity":100}]`,
+		`[{"id":"t1","operation":"buy","unit-cost":10.00,"quantity":100}]`,
+	})
+
+	// When processing the input as a stream
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Stream: true})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect the repeated buy to be ignored within its array only
+	expected := []string{
+		`[{"id":"t1","tax":0.00}]`,
+		`[{"id":"t1","tax":0.00}]`,
+	}
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
+}
+
+func TestCalculateCapitalGainWritesErrorWhenStreamedInputIsNotAnArray(t *testing.T) {
+	t.Parallel()
+
+	// Given an input holding an object rather than an array of operations
+	defaultConsole := test.NewConsoleMock([]string{`{"operations":[]}`})
+
+	// When processing the input as a stream
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Stream: true})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect an error object, and the input to be reported as rejected
+	expected := `{"errors":[{"line":1,"code":"malformed-input","message":"malformed input: expected a JSON array of operations"}]}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainWritesErrorsInPlaceOfInvalidStreamedOperation(t *testing.T) {
+	t.Parallel()
+
+	// Given a streamed array whose second operation, on its second line, has no quantity
+	defaultConsole := test.NewConsoleMock([]string{
+		`[{"operation":"buy","unit-cost":10.00,"quantity":100},`,
+		`{"operation":"sell","unit-cost":20.00},`,
+		`{"operation":"sell","unit-cost":20.00,"quantity":100}]`,
+	})
+
+	// When processing the input as a stream
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Stream: true})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect the errors, located at the line and index of the invalid operation, as its element,
+	// and the following ones calculated
+	expected := `[{"tax":0.00},` +
+		`{"errors":[{"line":2,"index":1,"field":"quantity","code":"invalid-value","message":"invalid value: must be greater than zero"}]},` +
+		`{"tax":0.00}]`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainReadsPrettyPrintedSimulationsSeparatedByBlankLines(t *testing.T) {
+	t.Parallel()
+
+	// Given a pretty-printed array, a blank line, and two concatenated values on a single line
+	defaultConsole := test.NewConsoleMock([]string{
+		``,
+		`[`,
+		`  {"operation": "buy", "unit-cost": 10.00, "quantity": 10000},`,
+		``,
+		`  {"operation": "sell", "unit-cost": 20.00, "quantity": 5000}`,
+		`]`,
+		``,
+		``,
+		`[{"operation":"buy","unit-cost":10.00,"quantity":100}] {"operations":[]}`,
+		``,
+	})
+
+	// When processing these inputs to calculate taxes
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	assert.NoError(t, calculateCapitalGains.Handle())
+
+	// Then I expect one output line per top-level value, each one an independent simulation
+	expected := []string{
+		`[{"tax":0.00},{"tax":10000.00}]`,
+		`[{"tax":0.00}]`,
+		`[]`,
+	}
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
+}
+
+func TestCalculateCapitalGainWritesErrorWhenTopLevelValueIsNotASimulation(t *testing.T) {
+	t.Parallel()
+
+	// Given a valid array followed by a top-level number
+	defaultConsole := test.NewConsoleMock([]string{
+		`[{"operation":"buy","unit-cost":10.00,"quantity":100}]`,
+		``,
+		`42`,
+		`[`,
+		`  {"operation":"buy","unit-cost":10.00,"quantity":100}`,
+		`]`,
+	})
+
+	// When processing these inputs to calculate taxes
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect an error object located at the line of the number, between the outputs of the arrays
+	expected := []string{
+		`[{"tax":0.00}]`,
+		`{"errors":[{"line":3,"code":"malformed-input",` +
+			`"message":"malformed input: expected a JSON array of operations or an object with operations"}]}`,
+		`[{"tax":0.00}]`,
+	}
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainResumesAfterASimulationLeftOpen(t *testing.T) {
+	t.Parallel()
+
+	// Given a simulation missing its closing brackets between valid ones
+	defaultConsole := test.NewConsoleMock([]string{
+		`[{"operation":"buy","unit-cost":10.00,"quantity":100}]`,
+		`[{"operation":"buy",`,
+		`[{"operation":"buy","unit-cost":10.00,"quantity":10000},{"operation":"sell","unit-cost":20.00,"quantity":5000}]`,
+		`[{"operation":"buy","unit-cost":10.00,"quantity":100}]`,
+	})
+
+	// When processing these inputs to calculate taxes
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect an error object located at the open simulation, and the following ones calculated
+	expected := []string{
+		`[{"tax":0.00}]`,
+		`{"errors":[{"line":2,"code":"malformed-input","message":"malformed input: not valid JSON"}]}`,
+		`[{"tax":0.00},{"tax":10000.00}]`,
+		`[{"tax":0.00}]`,
+	}
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainWritesErrorForUndatedOperationInChronologicalMode(t *testing.T) {
+	t.Parallel()
+
+	// Given a line whose second operation has no trade date
+	defaultConsole := test.NewConsoleMock([]string{
+		`[{"date":"2024-01-10","operation":"buy","unit-cost":10.00,"quantity":100},{"operation":"sell","unit-cost":20.00,"quantity":100}]`,
+	})
+
+	// When processing the input in chronological order
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Chronological: true})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect an error object for the missing date instead of the taxes
+	expected := `{"errors":[{"line":1,"index":1,"field":"date","code":"missing-field",` +
+		`"message":"missing field: required to apply the operations chronologically"}]}`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainRejectsLinesBreakingTheContractInStrictMode(t *testing.T) {
+	t.Parallel()
+
+	// Given a line with numbers given as strings and a line with a misspelled field
+	defaultConsole := test.NewConsoleMock([]string{
+		`[{"operation":"buy","unit-cost":"10.00","quantity":"10000"},{"operation":"sell","unit-cost":"20.00","quantity":"5000"}]`,
+		`[{"operation":"buy","unit-cost":10.00,"quantity":100,"tickr":"PETR4"}]`,
+	})
+
+	// When processing the input in strict mode
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Strict: true})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect the taxes of the first line and the unknown field of the second one
+	expected := []string{
+		`[{"tax":0.00},{"tax":10000.00}]`,
+		`{"errors":[{"line":2,"index":0,"field":"tickr","code":"unknown-field","message":"unknown field: not defined by the contract"}]}`,
+	}
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainStreamsStrictlyParsedOperations(t *testing.T) {
+	t.Parallel()
+
+	// Given a streamed array whose second operation has a price with three decimal places
+	defaultConsole := test.NewConsoleMock([]string{
+		`[{"operation":"buy","unit-cost":"10.00","quantity":100},{"operation":"sell","unit-cost":20.001,"quantity":100}]`,
+	})
+
+	// When processing the input as a stream in strict mode
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Stream: true, Strict: true})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect the errors as the element of the lossy operation
+	expected := `[{"tax":0.00},{"errors":[{"line":1,"index":1,"field":"unit-cost","code":"invalid-value",` +
+		`"message":"invalid value: \"20.001\" must have at most two decimal places"}]}]`
+	assert.Equal(t, expected, defaultConsole.GetByIndex(0))
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestCalculateCapitalGainWritesConcurrentlyCalculatedLinesInInputOrder(t *testing.T) {
+	t.Parallel()
+
+	// Given many simulations selling at a price of their own, with an invalid one among them
+	const lines = 200
+
+	inputs := make([]string, 0, lines+1)
+	expected := make([]string, 0, lines+1)
+
+	for index := range lines {
+		inputs = append(inputs, fmt.Sprintf(`[{"operation":"buy","unit-cost":10.00,"quantity":10000},`+
+			`{"operation":"sell","unit-cost":%d.00,"quantity":5000}]`, 20+index))
+		expected = append(expected, fmt.Sprintf(`[{"tax":0.00},{"tax":%d.00}]`, 1000*(10+index)))
+
+		if index == lines/2 {
+			inputs = append(inputs, `[{"operation":"hold","unit-cost":10.00,"quantity":100}]`)
+			expected = append(expected, `{"errors":[{"line":102,"index":0,"field":"operation","code":"unsupported-operation",`+
+				`"message":"unsupported operation: \"hold\" is neither buy nor sell"}]}`)
+		}
+	}
+
+	defaultConsole := test.NewConsoleMock(inputs)
+
+	// When processing them with several workers
+	calculateCapitalGains := newCalculateCapitalGain(defaultConsole, console.Settings{Workers: 8})
+	err := calculateCapitalGains.Handle()
+
+	// Then I expect the output of each line in the order of the input, the invalid one rejected
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
 }
@@ -1,10 +1,23 @@
 package console
 
+import "io"
+
 // Console abstracts the terminal I/O used by the application.
 type Console interface {
-	// ReadLines returns all lines read from the standard input until EOF.
+	// ReadLines returns the lines not read yet from the standard input, until EOF.
 	ReadLines() []string
 
+	// ReadLine returns the next line read from the standard input as soon as it arrives,
+	// or false when the input reached EOF.
+	ReadLine() (string, bool)
+
+	// Input returns the standard input, for reading it as a stream of tokens rather than lines.
+	Input() io.Reader
+
+	// Write writes the text to the standard output without ending the line, so a single
+	// output line can be streamed in parts. The text is flushed along with the next line.
+	Write(text string)
+
 	// WriteLine writes a single line to the standard output.
 	WriteLine(text string)
 }
@@ -2,62 +2,111 @@ package console
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"sync"
 )
 
-const (
-	// maximumScannerTokenSizeBytes is the maximum allowed size for a single scanned token (line).
-	// This prevents bufio.Scanner from failing when a JSON line is very large (e.g., minified JSON).
-	maximumScannerTokenSizeBytes = 10_485_760
-
-	// defaultScannerBufferCapacityBytes is the initial buffer capacity used by bufio.Scanner.
-	// It should be large enough for typical JSON inputs without causing reallocations.
-	defaultScannerBufferCapacityBytes = 65_536
-)
+// defaultInputBufferCapacityBytes is the capacity of the buffer the input is read through.
+// Lines longer than the buffer are still read in full, so there is no limit on the size of a line.
+const defaultInputBufferCapacityBytes = 65_536
 
+// DefaultConsole reads and writes the standard streams, or the given ones. Once reading the input or
+// writing the output fails, the input reads as ended and the output is no longer written, the failure
+// being kept for Err to return.
 type DefaultConsole struct {
-	inputScanner *bufio.Scanner
+	inputReader  *bufio.Reader
 	outputWriter *bufio.Writer
+	failure      error
+	failureMutex sync.Mutex
 }
 
 func NewDefaultConsole() *DefaultConsole {
-	inputScanner := bufio.NewScanner(os.Stdin)
-	inputScanner.Buffer(make([]byte, 0, defaultScannerBufferCapacityBytes), maximumScannerTokenSizeBytes)
+	return NewDefaultConsoleWith(os.Stdin, os.Stdout)
+}
+
+// NewDefaultConsoleWith returns the console reading from and writing to the given streams, such as files.
+func NewDefaultConsoleWith(input io.Reader, output io.Writer) *DefaultConsole {
+	adapter := &DefaultConsole{outputWriter: bufio.NewWriter(output)}
+	adapter.inputReader = bufio.NewReaderSize(&consoleInput{reader: input, console: adapter}, defaultInputBufferCapacityBytes)
 
-	return &DefaultConsole{
-		inputScanner: inputScanner,
-		outputWriter: bufio.NewWriter(os.Stdout),
+	return adapter
+}
+
+// Err returns the first error reading the input or writing the output, if any.
+func (adapter *DefaultConsole) Err() error {
+	adapter.failureMutex.Lock()
+	defer adapter.failureMutex.Unlock()
+
+	return adapter.failure
+}
+
+func (adapter *DefaultConsole) fail(err error) {
+	adapter.failureMutex.Lock()
+	defer adapter.failureMutex.Unlock()
+
+	if adapter.failure == nil {
+		adapter.failure = err
 	}
 }
 
 func (adapter *DefaultConsole) ReadLines() []string {
 	lines := make([]string, 0)
 
-	for adapter.inputScanner.Scan() {
-		line := adapter.inputScanner.Text()
-
-		if strings.TrimSpace(line) == "" {
-			break
-		}
-
+	for line, ok := adapter.ReadLine(); ok; line, ok = adapter.ReadLine() {
 		lines = append(lines, line)
 	}
 
-	if scanError := adapter.inputScanner.Err(); scanError != nil {
-		panic(scanError)
+	return lines
+}
+
+func (adapter *DefaultConsole) ReadLine() (string, bool) {
+	line, readError := adapter.inputReader.ReadString('\n')
+
+	if readError != nil && (line == "" || !errors.Is(readError, io.EOF)) {
+		return "", false
 	}
 
-	return lines
+	return strings.TrimRight(line, "\r\n"), true
+}
+
+func (adapter *DefaultConsole) Input() io.Reader {
+	return adapter.inputReader
+}
+
+func (adapter *DefaultConsole) Write(text string) {
+	if _, writeError := adapter.outputWriter.WriteString(text); writeError != nil {
+		adapter.fail(writeError)
+	}
 }
 
 func (adapter *DefaultConsole) WriteLine(text string) {
 	if _, writeError := fmt.Fprintln(adapter.outputWriter, text); writeError != nil {
-		panic(writeError)
+		adapter.fail(writeError)
+		return
 	}
 
 	if flushError := adapter.outputWriter.Flush(); flushError != nil {
-		panic(flushError)
+		adapter.fail(flushError)
 	}
 }
+
+// consoleInput reads the input of the console, keeping the first error other than its end as the
+// failure of the console.
+type consoleInput struct {
+	reader  io.Reader
+	console *DefaultConsole
+}
+
+func (input *consoleInput) Read(buffer []byte) (int, error) {
+	read, err := input.reader.Read(buffer)
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		input.console.fail(err)
+	}
+
+	return read, err
+}
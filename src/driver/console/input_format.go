@@ -0,0 +1,66 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InputFormat selects how the console reads the operations from its input.
+type InputFormat string
+
+const (
+	// AutoInput reads JSON when the input starts with an array or an object, OFX when it starts
+	// with an OFX header or document, and CSV otherwise.
+	AutoInput InputFormat = "auto"
+
+	// JSONInput reads one JSON array, or object with a header, per line.
+	JSONInput InputFormat = "json"
+
+	// CSVInput reads a single CSV file with a header row, computed as one simulation.
+	CSVInput InputFormat = "csv"
+
+	// B3Input reads the trade statement exported by the B3 investor area, saved as CSV.
+	B3Input InputFormat = "b3"
+
+	// OFXInput reads the buy and sell transactions of an OFX investment statement.
+	OFXInput InputFormat = "ofx"
+
+	// NDJSONInput reads one operation object per line, calculating and writing the tax of each
+	// operation as soon as its line arrives.
+	NDJSONInput InputFormat = "ndjson"
+)
+
+// ParseInputFormat returns the input format with the given name.
+func ParseInputFormat(name string) (InputFormat, error) {
+	switch format := InputFormat(strings.ToLower(strings.TrimSpace(name))); format {
+	case AutoInput, JSONInput, CSVInput, B3Input, OFXInput, NDJSONInput:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported input format %q: expected auto, json, ndjson, csv, b3 or ofx", name)
+	}
+}
+
+// looksLikeJSON reports whether the first non-blank line starts a JSON array or object.
+func looksLikeJSON(lines []string) bool {
+	for _, line := range lines {
+		if trimmedLine := strings.TrimSpace(line); trimmedLine != "" {
+			return strings.HasPrefix(trimmedLine, "[") || strings.HasPrefix(trimmedLine, "{")
+		}
+	}
+
+	return true
+}
+
+// looksLikeOFX reports whether the first non-blank line starts an OFX header (SGML variant)
+// or an XML or OFX document.
+func looksLikeOFX(lines []string) bool {
+	for _, line := range lines {
+		if trimmedLine := strings.TrimSpace(line); trimmedLine != "" {
+			return strings.HasPrefix(trimmedLine, "OFXHEADER:") ||
+				strings.HasPrefix(trimmedLine, "<?xml") ||
+				strings.HasPrefix(strings.ToUpper(trimmedLine), "<OFX>")
+		}
+	}
+
+	return false
+}
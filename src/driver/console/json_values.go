@@ -0,0 +1,167 @@
+package console
+
+import (
+	"iter"
+	"strings"
+)
+
+// jsonValue is the text of a top-level JSON value of the input, along with the line it starts on.
+type jsonValue struct {
+	text string
+	line int
+}
+
+// jsonValues splits lines into their top-level JSON values, regardless of line breaks and blank
+// lines, without decoding them. An array or an object ends where its outermost bracket closes, and
+// anything else at the end of its line. One left open ends before the next line starting an array or
+// an object it cannot hold, so a malformed value does not take the following ones along.
+type jsonValues struct {
+	value     strings.Builder
+	startLine int
+	open      []byte
+	last      byte
+	inString  bool
+	escaped   bool
+}
+
+// jsonValuesOf returns the top-level JSON values of the given lines, numbered from the given line.
+func jsonValuesOf(lines iter.Seq[string], firstLine int) iter.Seq[jsonValue] {
+	return func(yield func(jsonValue) bool) {
+		values := new(jsonValues)
+		lineNumber := firstLine
+
+		for line := range lines {
+			for _, value := range values.split(line, lineNumber) {
+				if !yield(value) {
+					return
+				}
+			}
+
+			lineNumber++
+		}
+
+		if values.value.Len() > 0 {
+			yield(values.flush())
+		}
+	}
+}
+
+// split returns the values the given line completes, keeping the rest of the line for the next ones.
+func (values *jsonValues) split(line string, lineNumber int) []jsonValue {
+	completed := make([]jsonValue, 0, 1)
+
+	if values.value.Len() > 0 && !values.holds(line) {
+		completed = append(completed, values.flush())
+	}
+
+	segmentStart := values.start(line, 0, lineNumber)
+
+	for index := segmentStart; index < len(line); index++ {
+		if !values.closes(line[index]) {
+			continue
+		}
+
+		values.value.WriteString(line[segmentStart : index+1])
+		completed = append(completed, values.flush())
+		segmentStart = values.start(line, index+1, lineNumber)
+		index = segmentStart - 1
+	}
+
+	return append(completed, values.keep(line[segmentStart:])...)
+}
+
+// start returns where the next value of the line begins from the given index: right there when a
+// value is still open, past the blanks otherwise, the value then starting on the given line.
+func (values *jsonValues) start(line string, from int, lineNumber int) int {
+	if values.value.Len() > 0 {
+		return from
+	}
+
+	for from < len(line) && isBlank(line[from]) {
+		from++
+	}
+
+	values.startLine = lineNumber
+
+	return from
+}
+
+// keep holds the rest of the line as the beginning of the next value, returning it right away when
+// it is neither an array nor an object, since such a value ends along with its line.
+func (values *jsonValues) keep(rest string) []jsonValue {
+	values.value.WriteString(rest)
+
+	if len(values.open) == 0 && strings.TrimSpace(values.value.String()) != "" {
+		return []jsonValue{values.flush()}
+	}
+
+	if values.value.Len() > 0 {
+		values.value.WriteByte('\n')
+	}
+
+	return nil
+}
+
+// holds reports whether the open value may go on with the given line, which it cannot when the line
+// starts an array or an object where the value expects anything else: a string never spans lines,
+// an object holds them only after a key, and an array of operations holds only objects.
+func (values *jsonValues) holds(line string) bool {
+	trimmed := strings.TrimLeft(line, " \t\r\n")
+
+	if trimmed == "" || (trimmed[0] != '[' && trimmed[0] != '{') {
+		return true
+	}
+
+	if values.inString || len(values.open) == 0 {
+		return false
+	}
+
+	if values.open[len(values.open)-1] == '{' {
+		return values.last == ':'
+	}
+
+	return trimmed[0] == '{' && (values.last == '[' || values.last == ',')
+}
+
+// closes tracks the nesting of the value, reporting whether the character ends it.
+func (values *jsonValues) closes(character byte) bool {
+	switch {
+	case values.escaped:
+		values.escaped = false
+	case values.inString:
+		values.escaped = character == '\\'
+		values.inString = character != '"'
+	case character == '"':
+		values.inString = true
+	case character == '[' || character == '{':
+		values.open = append(values.open, character)
+	case character == ']' || character == '}':
+		if len(values.open) > 0 {
+			values.open = values.open[:len(values.open)-1]
+		}
+
+		return len(values.open) == 0
+	}
+
+	if !isBlank(character) {
+		values.last = character
+	}
+
+	return false
+}
+
+func (values *jsonValues) flush() jsonValue {
+	value := jsonValue{text: strings.TrimSpace(values.value.String()), line: values.startLine}
+
+	values.value.Reset()
+	values.open = values.open[:0]
+	values.last = 0
+	values.inString = false
+	values.escaped = false
+
+	return value
+}
+
+func isBlank(character byte) bool {
+	return character == ' ' || character == '\t' || character == '\r' || character == '\n'
+}
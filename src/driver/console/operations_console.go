@@ -1,52 +1,351 @@
 package console
 
 import (
+	"errors"
+	"fmt"
+	"iter"
 	"strings"
+	"sync"
 
 	"capital-gains/src/driver"
+	"capital-gains/src/driver/formatters"
+	"capital-gains/src/driver/importers"
+	"capital-gains/src/driver/parsers"
 )
 
 type OperationsConsole struct {
-	console Console
-	parser  *OperationsParser
+	console      Console
+	parser       *parsers.OperationsParser
+	strictParser *parsers.StrictOperationsParser
+	formatter    formatters.Formatter
+	settings     Settings
+	skipped      []error
+	skippedMutex sync.Mutex
 }
 
-func NewOperationsConsole(console Console) *OperationsConsole {
+func NewOperationsConsole(console Console, settings Settings) *OperationsConsole {
 	return &OperationsConsole{
-		console: console,
-		parser:  NewOperationsParser(),
+		console:      console,
+		parser:       parsers.NewOperationsParser(),
+		strictParser: parsers.NewStrictOperationsParser(),
+		formatter:    formatters.New(settings.Output, settings.Columns, settings.Locale),
+		settings:     settings,
 	}
 }
 
-func (operationsConsole *OperationsConsole) ReadRequests() []driver.Request {
-	lines := operationsConsole.console.ReadLines()
-	completePayload := strings.TrimSpace(strings.Join(lines, "\n"))
-
-	if request, ok := operationsConsole.parser.Parse(completePayload); ok {
-		return []driver.Request{request}
+// ReadRequests returns the requests of the input, one per top-level JSON value regardless of
+// line breaks and blank lines, each one read as soon as it is complete. Inputs in other formats
+// are read until EOF as a single request. A request that cannot be calculated comes with the
+// validation errors locating its problems, and the following ones are still read.
+func (operationsConsole *OperationsConsole) ReadRequests() iter.Seq2[driver.Request, driver.ValidationErrors] {
+	return func(yield func(driver.Request, driver.ValidationErrors) bool) {
+		for parse := range operationsConsole.readUnparsedRequests() {
+			if !yield(parse()) {
+				return
+			}
+		}
 	}
+}
+
+// readUnparsedRequests returns the requests of the input as ReadRequests does, each one read as soon
+// as it is complete but parsed and validated only once its function is called, so requests can be
+// parsed apart from the reading of the input.
+func (operationsConsole *OperationsConsole) readUnparsedRequests() iter.Seq[func() (driver.Request, driver.ValidationErrors)] {
+	return func(yield func(func() (driver.Request, driver.ValidationErrors)) bool) {
+		firstLine, firstLineNumber, ok := operationsConsole.readFirstNonBlankLine()
 
-	requests := make([]driver.Request, 0)
+		if !ok {
+			return
+		}
 
-	for _, line := range lines {
-		linePayload := strings.TrimSpace(line)
+		if importer, ok := operationsConsole.settings.importerOf([]string{firstLine}); ok {
+			lines := append([]string{firstLine}, operationsConsole.console.ReadLines()...)
+			yield(func() (driver.Request, driver.ValidationErrors) {
+				return operationsConsole.readImported(importer, lines, firstLineNumber)
+			})
 
-		if linePayload == "" {
-			continue
+			return
 		}
 
-		request, ok := operationsConsole.parser.Parse(linePayload)
+		for value := range jsonValuesOf(operationsConsole.linesFrom(firstLine), firstLineNumber) {
+			parse := func() (driver.Request, driver.ValidationErrors) {
+				return operationsConsole.requestOf(value)
+			}
+
+			if !yield(parse) {
+				return
+			}
+		}
+	}
+}
+
+// requestOf parses and validates the request held by a top-level JSON value.
+func (operationsConsole *OperationsConsole) requestOf(value jsonValue) (driver.Request, driver.ValidationErrors) {
+	request, validationErrors := operationsConsole.parseRequest(value.text)
+
+	if len(validationErrors) > 0 {
+		return driver.Request{}, validationErrors.WithLine(value.line)
+	}
+
+	return request, operationsConsole.validate(request, value.line)
+}
+
+// readValues returns the top-level JSON values of the input, regardless of line breaks and blank lines,
+// without parsing them, each one along with the line it starts on.
+func (operationsConsole *OperationsConsole) readValues() iter.Seq[jsonValue] {
+	return func(yield func(jsonValue) bool) {
+		firstLine, firstLineNumber, ok := operationsConsole.readFirstNonBlankLine()
 
 		if !ok {
-			panic("invalid input: expected a JSON array of operations")
+			return
 		}
 
-		requests = append(requests, request)
+		for value := range jsonValuesOf(operationsConsole.linesFrom(firstLine), firstLineNumber) {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// readFirstNonBlankLine returns the first non-blank line of the input along with its number.
+func (operationsConsole *OperationsConsole) readFirstNonBlankLine() (string, int, bool) {
+	for lineNumber := 1; ; lineNumber++ {
+		line, ok := operationsConsole.console.ReadLine()
+
+		if !ok || strings.TrimSpace(line) != "" {
+			return line, lineNumber, ok
+		}
 	}
+}
 
-	return requests
+// linesFrom returns the given line followed by the remaining lines of the input, as they arrive.
+func (operationsConsole *OperationsConsole) linesFrom(firstLine string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for line, ok := firstLine, true; ok; line, ok = operationsConsole.console.ReadLine() {
+			if !yield(line) {
+				return
+			}
+		}
+	}
 }
 
+// ReadOperations returns the operation of each non-blank line as soon as it arrives, until EOF.
+// A line that does not hold a valid operation comes with the validation errors locating its problems.
+func (operationsConsole *OperationsConsole) ReadOperations() iter.Seq2[driver.Operation, driver.ValidationErrors] {
+	return func(yield func(driver.Operation, driver.ValidationErrors) bool) {
+		for lineNumber := 1; ; lineNumber++ {
+			line, ok := operationsConsole.console.ReadLine()
+
+			if !ok {
+				return
+			}
+
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			operation, validationErrors := operationsConsole.parseOperation(line)
+
+			if len(validationErrors) == 0 {
+				validationErrors = operation.Validate()
+			}
+
+			if !yield(operation, validationErrors.WithLine(lineNumber)) {
+				return
+			}
+		}
+	}
+}
+
+// parseRequest parses a top-level JSON value, holding to the contract field by field in strict mode.
+func (operationsConsole *OperationsConsole) parseRequest(payload string) (driver.Request, driver.ValidationErrors) {
+	localized, validationErrors := operationsConsole.localize(payload)
+
+	if len(validationErrors) > 0 {
+		return driver.Request{}, validationErrors
+	}
+
+	if operationsConsole.settings.Strict {
+		return operationsConsole.strictParser.Parse(localized)
+	}
+
+	request, ok := operationsConsole.parser.Parse(localized)
+
+	if !ok {
+		return driver.Request{}, parsers.MalformedInput(payload, "expected a JSON array of operations or an object with operations")
+	}
+
+	return request, nil
+}
+
+// parseOperation parses a single operation object, holding to the contract field by field in strict mode.
+func (operationsConsole *OperationsConsole) parseOperation(payload string) (driver.Operation, driver.ValidationErrors) {
+	localized, validationErrors := operationsConsole.localize(payload)
+
+	if len(validationErrors) > 0 {
+		return driver.Operation{}, validationErrors
+	}
+
+	if operationsConsole.settings.Strict {
+		return operationsConsole.strictParser.ParseOperation(localized)
+	}
+
+	operation, ok := operationsConsole.parser.ParseOperation(localized)
+
+	if !ok {
+		return driver.Operation{}, parsers.MalformedInput(payload, "expected a JSON operation object")
+	}
+
+	return operation, nil
+}
+
+// localize rewrites the numbers given as strings in the notation of the selected locale, if any,
+// returning an error for each one that is not a number in that locale.
+func (operationsConsole *OperationsConsole) localize(payload string) (string, driver.ValidationErrors) {
+	if operationsConsole.settings.Locale.IsZero() {
+		return payload, nil
+	}
+
+	return parsers.LocalizeNumbers(payload, operationsConsole.settings.Locale)
+}
+
+// StreamOperations returns the stream of operations decoded from the input, array by array.
+func (operationsConsole *OperationsConsole) StreamOperations() *OperationsStream {
+	return NewOperationsStream(operationsConsole.console.Input(), operationsConsole.parseOperation)
+}
+
+// readImported reads the lines of a file in another format, numbering its rows from the given line.
+func (operationsConsole *OperationsConsole) readImported(
+	importer importers.Importer,
+	lines []string,
+	firstLineNumber int,
+) (driver.Request, driver.ValidationErrors) {
+	request, err := importer.Read(strings.Join(lines, "\n"))
+
+	if errors.Is(err, importers.ErrUnsupportedTransaction) {
+		operationsConsole.skip(fmt.Errorf("line %d: %w", firstLineNumber, err))
+		err = nil
+	}
+
+	if err == nil {
+		return request, operationsConsole.validate(request, firstLineNumber)
+	}
+
+	line := firstLineNumber
+
+	var rowError *importers.RowError
+
+	if errors.As(err, &rowError) {
+		line += rowError.Row - 1
+		err = rowError.Err
+	}
+
+	validationError := driver.NewValidationError(fmt.Errorf("%w: %w", driver.ErrMalformedInput, err)).WithLine(line)
+
+	return driver.Request{}, driver.ValidationErrors{validationError}
+}
+
+// skip records the entries of an imported file that were left out of the calculation, as the file
+// may be read by any of the workers.
+func (operationsConsole *OperationsConsole) skip(err error) {
+	operationsConsole.skippedMutex.Lock()
+	defer operationsConsole.skippedMutex.Unlock()
+
+	operationsConsole.skipped = append(operationsConsole.skipped, err)
+}
+
+// Skipped returns the entries of the imported files that were left out of the calculation for not
+// being operations, or nil when none was.
+func (operationsConsole *OperationsConsole) Skipped() error {
+	operationsConsole.skippedMutex.Lock()
+	defer operationsConsole.skippedMutex.Unlock()
+
+	return errors.Join(operationsConsole.skipped...)
+}
+
+// validate returns the validation errors of the request starting at the given line, none when it
+// can be calculated with the current settings.
+func (operationsConsole *OperationsConsole) validate(request driver.Request, line int) driver.ValidationErrors {
+	validationErrors := request.Validate()
+
+	if operationsConsole.settings.Chronological {
+		validationErrors = append(validationErrors, request.ValidateTradeDates()...)
+	}
+
+	return validationErrors.WithLine(line)
+}
+
+// WriteResponse writes the taxes of a simulation in the output format, nothing when it renders no line.
 func (operationsConsole *OperationsConsole) WriteResponse(response driver.Response) {
-	operationsConsole.console.WriteLine(response.ToString())
+	operationsConsole.writeFormatted(operationsConsole.formatter.FormatResponse(response))
+}
+
+func (operationsConsole *OperationsConsole) WriteTax(tax driver.Tax) {
+	operationsConsole.console.WriteLine(tax.ToString())
+}
+
+// WriteArrayStart starts an output array whose elements are written as they are calculated.
+func (operationsConsole *OperationsConsole) WriteArrayStart() {
+	operationsConsole.console.Write("[")
+}
+
+// WriteArrayElement writes the element at the given index of the output array being streamed.
+func (operationsConsole *OperationsConsole) WriteArrayElement(index int, tax driver.Tax) {
+	if index > 0 {
+		operationsConsole.console.Write(",")
+	}
+
+	operationsConsole.console.Write(tax.ToString())
+}
+
+// WriteArrayEnd ends the output array being streamed, along with its line.
+func (operationsConsole *OperationsConsole) WriteArrayEnd() {
+	operationsConsole.console.WriteLine("]")
+}
+
+// WriteValidationErrors writes the validation errors in place of the output of the simulation they belong to.
+func (operationsConsole *OperationsConsole) WriteValidationErrors(validationErrors driver.ValidationErrors) {
+	operationsConsole.writeFormatted(operationsConsole.formatter.FormatValidationErrors(validationErrors))
+}
+
+func (operationsConsole *OperationsConsole) writeFormatted(text string) {
+	if text != "" {
+		operationsConsole.console.WriteLine(text)
+	}
+}
+
+// WriteArrayInvalidElement writes the validation errors of an operation in place of its element of the
+// output array being streamed.
+func (operationsConsole *OperationsConsole) WriteArrayInvalidElement(index int, validationErrors driver.ValidationErrors) {
+	if index > 0 {
+		operationsConsole.console.Write(",")
+	}
+
+	operationsConsole.console.Write(validationErrors.ToString())
+}
+
+func (operationsConsole *OperationsConsole) WriteTaxDiffReport(report driver.TaxDiffReport) {
+	operationsConsole.console.WriteLine(report.ToString())
+}
+
+// WriteReport writes the report of a simulation in the output format, as JSON when the format does not render reports.
+func (operationsConsole *OperationsConsole) WriteReport(statement driver.Statement) {
+	if reportFormatter, ok := operationsConsole.formatter.(formatters.ReportFormatter); ok {
+		operationsConsole.writeFormatted(reportFormatter.FormatReport(statement))
+		return
+	}
+
+	operationsConsole.console.WriteLine(statement.Report.ToString())
+}
+
+// End completes the output once every simulation was written, when the output format renders a single document.
+func (operationsConsole *OperationsConsole) End() {
+	if documentFormatter, ok := operationsConsole.formatter.(formatters.DocumentFormatter); ok {
+		operationsConsole.writeFormatted(documentFormatter.End())
+	}
+}
+
+func (operationsConsole *OperationsConsole) WriteExplanation(explanation driver.Explanation) {
+	operationsConsole.console.WriteLine(explanation.ToString())
 }
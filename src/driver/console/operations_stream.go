@@ -0,0 +1,121 @@
+package console
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"capital-gains/src/driver"
+)
+
+// OperationsStream decodes JSON arrays of operations token by token, one operation at a time,
+// so neither the size of an array nor the length of a line is bounded by memory.
+type OperationsStream struct {
+	decoder        *json.Decoder
+	lines          *lineCounter
+	parseOperation func(payload string) (driver.Operation, driver.ValidationErrors)
+	index          int
+}
+
+// NewOperationsStream returns the stream of the operations of the input, each one parsed by the given function.
+func NewOperationsStream(
+	input io.Reader,
+	parseOperation func(payload string) (driver.Operation, driver.ValidationErrors),
+) *OperationsStream {
+	lines := &lineCounter{reader: input}
+
+	return &OperationsStream{decoder: json.NewDecoder(lines), lines: lines, parseOperation: parseOperation}
+}
+
+// NextArray advances to the start of the next top-level array, returning false at the end of the
+// input or, along with the error, when the input holds something else.
+func (stream *OperationsStream) NextArray() (bool, driver.ValidationErrors) {
+	token, err := stream.decoder.Token()
+
+	if errors.Is(err, io.EOF) {
+		return false, nil
+	}
+
+	if err != nil || token != json.Delim('[') {
+		return false, streamError("expected a JSON array of operations").WithLine(stream.line())
+	}
+
+	stream.index = 0
+
+	return true, nil
+}
+
+// NextOperation decodes the next operation of the current array, returning false at its end. An
+// operation that cannot be calculated is returned along with its validation errors, while an error
+// without an operation means the input is broken and cannot be read any further.
+func (stream *OperationsStream) NextOperation() (driver.Operation, bool, driver.ValidationErrors) {
+	if !stream.decoder.More() {
+		if _, err := stream.decoder.Token(); err != nil {
+			return driver.Operation{}, false, streamError("expected the end of the JSON array of operations").WithLine(stream.line())
+		}
+
+		return driver.Operation{}, false, nil
+	}
+
+	var element json.RawMessage
+
+	if err := stream.decoder.Decode(&element); err != nil {
+		return driver.Operation{}, false, streamError("expected a JSON operation object").WithLine(stream.line()).WithIndex(stream.index)
+	}
+
+	line := stream.lines.lineAt(stream.decoder.InputOffset() - int64(len(element)))
+
+	operation, validationErrors := stream.parseOperation(string(element))
+
+	if len(validationErrors) == 0 {
+		validationErrors = operation.Validate()
+	}
+
+	index := stream.index
+	stream.index++
+
+	return operation, true, validationErrors.WithLine(line).WithIndex(index)
+}
+
+// line returns the line the decoder stopped reading at.
+func (stream *OperationsStream) line() int {
+	return stream.lines.lineAt(stream.decoder.InputOffset())
+}
+
+func streamError(expected string) driver.ValidationErrors {
+	return driver.ValidationErrors{driver.NewValidationError(fmt.Errorf("%w: %s", driver.ErrMalformedInput, expected))}
+}
+
+// lineCounter reads the input while keeping the offsets of the line breaks not yet located, so the
+// line of an offset is found without keeping the lines themselves.
+type lineCounter struct {
+	reader io.Reader
+	read   int64
+	breaks []int64
+	passed int
+}
+
+func (counter *lineCounter) Read(buffer []byte) (int, error) {
+	read, err := counter.reader.Read(buffer)
+
+	for index, character := range buffer[:read] {
+		if character == '\n' {
+			counter.breaks = append(counter.breaks, counter.read+int64(index))
+		}
+	}
+
+	counter.read += int64(read)
+
+	return read, err
+}
+
+// lineAt returns the line of the given offset, counted from 1, offsets being located in input order.
+func (counter *lineCounter) lineAt(offset int64) int {
+	for len(counter.breaks) > 0 && counter.breaks[0] < offset {
+		counter.breaks = counter.breaks[1:]
+		counter.passed++
+	}
+
+	return counter.passed + 1
+}
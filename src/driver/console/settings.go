@@ -0,0 +1,213 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"capital-gains/src/driver/formatters"
+	"capital-gains/src/driver/importers"
+	"capital-gains/src/driver/locales"
+)
+
+// ErrIncompatibleSettings is returned when the selected behaviors cannot be combined.
+var ErrIncompatibleSettings = errors.New("incompatible settings")
+
+// Settings holds the opt-in behaviors of the console, selected by command-line flags.
+// The zero value keeps the default behavior.
+type Settings struct {
+	// Chronological applies the operations of each line in the order they were traded,
+	// instead of the order they were given.
+	Chronological bool
+
+	// Explain writes the step-by-step calculation of each line instead of the tax array.
+	Explain bool
+
+	// Report writes the tax due per month of each line instead of the tax array.
+	Report bool
+
+	// Summary wraps the taxes of each line along with the summary of the simulation: its totals and
+	// its final position.
+	Summary bool
+
+	// Stream reads JSON arrays of operations of any size as a stream, applying each operation as
+	// it is decoded and writing the output array as it goes.
+	Stream bool
+
+	// Strict rejects JSON input breaking the contract that the default mode tolerates: unknown fields,
+	// absent required fields and numbers that cannot be read without loss. Numbers may be given as strings.
+	Strict bool
+
+	// Workers calculates up to that many lines at the same time, writing their output in the order of
+	// the input. The zero value, like one, calculates them one at a time.
+	Workers int
+
+	// Input selects how the operations are read. The zero value detects the format.
+	Input InputFormat
+
+	// Output selects how the taxes of each line are rendered. The zero value renders JSON.
+	Output formatters.Format
+
+	// Columns are the optional columns shown next to the tax of each operation.
+	Columns []formatters.Column
+
+	// Locale reads the numbers given as strings in JSON input, and the cells and numbers of CSV input
+	// read without a profile, in its notation, and writes the amounts of the text output formats in it.
+	// The zero value keeps the dot-decimal notation of the JSON contract.
+	Locale locales.Locale
+
+	// Profile maps the columns of a third-party CSV export onto the fields of an operation.
+	// When nil, CSV columns must be named after the fields.
+	Profile *importers.Profile
+}
+
+// Validate reports settings that cannot be combined: streamed input is calculated one operation
+// at a time, so it can be neither reordered, explained nor reported.
+func (settings Settings) Validate() error {
+	streamed := settings.Stream || settings.Input == NDJSONInput
+
+	if err := settings.validateStreaming(streamed); err != nil {
+		return err
+	}
+
+	if settings.Explain && settings.Report {
+		return fmt.Errorf("%w: a line is either explained or reported", ErrIncompatibleSettings)
+	}
+
+	if err := settings.validateStrict(); err != nil {
+		return err
+	}
+
+	if err := settings.validateWorkers(streamed); err != nil {
+		return err
+	}
+
+	return settings.validateOutput(streamed)
+}
+
+// validateStreaming reports modes streamed input cannot be calculated in: they need the whole input,
+// and only JSON input can be streamed as arrays.
+func (settings Settings) validateStreaming(streamed bool) error {
+	if streamed && (settings.Chronological || settings.Explain || settings.Report) {
+		return fmt.Errorf("%w: chronological, explain and report modes need the whole input, which streaming does not keep", ErrIncompatibleSettings)
+	}
+
+	if settings.Stream && !settings.readsJSONValues() {
+		return fmt.Errorf("%w: only JSON input can be streamed as arrays", ErrIncompatibleSettings)
+	}
+
+	return nil
+}
+
+// validateStrict reports strict mode for input other than JSON, whose formats have contracts of their own.
+func (settings Settings) validateStrict() error {
+	if settings.Strict && !settings.readsJSONValues() && settings.Input != NDJSONInput {
+		return fmt.Errorf("%w: strict mode holds JSON input to the contract, and other formats have their own", ErrIncompatibleSettings)
+	}
+
+	return nil
+}
+
+// validateWorkers reports worker counts that cannot be used: streamed input is a single simulation
+// whose operations depend on the ones before them, so it is calculated by a single worker.
+func (settings Settings) validateWorkers(streamed bool) error {
+	if settings.Workers < 0 {
+		return fmt.Errorf("%w: the number of workers cannot be negative", ErrIncompatibleSettings)
+	}
+
+	if streamed && settings.Workers > 1 {
+		return fmt.Errorf("%w: streamed input is calculated one operation at a time, by a single worker", ErrIncompatibleSettings)
+	}
+
+	return nil
+}
+
+// validateOutput reports output settings that cannot be combined: only the tax output of whole
+// lines can be rendered in other formats, with optional columns or with a summary, while reports
+// are also rendered as HTML.
+func (settings Settings) validateOutput(streamed bool) error {
+	formatted := (settings.Output != "" && settings.Output != formatters.JSONFormat) || len(settings.Columns) > 0
+
+	if formatted && streamed {
+		return fmt.Errorf("%w: streamed taxes are written as JSON, without optional columns", ErrIncompatibleSettings)
+	}
+
+	if formatted && settings.Explain {
+		return fmt.Errorf("%w: explanations are written as JSON, without optional columns", ErrIncompatibleSettings)
+	}
+
+	if settings.Report && !settings.rendersReport() {
+		return fmt.Errorf("%w: reports are written as JSON or HTML, without optional columns", ErrIncompatibleSettings)
+	}
+
+	return settings.validateSummary(streamed)
+}
+
+// rendersReport tells whether the output format renders reports, without optional columns.
+func (settings Settings) rendersReport() bool {
+	return len(settings.Columns) == 0 && slices.Contains([]formatters.Format{"", formatters.JSONFormat, formatters.HTMLFormat}, settings.Output)
+}
+
+// validateSummary reports summaries that cannot be written: the summary wraps the JSON tax output of whole lines.
+func (settings Settings) validateSummary(streamed bool) error {
+	if !settings.Summary {
+		return nil
+	}
+
+	if streamed || settings.Explain || settings.Report {
+		return fmt.Errorf("%w: the summary wraps the taxes of whole lines", ErrIncompatibleSettings)
+	}
+
+	if settings.Output != "" && settings.Output != formatters.JSONFormat {
+		return fmt.Errorf("%w: the summary is written as a JSON wrapper object", ErrIncompatibleSettings)
+	}
+
+	return nil
+}
+
+// readsJSONValues tells whether the input is read as a sequence of JSON values, which auto-detection
+// also chooses for input starting with a JSON array or object.
+func (settings Settings) readsJSONValues() bool {
+	return settings.Input == "" || settings.Input == AutoInput || settings.Input == JSONInput
+}
+
+// importerOf returns the importer reading the given lines, or false when they must be read as JSON.
+func (settings Settings) importerOf(lines []string) (importers.Importer, bool) {
+	switch settings.Input {
+	case B3Input:
+		return importers.NewB3Reader(), true
+	case OFXInput:
+		return importers.NewOFXReader(), true
+	case CSVInput:
+		return settings.csvReader(), true
+	case JSONInput:
+		return nil, false
+	default:
+		if looksLikeJSON(lines) {
+			return nil, false
+		}
+
+		if looksLikeOFX(lines) {
+			return importers.NewOFXReader(), true
+		}
+
+		return settings.csvReader(), true
+	}
+}
+
+func (settings Settings) csvReader() *importers.CSVReader {
+	if settings.Profile == nil && !settings.Locale.IsZero() {
+		profile := importers.DefaultProfile()
+		profile.Delimiter = settings.Locale.ListSeparator()
+		profile.DecimalSeparator = settings.Locale.DecimalSeparator()
+		profile.ThousandsSeparator = settings.Locale.ThousandsSeparator()
+
+		return importers.NewCSVReaderWith(profile)
+	}
+
+	if settings.Profile == nil {
+		return importers.NewCSVReader()
+	}
+
+	return importers.NewCSVReaderWith(*settings.Profile)
+}
@@ -0,0 +1,153 @@
+package console_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driver/console"
+	"capital-gains/src/driver/formatters"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettingsValidateAcceptsDefaultSettings(t *testing.T) {
+	t.Parallel()
+
+	// Given the default settings
+	settings := console.Settings{}
+
+	// When validating them
+	err := settings.Validate()
+
+	// Then I expect no error
+	assert.NoError(t, err)
+}
+
+func TestSettingsValidateRejectsStreamingCombinedWithExplain(t *testing.T) {
+	t.Parallel()
+
+	// Given settings streaming the input while explaining the calculation
+	settings := console.Settings{Stream: true, Explain: true}
+
+	// When validating them
+	err := settings.Validate()
+
+	// Then I expect the combination to be rejected
+	assert.ErrorIs(t, err, console.ErrIncompatibleSettings)
+}
+
+func TestSettingsValidateRejectsStreamingNonJSONInput(t *testing.T) {
+	t.Parallel()
+
+	// Given settings streaming a CSV input
+	settings := console.Settings{Stream: true, Input: console.CSVInput}
+
+	// When validating them
+	err := settings.Validate()
+
+	// Then I expect the combination to be rejected
+	assert.ErrorIs(t, err, console.ErrIncompatibleSettings)
+}
+
+func TestSettingsValidateRejectsStrictModeForImportedInput(t *testing.T) {
+	t.Parallel()
+
+	// Given settings holding a B3 statement to the JSON contract
+	settings := console.Settings{Strict: true, Input: console.B3Input}
+
+	// When validating them
+	err := settings.Validate()
+
+	// Then I expect the combination to be rejected
+	assert.ErrorIs(t, err, console.ErrIncompatibleSettings)
+}
+
+func TestSettingsValidateRejectsFormattedOutputOfStreamedInput(t *testing.T) {
+	t.Parallel()
+
+	// Given settings rendering the taxes of an NDJSON input as a table
+	settings := console.Settings{Input: console.NDJSONInput, Output: formatters.TableFormat}
+
+	// When validating them
+	err := settings.Validate()
+
+	// Then I expect the combination to be rejected
+	assert.ErrorIs(t, err, console.ErrIncompatibleSettings)
+}
+
+func TestSettingsValidateRejectsOptionalColumnsOfExplanations(t *testing.T) {
+	t.Parallel()
+
+	// Given settings explaining the calculation with optional columns
+	settings := console.Settings{Explain: true, Columns: []formatters.Column{formatters.GainColumn}}
+
+	// When validating them
+	err := settings.Validate()
+
+	// Then I expect the combination to be rejected
+	assert.ErrorIs(t, err, console.ErrIncompatibleSettings)
+}
+
+func TestSettingsValidateRejectsSummaryOfStreamedInput(t *testing.T) {
+	t.Parallel()
+
+	// Given settings summarizing a JSON input read as a stream
+	settings := console.Settings{Stream: true, Summary: true}
+
+	// When validating them
+	err := settings.Validate()
+
+	// Then I expect the combination to be rejected
+	assert.ErrorIs(t, err, console.ErrIncompatibleSettings)
+}
+
+func TestSettingsValidateRejectsSummaryInTextFormats(t *testing.T) {
+	t.Parallel()
+
+	// Given settings summarizing the taxes rendered as CSV
+	settings := console.Settings{Summary: true, Output: formatters.CSVFormat}
+
+	// When validating them
+	err := settings.Validate()
+
+	// Then I expect the combination to be rejected
+	assert.ErrorIs(t, err, console.ErrIncompatibleSettings)
+}
+
+func TestSettingsValidateRejectsReportsInTextFormats(t *testing.T) {
+	t.Parallel()
+
+	// Given settings rendering the monthly report as a table
+	settings := console.Settings{Report: true, Output: formatters.TableFormat}
+
+	// When validating them
+	err := settings.Validate()
+
+	// Then I expect the combination to be rejected
+	assert.ErrorIs(t, err, console.ErrIncompatibleSettings)
+}
+
+func TestSettingsValidateAcceptsReportsInHTML(t *testing.T) {
+	t.Parallel()
+
+	// Given settings rendering the monthly report as an HTML document
+	settings := console.Settings{Report: true, Output: formatters.HTMLFormat}
+
+	// When validating them
+	err := settings.Validate()
+
+	// Then I expect the combination to be accepted
+	assert.NoError(t, err)
+}
+
+func TestSettingsValidateRejectsSeveralWorkersForStreamedInput(t *testing.T) {
+	t.Parallel()
+
+	// Given settings calculating NDJSON input with several workers
+	settings := console.Settings{Input: console.NDJSONInput, Workers: 4}
+
+	// When validating them
+	err := settings.Validate()
+
+	// Then I expect the combination to be rejected
+	assert.ErrorIs(t, err, console.ErrIncompatibleSettings)
+}
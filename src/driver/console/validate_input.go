@@ -0,0 +1,82 @@
+package console
+
+import (
+	"fmt"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/parsers"
+	"capital-gains/src/driver/schemas"
+)
+
+// ValidateInput checks every top-level JSON value of the input against the published request schema
+// without calculating any tax, writing all the violations of each value as its output line.
+type ValidateInput struct {
+	settings          Settings
+	operationsConsole *OperationsConsole
+}
+
+func NewValidateInput(console Console, settings Settings) *ValidateInput {
+	return &ValidateInput{
+		settings:          settings,
+		operationsConsole: NewOperationsConsole(console, settings),
+	}
+}
+
+// Handle validates the whole input, returning ErrRejectedInput when any of its values breaks the schema.
+func (validateInput *ValidateInput) Handle() error {
+	schema := schemas.Request()
+
+	if validateInput.settings.Input == NDJSONInput {
+		schema = schemas.OperationSchema()
+	}
+
+	rejected := 0
+
+	for value := range validateInput.operationsConsole.readValues() {
+		validationErrors := violationsOf(schema, value.text).WithLine(value.line)
+
+		if len(validationErrors) > 0 {
+			rejected++
+		}
+
+		validateInput.operationsConsole.WriteValidationErrors(validationErrors)
+	}
+
+	if rejected > 0 {
+		return fmt.Errorf("%w: %d of the values break the schema", ErrRejectedInput, rejected)
+	}
+
+	return nil
+}
+
+// violationsOf returns the violations of the schema as validation errors, locating them by operation
+// index and field as the calculation does.
+func violationsOf(schema schemas.Schema, payload string) driver.ValidationErrors {
+	violations, err := schema.Validate([]byte(payload))
+
+	if err != nil {
+		return parsers.MalformedInput(payload, "not valid JSON")
+	}
+
+	validationErrors := make(driver.ValidationErrors, 0, len(violations))
+
+	for _, violation := range violations {
+		validationError := driver.NewValidationError(fmt.Errorf("%w: %s", driver.ErrSchemaViolation, violation.Message))
+		path := violation.Path
+
+		if len(path) >= 2 && path[0] == "operations" {
+			path = path[1:]
+		}
+
+		if len(path) > 0 {
+			if index, ok := path[0].(int); ok {
+				validationError = validationError.WithIndex(index)
+				path = path[1:]
+			}
+		}
+
+		validationErrors = append(validationErrors, validationError.WithField(schemas.FieldOf(path)))
+	}
+
+	return validationErrors
+}
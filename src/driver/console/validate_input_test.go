@@ -0,0 +1,59 @@
+package console_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driver/console"
+	"capital-gains/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateInputWritesTheViolationsOfEachValue(t *testing.T) {
+	t.Parallel()
+
+	// Given a valid array, a pretty-printed array with two violations, and broken JSON
+	defaultConsole := test.NewConsoleMock([]string{
+		`[{"operation":"buy","unit-cost":10.00,"quantity":100}]`,
+		``,
+		`[`,
+		`  {"operation":"buy","unit-cost":10.00,"quantity":0,"unitcost":10.00}`,
+		`]`,
+		`[oops`,
+	})
+
+	// When validating the input
+	validateInput := console.NewValidateInput(defaultConsole, console.Settings{})
+	err := validateInput.Handle()
+
+	// Then I expect one output line per value, listing every violation located by line, index and field
+	expected := []string{
+		`{"errors":[]}`,
+		`{"errors":[` +
+			`{"line":3,"index":0,"field":"quantity","code":"schema-violation","message":"schema violation: must be greater than 0"},` +
+			`{"line":3,"index":0,"field":"unitcost","code":"schema-violation","message":"schema violation: is not allowed"}]}`,
+		`{"errors":[{"line":6,"code":"malformed-input","message":"malformed input: not valid JSON"}]}`,
+	}
+	assert.Equal(t, expected, defaultConsole.WrittenLines())
+
+	// And the input to be reported as rejected
+	assert.ErrorIs(t, err, console.ErrRejectedInput)
+}
+
+func TestValidateInputChecksEachNDJSONLineAsAnOperation(t *testing.T) {
+	t.Parallel()
+
+	// Given an NDJSON input whose operations are valid
+	defaultConsole := test.NewConsoleMock([]string{
+		`{"operation":"buy","unit-cost":10.00,"quantity":100}`,
+		`{"operation":"sell","unit-cost":20.00,"quantity":100,"date":"2024-01-10"}`,
+	})
+
+	// When validating the input
+	validateInput := console.NewValidateInput(defaultConsole, console.Settings{Input: console.NDJSONInput})
+	err := validateInput.Handle()
+
+	// Then I expect no violation for any of the lines
+	assert.Equal(t, []string{`{"errors":[]}`, `{"errors":[]}`}, defaultConsole.WrittenLines())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,81 @@
+package driver
+
+import (
+	"fmt"
+	"strings"
+
+	"capital-gains/src/application/commands"
+)
+
+const (
+	amendCorrectionName  = "amend"
+	cancelCorrectionName = "cancel"
+)
+
+// Correction is a retroactive change to an operation of the same input,
+// identified by the operation id.
+type Correction struct {
+	Action      string     `json:"action"`
+	ID          string     `json:"id"`
+	Replacement *Operation `json:"replacement,omitempty"`
+}
+
+// ToCommand returns the command registering the correction, or the validation error of a correction
+// that is neither an amend with its replacement nor a cancel.
+func (correction Correction) ToCommand() (commands.Command, error) {
+	normalizedActionName := strings.ToLower(strings.TrimSpace(correction.Action))
+
+	switch normalizedActionName {
+	case amendCorrectionName:
+		if correction.Replacement == nil {
+			return nil, fieldError("replacement", ErrMissingField, "required to amend an operation")
+		}
+
+		replacement := *correction.Replacement
+
+		if replacement.ID == "" {
+			replacement.ID = correction.ID
+		}
+
+		command, err := replacement.ToCommand()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return commands.NewAmendOperation(correction.ID, command), nil
+	case cancelCorrectionName:
+		return commands.NewCancelOperation(correction.ID), nil
+	default:
+		return nil, fieldError("action", ErrUnsupportedCorrection, fmt.Sprintf("%q is neither amend nor cancel", correction.Action))
+	}
+}
+
+// Validate returns the problems that keep the correction from being applied, all of them at once,
+// given the ids of the operations of the same input.
+func (correction Correction) Validate(operationIDs map[string]bool) ValidationErrors {
+	validationErrors := make(ValidationErrors, 0)
+
+	switch strings.ToLower(strings.TrimSpace(correction.Action)) {
+	case amendCorrectionName:
+		if correction.Replacement == nil {
+			validationErrors = append(validationErrors, fieldError("replacement", ErrMissingField, "required to amend an operation"))
+		} else {
+			validationErrors = append(validationErrors, correction.Replacement.Validate().Within("replacement")...)
+		}
+	case cancelCorrectionName:
+	case "":
+		validationErrors = append(validationErrors, fieldError("action", ErrMissingField, "expected amend or cancel"))
+	default:
+		validationErrors = append(validationErrors, fieldError("action", ErrUnsupportedCorrection, fmt.Sprintf("%q is neither amend nor cancel", correction.Action)))
+	}
+
+	switch {
+	case correction.ID == "":
+		validationErrors = append(validationErrors, fieldError("id", ErrMissingField, "required to find the operation to correct"))
+	case !operationIDs[correction.ID]:
+		validationErrors = append(validationErrors, fieldError("id", ErrUnknownOperationID, fmt.Sprintf("no operation has the id %q", correction.ID)))
+	}
+
+	return validationErrors
+}
@@ -0,0 +1,123 @@
+package driver
+
+import (
+	"encoding/json"
+	"time"
+
+	"capital-gains/src/application/domain/models"
+)
+
+type PositionExplanation struct {
+	Quantity        int    `json:"quantity"`
+	AverageUnitCost Amount `json:"average-unit-cost"`
+	AccumulatedLoss Amount `json:"accumulated-loss"`
+}
+
+// OperationExplanation details how a single operation was applied: the gain it realized,
+// the accumulated loss deducted from it, its tax and the resulting position.
+type OperationExplanation struct {
+	Index        int                 `json:"index"`
+	Account      string              `json:"account,omitempty"`
+	ID           string              `json:"id,omitempty"`
+	Date         string              `json:"date,omitempty"`
+	Ticker       string              `json:"ticker,omitempty"`
+	Operation    string              `json:"operation"`
+	Quantity     int                 `json:"quantity"`
+	UnitCost     Amount              `json:"unit-cost"`
+	Fees         Amount              `json:"fees,omitempty"`
+	AppliedAt    int                 `json:"applied-at"`
+	Gain         Amount              `json:"gain"`
+	DeductedLoss Amount              `json:"deducted-loss"`
+	Tax          Amount              `json:"tax"`
+	Position     PositionExplanation `json:"position"`
+}
+
+// Reordering reports an operation applied at a different position than it was given in.
+type Reordering struct {
+	Index     int    `json:"index"`
+	Account   string `json:"account,omitempty"`
+	ID        string `json:"id,omitempty"`
+	AppliedAt int    `json:"applied-at"`
+}
+
+// Explanation is the explain output of an input line: the step-by-step calculation of
+// every operation, in input order within each account, and the operations that were reordered.
+type Explanation struct {
+	Operations []OperationExplanation `json:"operations"`
+	Reordered  []Reordering           `json:"reordered"`
+}
+
+func NewExplanation(capitalGains []models.CapitalGain) Explanation {
+	explanation := Explanation{
+		Operations: make([]OperationExplanation, 0),
+		Reordered:  make([]Reordering, 0),
+	}
+
+	for _, capitalGain := range capitalGains {
+		offset := len(explanation.Operations)
+		taxEvents := capitalGain.Events()
+
+		for _, taxEvent := range taxEvents {
+			trade := taxEvent.Trade()
+			position := taxEvent.Position()
+
+			explanation.Operations = append(explanation.Operations, OperationExplanation{
+				Index:        len(explanation.Operations),
+				Account:      capitalGain.Account(),
+				ID:           trade.ID(),
+				Date:         formatTradedAt(trade.TradedAt(), trade.IsTimed()),
+				Ticker:       trade.Ticker(),
+				Operation:    trade.Side(),
+				Quantity:     trade.Quantity(),
+				UnitCost:     Amount(trade.UnitCost()),
+				Fees:         Amount(trade.Fees()),
+				Gain:         Amount(taxEvent.Realization().Gain()),
+				DeductedLoss: Amount(taxEvent.Realization().DeductedLoss()),
+				Tax:          Amount(taxEvent.Amount()),
+				Position: PositionExplanation{
+					Quantity:        position.Quantity(),
+					AverageUnitCost: Amount(position.AverageUnitCost()),
+					AccumulatedLoss: Amount(position.AccumulatedLoss()),
+				},
+			})
+		}
+
+		for appliedAt, index := range capitalGain.AppliedOrder() {
+			explained := &explanation.Operations[offset+index]
+			explained.AppliedAt = offset + appliedAt
+
+			if explained.AppliedAt != explained.Index {
+				explanation.Reordered = append(explanation.Reordered, Reordering{
+					Index:     explained.Index,
+					Account:   explained.Account,
+					ID:        explained.ID,
+					AppliedAt: explained.AppliedAt,
+				})
+			}
+		}
+	}
+
+	return explanation
+}
+
+func (explanation Explanation) ToString() string {
+	serializedExplanation, err := json.Marshal(explanation)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return string(serializedExplanation)
+}
+
+func formatTradedAt(tradedAt time.Time, timed bool) string {
+	if tradedAt.IsZero() {
+		return ""
+	}
+
+	if !timed {
+		return tradedAt.Format(DateLayout)
+	}
+
+	return tradedAt.Format(DateLayout + " " + TimeLayout)
+}
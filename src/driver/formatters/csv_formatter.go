@@ -0,0 +1,77 @@
+package formatters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/locales"
+)
+
+// CSVFormatter renders the whole output as a single CSV file: the header first, then a row per
+// operation numbered by its simulation, and a row per validation error of the rejected ones.
+type CSVFormatter struct {
+	columns     []Column
+	simulations int
+}
+
+func NewCSVFormatter(columns []Column) *CSVFormatter {
+	return &CSVFormatter{columns: columns}
+}
+
+func (formatter *CSVFormatter) FormatResponse(response driver.Response) string {
+	rows := make([][]string, 0)
+
+	for _, account := range response.Accounts() {
+		for _, operation := range account.Operations {
+			row := []string{operation.Account, operation.ID}
+
+			for _, column := range formatter.columns {
+				row = append(row, column.text(operation, locales.Locale{}))
+			}
+
+			rows = append(rows, append(row, formatAmount(operation.Tax), ""))
+		}
+	}
+
+	return formatter.render(rows)
+}
+
+func (formatter *CSVFormatter) FormatValidationErrors(validationErrors driver.ValidationErrors) string {
+	rows := make([][]string, 0, len(validationErrors))
+
+	for _, validationError := range validationErrors {
+		row := make([]string, len(formatter.columns)+4)
+		row[len(row)-1] = validationError.Error()
+		rows = append(rows, row)
+	}
+
+	return formatter.render(rows)
+}
+
+// render writes the rows of the next simulation, preceded by the header when it is the first one.
+func (formatter *CSVFormatter) render(rows [][]string) string {
+	var text strings.Builder
+
+	writer := csv.NewWriter(&text)
+	formatter.simulations++
+
+	if formatter.simulations == 1 {
+		header := []string{"simulation", "account", "id"}
+
+		for _, column := range formatter.columns {
+			header = append(header, column.key())
+		}
+
+		_ = writer.Write(append(header, "tax", "error"))
+	}
+
+	for _, row := range rows {
+		_ = writer.Write(append([]string{fmt.Sprint(formatter.simulations)}, row...))
+	}
+
+	writer.Flush()
+
+	return strings.TrimSuffix(text.String(), "\n")
+}
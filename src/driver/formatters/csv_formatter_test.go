@@ -0,0 +1,41 @@
+package formatters_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driver/formatters"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVFormatterGivenSeveralSimulationsWhenFormattedThenHeaderIsWrittenOnce(t *testing.T) {
+	t.Parallel()
+
+	// Given a CSV formatter with an optional column
+	formatter := formatters.NewCSVFormatter([]formatters.Column{formatters.PriceColumn})
+
+	// When I format a simulation and the validation errors of the next one
+	first := formatter.FormatResponse(newAccountResponse())
+	second := formatter.FormatValidationErrors(newValidationErrors())
+
+	// Then I expect the header only before the first one, and the rows numbered by simulation
+	assert.Equal(t, "simulation,account,id,unit-cost,tax,error\n1,alice,buy-1,10.00,0.00,", first)
+	assert.Equal(t, `2,,,,,"line 2, operation 0, quantity: missing field"`, second)
+}
+
+func TestCSVFormatterGivenSimulationWhenFormattedThenARowPerOperationFollowsTheHeader(t *testing.T) {
+	t.Parallel()
+
+	// Given a CSV formatter without optional columns
+	formatter := formatters.NewCSVFormatter(nil)
+
+	// When I format the taxes of a simulation
+	output := formatter.FormatResponse(newResponse())
+
+	// Then I expect a row per operation after the header
+	expected := "simulation,account,id,tax,error\n" +
+		"1,,,0.00,\n" +
+		"1,,,10000.00,\n" +
+		"1,,,0.00,"
+	assert.Equal(t, expected, output)
+}
@@ -0,0 +1,202 @@
+package formatters
+
+import (
+	"fmt"
+	"strings"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/locales"
+)
+
+// Format selects how the output of each simulation is rendered.
+type Format string
+
+const (
+	// JSONFormat renders the JSON contract, one value per simulation.
+	JSONFormat Format = "json"
+
+	// TableFormat renders a table aligned for the terminal per simulation.
+	TableFormat Format = "table"
+
+	// CSVFormat renders a single CSV file, numbering the rows of each simulation.
+	CSVFormat Format = "csv"
+
+	// MarkdownFormat renders a Markdown table per simulation.
+	MarkdownFormat Format = "markdown"
+
+	// HTMLFormat renders a single self-contained HTML document, with a section per simulation.
+	HTMLFormat Format = "html"
+)
+
+// Column is an optional column of the output, shown next to the tax of each operation.
+type Column string
+
+const (
+	// OperationColumn is the side of the operation, buy or sell.
+	OperationColumn Column = "operation"
+
+	// QuantityColumn is the quantity traded.
+	QuantityColumn Column = "quantity"
+
+	// PriceColumn is the unit cost the operation was traded at.
+	PriceColumn Column = "price"
+
+	// GainColumn is the gain realized by the operation, negative for a loss.
+	GainColumn Column = "gain"
+
+	// RemainingLossColumn is the accumulated loss left to be deducted after the operation.
+	RemainingLossColumn Column = "remaining-loss"
+)
+
+// Formatter renders the output of each simulation, in the order they were calculated.
+type Formatter interface {
+	// FormatResponse renders the taxes of a simulation.
+	//
+	// [param]  response driver.Response   taxes of the operations of the simulation.
+	// [return] string                     text written as the output of the simulation.
+	FormatResponse(response driver.Response) string
+
+	// FormatValidationErrors renders the validation errors of a simulation that was not calculated.
+	//
+	// [param]  validationErrors driver.ValidationErrors   problems found in the simulation.
+	// [return] string                                     text written in place of the output of the simulation.
+	FormatValidationErrors(validationErrors driver.ValidationErrors) string
+}
+
+// ReportFormatter is a Formatter that also renders the report of each simulation.
+type ReportFormatter interface {
+	Formatter
+
+	// FormatReport renders the report of a simulation.
+	//
+	// [param]  statement driver.Statement   calculation of every operation and tax due per month of the simulation.
+	// [return] string                       text written as the output of the simulation.
+	FormatReport(statement driver.Statement) string
+}
+
+// DocumentFormatter is a Formatter rendering the output of every simulation into a single document,
+// which is only complete once the last simulation was rendered.
+type DocumentFormatter interface {
+	Formatter
+
+	// End completes the document.
+	//
+	// [return] string   text written after the output of the last simulation.
+	End() string
+}
+
+var (
+	_ ReportFormatter   = (*JSONFormatter)(nil)
+	_ Formatter         = (*TableFormatter)(nil)
+	_ Formatter         = (*CSVFormatter)(nil)
+	_ Formatter         = (*MarkdownFormatter)(nil)
+	_ ReportFormatter   = (*HTMLFormatter)(nil)
+	_ DocumentFormatter = (*HTMLFormatter)(nil)
+)
+
+// ParseFormat returns the output format with the given name.
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(name))); format {
+	case JSONFormat, TableFormat, CSVFormat, MarkdownFormat, HTMLFormat:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q: expected json, table, csv, markdown or html", name)
+	}
+}
+
+// ParseColumns returns the optional columns named in the comma-separated list, in the given order.
+func ParseColumns(names string) ([]Column, error) {
+	columns := make([]Column, 0)
+
+	for name := range strings.SplitSeq(names, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+
+		switch column := Column(strings.ToLower(strings.TrimSpace(name))); column {
+		case OperationColumn, QuantityColumn, PriceColumn, GainColumn, RemainingLossColumn:
+			columns = append(columns, column)
+		default:
+			return nil, fmt.Errorf("unsupported column %q: expected operation, quantity, price, gain or remaining-loss", name)
+		}
+	}
+
+	return columns, nil
+}
+
+// New returns the formatter of the given format, showing the given optional columns. The zero
+// format renders JSON. The human-readable formats, table, Markdown and HTML, write numbers in the
+// notation of the locale, while JSON and CSV keep the dot-decimal notation of the contract.
+func New(format Format, columns []Column, locale locales.Locale) Formatter {
+	switch format {
+	case TableFormat:
+		return NewTableFormatter(columns, locale)
+	case CSVFormat:
+		return NewCSVFormatter(columns)
+	case MarkdownFormat:
+		return NewMarkdownFormatter(columns, locale)
+	case HTMLFormat:
+		return NewHTMLFormatter(columns, locale)
+	default:
+		return NewJSONFormatter(columns)
+	}
+}
+
+// header returns the title of the column in human-readable outputs.
+func (column Column) header() string {
+	switch column {
+	case PriceColumn:
+		return "Price"
+	case RemainingLossColumn:
+		return "Remaining loss"
+	default:
+		return strings.ToUpper(string(column[:1])) + string(column[1:])
+	}
+}
+
+// key returns the name of the column in JSON and CSV outputs, the one of the input field it echoes when there is one.
+func (column Column) key() string {
+	if column == PriceColumn {
+		return "unit-cost"
+	}
+
+	return string(column)
+}
+
+// value returns the value of the column for the operation, as it is serialized to JSON.
+func (column Column) value(operation driver.OperationTax) any {
+	switch column {
+	case OperationColumn:
+		return operation.Operation
+	case QuantityColumn:
+		return operation.Quantity
+	case PriceColumn:
+		return operation.UnitCost
+	case GainColumn:
+		return operation.Gain
+	default:
+		return operation.RemainingLoss
+	}
+}
+
+// text returns the value of the column for the operation, as it is written to text outputs in
+// the notation of the locale.
+func (column Column) text(operation driver.OperationTax, locale locales.Locale) string {
+	switch value := column.value(operation).(type) {
+	case driver.Amount:
+		return locale.FormatAmount(float64(value))
+	case int:
+		return locale.FormatNumber(float64(value), 0)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// numeric tells whether the values of the column are numbers, aligned to the right in text outputs.
+func (column Column) numeric() bool {
+	return column != OperationColumn
+}
+
+func formatAmount(amount driver.Amount) string {
+	return locales.Locale{}.FormatAmount(float64(amount))
+}
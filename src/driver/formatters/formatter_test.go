@@ -0,0 +1,108 @@
+package formatters_test
+
+import (
+	"testing"
+
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/formatters"
+	"capital-gains/src/driver/locales"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var allColumns = []formatters.Column{
+	formatters.OperationColumn,
+	formatters.QuantityColumn,
+	formatters.PriceColumn,
+	formatters.GainColumn,
+	formatters.RemainingLossColumn,
+}
+
+// newResponse returns the taxes of a buy, a taxed sell and a sell at a loss.
+func newResponse() driver.Response {
+	capitalGain := models.NewCapitalGain()
+	capitalGain.ApplyOperations([]models.Operation{
+		models.NewBuy(models.NewQuantity(10000), models.NewMonetaryValue(10.00)),
+		models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(20.00)),
+		models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(5.00)),
+	})
+
+	return driver.NewResponse([]models.CapitalGain{capitalGain})
+}
+
+// newAccountResponse returns the taxes of a buy identified by its id within an account.
+func newAccountResponse() driver.Response {
+	buy := models.NewBuy(models.NewQuantity(100), models.NewMonetaryValue(10.00)).
+		WithMetadata(models.NewMetadata().WithID("buy-1"))
+	capitalGain := models.NewCapitalGainFor("alice", models.NewPosition())
+	capitalGain.ApplyOperations([]models.Operation{buy})
+
+	return driver.NewResponse([]models.CapitalGain{capitalGain})
+}
+
+func newValidationErrors() driver.ValidationErrors {
+	return driver.ValidationErrors{
+		driver.NewValidationError(driver.ErrMissingField).WithLine(2).WithIndex(0).WithField("quantity"),
+	}
+}
+
+func TestParseFormatGivenKnownNameWhenParsedThenFormatIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// When I parse the names of the formats, in any case
+	// Then I expect each of them to be recognized
+	for name, expected := range map[string]formatters.Format{
+		"json":     formatters.JSONFormat,
+		"Table":    formatters.TableFormat,
+		"csv":      formatters.CSVFormat,
+		"MARKDOWN": formatters.MarkdownFormat,
+		"html":     formatters.HTMLFormat,
+	} {
+		format, err := formatters.ParseFormat(name)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, format)
+	}
+}
+
+func TestParseFormatGivenUnknownNameWhenParsedThenErrorIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// When I parse the name of a format that is not supported
+	_, err := formatters.ParseFormat("xml")
+
+	// Then I expect an error listing the supported ones
+	assert.EqualError(t, err, `unsupported format "xml": expected json, table, csv, markdown or html`)
+}
+
+func TestParseColumnsGivenListWhenParsedThenColumnsKeepTheirOrder(t *testing.T) {
+	t.Parallel()
+
+	// When I parse a list of columns, with blanks and in any case
+	columns, err := formatters.ParseColumns("gain, Price,,operation")
+
+	// Then I expect the columns in the given order
+	assert.NoError(t, err)
+	assert.Equal(t, []formatters.Column{formatters.GainColumn, formatters.PriceColumn, formatters.OperationColumn}, columns)
+}
+
+func TestParseColumnsGivenUnknownColumnWhenParsedThenErrorIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// When I parse a list naming a column that is not supported
+	_, err := formatters.ParseColumns("gain,fees")
+
+	// Then I expect an error naming it
+	assert.EqualError(t, err, `unsupported column "fees": expected operation, quantity, price, gain or remaining-loss`)
+}
+
+func TestNewGivenZeroFormatWhenCreatedThenJSONFormatterIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// When I create the formatter of the zero format
+	formatter := formatters.New("", nil, locales.Locale{})
+
+	// Then I expect the JSON contract to be rendered
+	assert.IsType(t, &formatters.JSONFormatter{}, formatter)
+}
@@ -0,0 +1,113 @@
+package formatters
+
+import (
+	"fmt"
+	"slices"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/locales"
+)
+
+// grid is the output of a simulation laid out as the header and the rows of a table, for the
+// outputs rendering it as text.
+type grid struct {
+	headers []string
+	numeric []bool
+	rows    [][]string
+}
+
+// responseGrid lays out a row per operation: its account and id when the operations carry them,
+// the optional columns, and its tax, with numbers in the notation of the locale.
+func responseGrid(response driver.Response, columns []Column, locale locales.Locale) grid {
+	operations := make([]driver.OperationTax, 0)
+
+	for _, account := range response.Accounts() {
+		operations = append(operations, account.Operations...)
+	}
+
+	withIDs := slices.ContainsFunc(operations, func(operation driver.OperationTax) bool { return operation.ID != "" })
+	table := grid{rows: make([][]string, len(operations))}
+
+	if response.Grouped() {
+		addColumn(&table, "Account", false, operations, func(operation driver.OperationTax) string { return operation.Account })
+	}
+
+	if withIDs {
+		addColumn(&table, "ID", false, operations, func(operation driver.OperationTax) string { return operation.ID })
+	}
+
+	for _, column := range columns {
+		addColumn(&table, column.header(), column.numeric(), operations, func(operation driver.OperationTax) string {
+			return column.text(operation, locale)
+		})
+	}
+
+	addColumn(&table, "Tax", true, operations, func(operation driver.OperationTax) string {
+		return locale.FormatAmount(float64(operation.Tax))
+	})
+
+	return table
+}
+
+// validationErrorsGrid lays out a row per validation error, locating it and describing it.
+func validationErrorsGrid(validationErrors driver.ValidationErrors) grid {
+	table := grid{
+		headers: []string{"Line", "Index", "Field", "Code", "Message"},
+		numeric: []bool{true, true, false, false, false},
+		rows:    make([][]string, 0, len(validationErrors)),
+	}
+
+	for _, validationError := range validationErrors {
+		line, index := "", ""
+
+		if validationError.Line > 0 {
+			line = fmt.Sprint(validationError.Line)
+		}
+
+		if validationError.Index >= 0 {
+			index = fmt.Sprint(validationError.Index)
+		}
+
+		table.rows = append(table.rows, []string{
+			line,
+			index,
+			validationError.Field,
+			validationError.Code(),
+			validationError.Err.Error(),
+		})
+	}
+
+	return table
+}
+
+// addColumn adds a column to the table, the text of each item in its row.
+func addColumn[T any](table *grid, header string, numeric bool, items []T, text func(T) string) {
+	table.headers = append(table.headers, header)
+	table.numeric = append(table.numeric, numeric)
+
+	for index, item := range items {
+		table.rows[index] = append(table.rows[index], text(item))
+	}
+}
+
+// widths returns the width of each column, the one of its longest cell.
+func (table grid) widths() []int {
+	widths := make([]int, len(table.headers))
+
+	for _, row := range append([][]string{table.headers}, table.rows...) {
+		for index, cell := range row {
+			widths[index] = max(widths[index], len([]rune(cell)))
+		}
+	}
+
+	return widths
+}
+
+// pad fills the cell up to the width of its column, aligning numbers to the right.
+func (table grid) pad(cell string, column int, width int) string {
+	if table.numeric[column] {
+		return fmt.Sprintf("%*s", width, cell)
+	}
+
+	return fmt.Sprintf("%-*s", width, cell)
+}
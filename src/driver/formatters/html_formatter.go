@@ -0,0 +1,279 @@
+package formatters
+
+import (
+	_ "embed"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/locales"
+)
+
+//go:embed html_formatter.css
+var htmlStyle string
+
+// HTMLFormatter renders every simulation as a section of a single self-contained HTML document, with
+// inline CSS and SVG charts, so it can be sent along as a statement without any external asset.
+type HTMLFormatter struct {
+	columns  []Column
+	locale   locales.Locale
+	sections int
+}
+
+func NewHTMLFormatter(columns []Column, locale locales.Locale) *HTMLFormatter {
+	return &HTMLFormatter{columns: columns, locale: locale}
+}
+
+func (formatter *HTMLFormatter) FormatResponse(response driver.Response) string {
+	return formatter.section(htmlTable(responseGrid(response, formatter.columns, formatter.locale)))
+}
+
+func (formatter *HTMLFormatter) FormatValidationErrors(validationErrors driver.ValidationErrors) string {
+	return formatter.section(`<p class="rejected">Not calculated because of validation errors.</p>` + "\n" +
+		htmlTable(validationErrorsGrid(validationErrors)))
+}
+
+// FormatReport renders the operations of the simulation along with the position each of them left,
+// the tax due per month, and charts of how the tax, the accumulated loss and the position evolved.
+func (formatter *HTMLFormatter) FormatReport(statement driver.Statement) string {
+	locale := formatter.locale
+	sections := []string{
+		fmt.Sprintf(`<p class="total">Total tax: %s</p>`, html.EscapeString(locale.FormatAmount(float64(statement.Report.TotalTax)))),
+		"<h3>Operations</h3>",
+		htmlTable(operationsGrid(statement.Operations, locale)),
+		"<h3>Tax per month</h3>",
+		htmlTable(monthsGrid(statement.Report.Months, locale)),
+		monthlyTaxChart(statement.Report.Months, locale).render(),
+	}
+
+	for _, operations := range byAccount(statement.Operations) {
+		if account := operations[0].Account; account != "" {
+			sections = append(sections, fmt.Sprintf("<h3>Account %s</h3>", html.EscapeString(account)))
+		}
+
+		for _, evolution := range evolutionCharts(operations, locale) {
+			sections = append(sections, evolution.render())
+		}
+	}
+
+	return formatter.section(strings.Join(slices.DeleteFunc(sections, func(text string) bool { return text == "" }), "\n"))
+}
+
+// End closes the document, starting it first when no simulation was rendered.
+func (formatter *HTMLFormatter) End() string {
+	start := ""
+
+	if formatter.sections == 0 {
+		start = formatter.start()
+	}
+
+	return start + "</main>\n</body>\n</html>"
+}
+
+// section renders the output of the next simulation, preceded by the start of the document when it is the first one.
+func (formatter *HTMLFormatter) section(body string) string {
+	start := ""
+
+	if formatter.sections == 0 {
+		start = formatter.start()
+	}
+
+	formatter.sections++
+
+	return fmt.Sprintf("%s<section>\n<h2>Simulation %d</h2>\n%s\n</section>", start, formatter.sections, body)
+}
+
+func (formatter *HTMLFormatter) start() string {
+	language := formatter.locale.Name()
+
+	if language == "" {
+		language = "en"
+	}
+
+	return "<!DOCTYPE html>\n" +
+		fmt.Sprintf("<html lang=%q>\n", language) +
+		"<head>\n" +
+		`<meta charset="utf-8">` + "\n" +
+		`<meta name="viewport" content="width=device-width, initial-scale=1">` + "\n" +
+		"<title>Capital gains statement</title>\n" +
+		"<style>\n" + htmlStyle + "</style>\n" +
+		"</head>\n" +
+		"<body>\n" +
+		"<main>\n" +
+		"<h1>Capital gains statement</h1>\n"
+}
+
+// operationsGrid lays out a row per operation: what was traded, the gain and the tax it led to,
+// and the position it left.
+func operationsGrid(operations []driver.OperationExplanation, locale locales.Locale) grid {
+	table := grid{rows: make([][]string, len(operations))}
+	amount := func(value driver.Amount) string { return locale.FormatAmount(float64(value)) }
+	optional := []struct {
+		header string
+		text   func(driver.OperationExplanation) string
+	}{
+		{header: "Account", text: func(operation driver.OperationExplanation) string { return operation.Account }},
+		{header: "ID", text: func(operation driver.OperationExplanation) string { return operation.ID }},
+		{header: "Date", text: func(operation driver.OperationExplanation) string { return operation.Date }},
+		{header: "Ticker", text: func(operation driver.OperationExplanation) string { return operation.Ticker }},
+	}
+
+	// The fields the operations may go without only get a column when some operation has them.
+	for _, column := range optional {
+		if slices.ContainsFunc(operations, func(operation driver.OperationExplanation) bool { return column.text(operation) != "" }) {
+			addColumn(&table, column.header, false, operations, column.text)
+		}
+	}
+
+	addColumn(&table, "Operation", false, operations, func(operation driver.OperationExplanation) string { return operation.Operation })
+	addColumn(&table, "Quantity", true, operations, func(operation driver.OperationExplanation) string {
+		return locale.FormatNumber(float64(operation.Quantity), 0)
+	})
+	addColumn(&table, "Price", true, operations, func(operation driver.OperationExplanation) string { return amount(operation.UnitCost) })
+	addColumn(&table, "Gain", true, operations, func(operation driver.OperationExplanation) string { return amount(operation.Gain) })
+	addColumn(&table, "Tax", true, operations, func(operation driver.OperationExplanation) string { return amount(operation.Tax) })
+	addColumn(&table, "Position", true, operations, func(operation driver.OperationExplanation) string {
+		return locale.FormatNumber(float64(operation.Position.Quantity), 0)
+	})
+	addColumn(&table, "Average cost", true, operations, func(operation driver.OperationExplanation) string {
+		return amount(operation.Position.AverageUnitCost)
+	})
+	addColumn(&table, "Accumulated loss", true, operations, func(operation driver.OperationExplanation) string {
+		return amount(operation.Position.AccumulatedLoss)
+	})
+
+	return table
+}
+
+// monthsGrid lays out a row per month of each account, with what its sells were sold for, the gain
+// they realized and the tax due.
+func monthsGrid(months []driver.MonthlyTax, locale locales.Locale) grid {
+	table := grid{rows: make([][]string, len(months))}
+	amount := func(value driver.Amount) string { return locale.FormatAmount(float64(value)) }
+
+	if slices.ContainsFunc(months, func(month driver.MonthlyTax) bool { return month.Account != "" }) {
+		addColumn(&table, "Account", false, months, func(month driver.MonthlyTax) string { return month.Account })
+	}
+
+	addColumn(&table, "Month", false, months, monthOf)
+	addColumn(&table, "Proceeds", true, months, func(month driver.MonthlyTax) string { return amount(month.Proceeds) })
+	addColumn(&table, "Gain", true, months, func(month driver.MonthlyTax) string { return amount(month.Gain) })
+	addColumn(&table, "Tax", true, months, func(month driver.MonthlyTax) string { return amount(month.Tax) })
+
+	return table
+}
+
+// monthlyTaxChart draws a bar per month of each account with the tax due.
+func monthlyTaxChart(months []driver.MonthlyTax, locale locales.Locale) chart {
+	taxChart := chart{kind: barChart, title: "Tax per month", format: locale.FormatAmount}
+
+	for _, month := range months {
+		label := monthOf(month)
+
+		if month.Account != "" {
+			label = month.Account + " " + label
+		}
+
+		taxChart.labels = append(taxChart.labels, label)
+		taxChart.values = append(taxChart.values, float64(month.Tax))
+	}
+
+	return taxChart
+}
+
+// evolutionCharts draws how the accumulated loss, the quantity and the average unit cost of the
+// position of an account evolved, operation after operation.
+func evolutionCharts(operations []driver.OperationExplanation, locale locales.Locale) []chart {
+	quantity := func(value float64) string { return locale.FormatNumber(value, 0) }
+	charts := []chart{
+		{kind: lineChart, title: "Accumulated loss", format: locale.FormatAmount},
+		{kind: lineChart, title: "Position", format: quantity},
+		{kind: lineChart, title: "Average unit cost", format: locale.FormatAmount},
+	}
+
+	for index, operation := range operations {
+		label := operation.Date
+
+		if label == "" {
+			label = fmt.Sprintf("#%d", index+1)
+		}
+
+		values := []float64{
+			float64(operation.Position.AccumulatedLoss),
+			float64(operation.Position.Quantity),
+			float64(operation.Position.AverageUnitCost),
+		}
+
+		for chartIndex := range charts {
+			charts[chartIndex].labels = append(charts[chartIndex].labels, label)
+			charts[chartIndex].values = append(charts[chartIndex].values, values[chartIndex])
+		}
+	}
+
+	return charts
+}
+
+// byAccount splits the operations per account, in the order the accounts first appear.
+func byAccount(operations []driver.OperationExplanation) [][]driver.OperationExplanation {
+	accounts := make([][]driver.OperationExplanation, 0)
+
+	for _, operation := range operations {
+		index := slices.IndexFunc(accounts, func(account []driver.OperationExplanation) bool {
+			return account[0].Account == operation.Account
+		})
+
+		if index < 0 {
+			accounts = append(accounts, nil)
+			index = len(accounts) - 1
+		}
+
+		accounts[index] = append(accounts[index], operation)
+	}
+
+	return accounts
+}
+
+func monthOf(month driver.MonthlyTax) string {
+	if month.Month == "" {
+		return "Undated"
+	}
+
+	return month.Month
+}
+
+// htmlTable renders the grid as an HTML table, numbers aligned to the right.
+func htmlTable(table grid) string {
+	var text strings.Builder
+
+	text.WriteString("<table>\n<thead><tr>")
+
+	for index, header := range table.headers {
+		fmt.Fprintf(&text, "<th%s>%s</th>", cellClass(table, index), html.EscapeString(header))
+	}
+
+	text.WriteString("</tr></thead>\n<tbody>\n")
+
+	for _, row := range table.rows {
+		text.WriteString("<tr>")
+
+		for index, cell := range row {
+			fmt.Fprintf(&text, "<td%s>%s</td>", cellClass(table, index), html.EscapeString(cell))
+		}
+
+		text.WriteString("</tr>\n")
+	}
+
+	text.WriteString("</tbody>\n</table>")
+
+	return text.String()
+}
+
+func cellClass(table grid, column int) string {
+	if table.numeric[column] {
+		return ` class="number"`
+	}
+
+	return ""
+}
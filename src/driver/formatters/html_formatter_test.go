@@ -0,0 +1,120 @@
+package formatters_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/formatters"
+	"capital-gains/src/driver/locales"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newStatement returns the report of a buy, a taxed sell and a sell at a loss, traded in three months.
+func newStatement() driver.Statement {
+	capitalGain := models.NewCapitalGain()
+	capitalGain.ApplyOperations([]models.Operation{
+		models.NewBuy(models.NewQuantity(10000), models.NewMonetaryValue(10.00)).
+			WithMetadata(models.NewMetadata().WithTradedOn(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC))),
+		models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(20.00)).
+			WithMetadata(models.NewMetadata().WithTradedOn(time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC))),
+		models.NewSell(models.NewQuantity(5000), models.NewMonetaryValue(5.00)).
+			WithMetadata(models.NewMetadata().WithTradedOn(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC))),
+	})
+
+	return driver.NewStatement([]models.CapitalGain{capitalGain})
+}
+
+func TestHTMLFormatterGivenReportWhenFormattedThenDocumentHoldsTablesAndCharts(t *testing.T) {
+	t.Parallel()
+
+	// Given the report of a simulation
+	statement := newStatement()
+
+	// When I format it as HTML and end the document
+	formatter := formatters.NewHTMLFormatter(nil, locales.Locale{})
+	output := formatter.FormatReport(statement) + "\n" + formatter.End()
+
+	// Then I expect a single document with the operations, the tax per month and the charts
+	assert.True(t, strings.HasPrefix(output, "<!DOCTYPE html>\n<html lang=\"en\">"))
+	assert.True(t, strings.HasSuffix(output, "</main>\n</body>\n</html>"))
+	assert.Contains(t, output, `<p class="total">Total tax: 10000.00</p>`)
+	assert.Contains(t, output, `<tr><td>2024-02-05</td><td>sell</td><td class="number">5000</td>`+
+		`<td class="number">20.00</td><td class="number">50000.00</td><td class="number">10000.00</td>`+
+		`<td class="number">5000</td><td class="number">10.00</td><td class="number">0.00</td></tr>`)
+	assert.Contains(t, output, `<tr><td>2024-03</td><td class="number">25000.00</td>`+
+		`<td class="number">-25000.00</td><td class="number">0.00</td></tr>`)
+
+	for _, title := range []string{"Tax per month", "Accumulated loss", "Position", "Average unit cost"} {
+		assert.Contains(t, output, `aria-label="`+title+`"`)
+	}
+}
+
+func TestHTMLFormatterGivenReportWhenFormattedThenDocumentIsSelfContained(t *testing.T) {
+	t.Parallel()
+
+	// Given the report of a simulation
+	statement := newStatement()
+
+	// When I format it as HTML and end the document
+	formatter := formatters.NewHTMLFormatter(nil, locales.Locale{})
+	output := formatter.FormatReport(statement) + "\n" + formatter.End()
+
+	// Then I expect the style and the charts inline, without any external asset
+	assert.Contains(t, output, "<style>\n")
+	assert.NotContains(t, output, "<link")
+	assert.NotContains(t, output, "<script")
+	assert.NotContains(t, output, "src=")
+	assert.NotContains(t, output, "href=")
+}
+
+func TestHTMLFormatterGivenSeveralSimulationsWhenFormattedThenDocumentIsStartedOnce(t *testing.T) {
+	t.Parallel()
+
+	// Given a formatter that already rendered the report of a simulation
+	formatter := formatters.NewHTMLFormatter(nil, locales.BrazilianPortuguese())
+	first := formatter.FormatReport(newStatement())
+
+	// When I format the validation errors of the next one
+	second := formatter.FormatValidationErrors(newValidationErrors())
+
+	// Then I expect a section per simulation, the amounts in the locale, in a single document
+	assert.True(t, strings.HasPrefix(first, "<!DOCTYPE html>\n<html lang=\"pt-BR\">"))
+	assert.Contains(t, first, `<p class="total">Total tax: R$ 10.000,00</p>`)
+	assert.True(t, strings.HasPrefix(second, "<section>\n<h2>Simulation 2</h2>\n"))
+	assert.Contains(t, second, "<td>quantity</td><td>missing-field</td>")
+}
+
+func TestHTMLFormatterGivenMarkupInTheInputWhenFormattedThenItIsEscaped(t *testing.T) {
+	t.Parallel()
+
+	// Given the taxes of an operation whose account holds markup
+	capitalGain := models.NewCapitalGainFor("<script>alice</script>", models.NewPosition())
+	capitalGain.ApplyOperations([]models.Operation{models.NewBuy(models.NewQuantity(100), models.NewMonetaryValue(10.00))})
+	response := driver.NewResponse([]models.CapitalGain{capitalGain})
+
+	// When I format them as HTML
+	output := formatters.NewHTMLFormatter(nil, locales.Locale{}).FormatResponse(response)
+
+	// Then I expect the markup to be written as text
+	assert.Contains(t, output, "<td>&lt;script&gt;alice&lt;/script&gt;</td>")
+	assert.NotContains(t, output, "<script>")
+}
+
+func TestHTMLFormatterGivenNoSimulationWhenEndedThenEmptyDocumentIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a formatter that rendered nothing
+	formatter := formatters.NewHTMLFormatter(nil, locales.Locale{})
+
+	// When I end the document
+	output := formatter.End()
+
+	// Then I expect a complete document without sections
+	assert.True(t, strings.HasPrefix(output, "<!DOCTYPE html>"))
+	assert.True(t, strings.HasSuffix(output, "<h1>Capital gains statement</h1>\n</main>\n</body>\n</html>"))
+	assert.NotContains(t, output, "<section>")
+}
@@ -0,0 +1,114 @@
+package formatters
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"capital-gains/src/driver"
+)
+
+// JSONFormatter renders the JSON contract. Without optional columns, it writes exactly the tax
+// output; otherwise, each tax object also holds the columns, named after the input fields.
+type JSONFormatter struct {
+	columns []Column
+}
+
+// jsonTax is the tax object of an operation along with the optional columns.
+type jsonTax struct {
+	operation driver.OperationTax
+	columns   []Column
+}
+
+// jsonAccount holds the tax objects of the operations of a single account.
+type jsonAccount struct {
+	Account string                `json:"account"`
+	Taxes   []jsonTax             `json:"taxes"`
+	Summary driver.AccountSummary `json:"summary"`
+}
+
+func NewJSONFormatter(columns []Column) *JSONFormatter {
+	return &JSONFormatter{columns: columns}
+}
+
+func (formatter *JSONFormatter) FormatResponse(response driver.Response) string {
+	if len(formatter.columns) == 0 {
+		return response.ToString()
+	}
+
+	taxes := make([]jsonTax, 0)
+	accounts := make([]jsonAccount, 0)
+
+	for _, account := range response.Accounts() {
+		accountTaxes := make([]jsonTax, 0, len(account.Operations))
+
+		for _, operation := range account.Operations {
+			accountTaxes = append(accountTaxes, jsonTax{operation: operation, columns: formatter.columns})
+		}
+
+		taxes = append(taxes, accountTaxes...)
+		accounts = append(accounts, jsonAccount{Account: account.Account, Taxes: accountTaxes, Summary: account.Summary})
+	}
+
+	if response.Grouped() {
+		return formatTaxes(response, driver.GroupedTaxes[jsonAccount]{Accounts: accounts})
+	}
+
+	return formatTaxes(response, taxes)
+}
+
+// formatTaxes renders the taxes of the response, wrapped along with its summary when it is summarized.
+func formatTaxes[T any](response driver.Response, taxes T) string {
+	if response.Summarized() {
+		return toJSON(driver.SummarizedTaxes[T]{Taxes: taxes, Summary: response.Summary()})
+	}
+
+	return toJSON(taxes)
+}
+
+// FormatReport renders the tax due per month of the simulation.
+func (formatter *JSONFormatter) FormatReport(statement driver.Statement) string {
+	return statement.Report.ToString()
+}
+
+func (formatter *JSONFormatter) FormatValidationErrors(validationErrors driver.ValidationErrors) string {
+	return validationErrors.ToString()
+}
+
+func (tax jsonTax) MarshalJSON() ([]byte, error) {
+	fields := make([]string, 0, len(tax.columns)+2)
+
+	if tax.operation.ID != "" {
+		serializedID, err := json.Marshal(tax.operation.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, fmt.Sprintf(`"id":%s`, serializedID))
+	}
+
+	for _, column := range tax.columns {
+		serializedValue, err := json.Marshal(column.value(tax.operation))
+
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, fmt.Sprintf("%q:%s", column.key(), serializedValue))
+	}
+
+	fields = append(fields, fmt.Sprintf(`"tax":%s`, formatAmount(tax.operation.Tax)))
+
+	return []byte("{" + strings.Join(fields, ",") + "}"), nil
+}
+
+func toJSON(value any) string {
+	serializedValue, err := json.Marshal(value)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return string(serializedValue)
+}
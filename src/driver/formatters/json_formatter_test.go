@@ -0,0 +1,81 @@
+package formatters_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driver/formatters"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONFormatterGivenNoColumnsWhenFormattedThenTaxOutputIsUnchanged(t *testing.T) {
+	t.Parallel()
+
+	// Given the taxes of a simulation
+	response := newResponse()
+
+	// When I format them without optional columns
+	output := formatters.NewJSONFormatter(nil).FormatResponse(response)
+
+	// Then I expect exactly the tax output
+	assert.Equal(t, response.ToString(), output)
+}
+
+func TestJSONFormatterGivenColumnsWhenFormattedThenTaxObjectsHoldThem(t *testing.T) {
+	t.Parallel()
+
+	// Given the taxes of a simulation
+	response := newResponse()
+
+	// When I format them with every optional column
+	output := formatters.NewJSONFormatter(allColumns).FormatResponse(response)
+
+	// Then I expect each tax object to hold the columns, named after the input fields
+	expected := `[` +
+		`{"operation":"buy","quantity":10000,"unit-cost":10.00,"gain":0.00,"remaining-loss":0.00,"tax":0.00},` +
+		`{"operation":"sell","quantity":5000,"unit-cost":20.00,"gain":50000.00,"remaining-loss":0.00,"tax":10000.00},` +
+		`{"operation":"sell","quantity":5000,"unit-cost":5.00,"gain":-25000.00,"remaining-loss":25000.00,"tax":0.00}]`
+	assert.Equal(t, expected, output)
+}
+
+func TestJSONFormatterGivenAccountsWhenFormattedThenTaxesStayGrouped(t *testing.T) {
+	t.Parallel()
+
+	// Given the taxes of a simulation whose operations carry an account
+	response := newAccountResponse()
+
+	// When I format them with an optional column
+	output := formatters.NewJSONFormatter([]formatters.Column{formatters.OperationColumn}).FormatResponse(response)
+
+	// Then I expect the taxes to be grouped by account along with their summary
+	expected := `{"accounts":[{"account":"alice","taxes":[{"id":"buy-1","operation":"buy","tax":0.00}],` +
+		`"summary":{"total-tax":0.00,"quantity":100,"average-unit-cost":10.00,"accumulated-loss":0.00}}]}`
+	assert.Equal(t, expected, output)
+}
+
+func TestJSONFormatterGivenValidationErrorsWhenFormattedThenErrorObjectIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// When I format the validation errors of a simulation
+	output := formatters.NewJSONFormatter(allColumns).FormatValidationErrors(newValidationErrors())
+
+	// Then I expect the error object of the contract
+	assert.Equal(t, newValidationErrors().ToString(), output)
+}
+
+func TestJSONFormatterGivenSummarizedResponseWhenFormattedWithColumnsThenTaxesAreWrapped(t *testing.T) {
+	t.Parallel()
+
+	// Given the taxes of a simulation along with its summary
+	response := newAccountResponse().WithSummary()
+
+	// When I format them with an optional column
+	output := formatters.NewJSONFormatter([]formatters.Column{formatters.QuantityColumn}).FormatResponse(response)
+
+	// Then I expect the taxes holding the column to be wrapped along with the summary
+	expected := `{"taxes":{"accounts":[{"account":"alice","taxes":[{"id":"buy-1","quantity":100,"tax":0.00}],` +
+		`"summary":{"total-tax":0.00,"quantity":100,"average-unit-cost":10.00,"accumulated-loss":0.00}}]},` +
+		`"summary":{"total-tax":0.00,"total-proceeds":0.00,"realized-gain":0.00,"realized-loss":0.00,` +
+		`"accumulated-loss":0.00,"positions":[{"account":"alice","quantity":100,"average-unit-cost":10.00,"accumulated-loss":0.00}]}}`
+	assert.Equal(t, expected, output)
+}
@@ -0,0 +1,90 @@
+package formatters
+
+import (
+	"strings"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/locales"
+)
+
+// MarkdownFormatter renders each simulation as a Markdown table, numbers aligned to the right, with
+// a blank line between simulations.
+type MarkdownFormatter struct {
+	columns []Column
+	locale  locales.Locale
+	written bool
+}
+
+func NewMarkdownFormatter(columns []Column, locale locales.Locale) *MarkdownFormatter {
+	return &MarkdownFormatter{columns: columns, locale: locale}
+}
+
+func (formatter *MarkdownFormatter) FormatResponse(response driver.Response) string {
+	return formatter.render(responseGrid(response, formatter.columns, formatter.locale))
+}
+
+func (formatter *MarkdownFormatter) FormatValidationErrors(validationErrors driver.ValidationErrors) string {
+	return formatter.render(validationErrorsGrid(validationErrors))
+}
+
+func (formatter *MarkdownFormatter) render(table grid) string {
+	var text strings.Builder
+
+	if formatter.written {
+		text.WriteString("\n")
+	}
+
+	formatter.written = true
+	table = escape(table)
+	widths := table.widths()
+	alignments := make([]string, len(widths))
+
+	for index, width := range widths {
+		// The alignment row needs at least three characters per column.
+		width = max(width, 3)
+		widths[index] = width
+
+		if table.numeric[index] {
+			alignments[index] = strings.Repeat("-", width-1) + ":"
+			continue
+		}
+
+		alignments[index] = ":" + strings.Repeat("-", width-1)
+	}
+
+	lines := make([]string, 0, len(table.rows)+2)
+	lines = append(lines, formatter.line(table, table.headers, widths), "| "+strings.Join(alignments, " | ")+" |")
+
+	for _, row := range table.rows {
+		lines = append(lines, formatter.line(table, row, widths))
+	}
+
+	text.WriteString(strings.Join(lines, "\n"))
+
+	return text.String()
+}
+
+func (formatter *MarkdownFormatter) line(table grid, cells []string, widths []int) string {
+	padded := make([]string, len(cells))
+
+	for index, cell := range cells {
+		padded[index] = table.pad(cell, index, widths[index])
+	}
+
+	return "| " + strings.Join(padded, " | ") + " |"
+}
+
+// escape returns the table with the pipes of its cells escaped, so they do not end the cells.
+func escape(table grid) grid {
+	escaped := grid{headers: table.headers, numeric: table.numeric, rows: make([][]string, len(table.rows))}
+
+	for index, row := range table.rows {
+		escaped.rows[index] = make([]string, len(row))
+
+		for column, cell := range row {
+			escaped.rows[index][column] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+	}
+
+	return escaped
+}
@@ -0,0 +1,46 @@
+package formatters_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/formatters"
+	"capital-gains/src/driver/locales"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownFormatterGivenColumnsWhenFormattedThenMarkdownTableIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given the taxes of a simulation
+	response := newResponse()
+
+	// When I format them as Markdown with some optional columns
+	output := formatters.NewMarkdownFormatter([]formatters.Column{formatters.QuantityColumn, formatters.RemainingLossColumn}, locales.Locale{}).
+		FormatResponse(response)
+
+	// Then I expect a table with the numbers aligned to the right
+	expected := "| Quantity | Remaining loss |      Tax |\n" +
+		"| -------: | -------------: | -------: |\n" +
+		"|    10000 |           0.00 |     0.00 |\n" +
+		"|     5000 |           0.00 | 10000.00 |\n" +
+		"|     5000 |       25000.00 |     0.00 |"
+	assert.Equal(t, expected, output)
+}
+
+func TestMarkdownFormatterGivenPipesInCellsWhenFormattedThenTheyAreEscaped(t *testing.T) {
+	t.Parallel()
+
+	// Given a validation error whose field holds a pipe
+	validationErrors := driver.ValidationErrors{driver.NewValidationError(driver.ErrUnknownField).WithField("a|b")}
+
+	// When I format it as Markdown
+	output := formatters.NewMarkdownFormatter(nil, locales.Locale{}).FormatValidationErrors(validationErrors)
+
+	// Then I expect the pipe not to end the cell
+	expected := "| Line | Index | Field | Code          | Message       |\n" +
+		"| ---: | ----: | :---- | :------------ | :------------ |\n" +
+		"|      |       | a\\|b  | unknown-field | unknown field |"
+	assert.Equal(t, expected, output)
+}
@@ -0,0 +1,180 @@
+package formatters
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Dimensions of the charts, in SVG user units.
+const (
+	chartWidth  = 640
+	chartHeight = 240
+	chartLeft   = 96
+	chartRight  = 16
+	chartTop    = 16
+	chartBottom = 40
+
+	// maxChartLabels is the most labels written under a chart; longer series only get some of them.
+	maxChartLabels = 12
+)
+
+// chartKind selects how the values of a chart are drawn.
+type chartKind int
+
+const (
+	// lineChart joins the values in a line, for what evolves from one operation to the next.
+	lineChart chartKind = iota
+
+	// barChart draws a bar per value, for what is summed up per period.
+	barChart
+)
+
+// chart is a series of values drawn as an inline SVG image, one point or bar per label, with the
+// values written in the given notation.
+type chart struct {
+	kind   chartKind
+	title  string
+	labels []string
+	values []float64
+	format func(float64) string
+}
+
+// render returns the chart as an SVG image within a figure, nothing when there are no values.
+func (chart chart) render() string {
+	if len(chart.values) == 0 {
+		return ""
+	}
+
+	var svg strings.Builder
+
+	fmt.Fprintf(
+		&svg,
+		`<figure><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" role="img" aria-label="%s">`,
+		chartWidth,
+		chartHeight,
+		html.EscapeString(chart.title),
+	)
+	svg.WriteString(chart.axis())
+
+	if chart.kind == barChart {
+		svg.WriteString(chart.bars())
+	} else {
+		svg.WriteString(chart.line())
+	}
+
+	svg.WriteString(chart.xLabels())
+	fmt.Fprintf(&svg, "</svg><figcaption>%s</figcaption></figure>", html.EscapeString(chart.title))
+
+	return svg.String()
+}
+
+// bounds returns the lowest and the highest value of the scale, which always includes zero.
+func (chart chart) bounds() (float64, float64) {
+	lowest := min(0, slices.Min(chart.values))
+	highest := max(0, slices.Max(chart.values))
+
+	if lowest == highest {
+		highest = lowest + 1
+	}
+
+	return lowest, highest
+}
+
+// y returns the vertical coordinate of the value.
+func (chart chart) y(value float64) float64 {
+	lowest, highest := chart.bounds()
+
+	return chartTop + (chartHeight-chartTop-chartBottom)*(highest-value)/(highest-lowest)
+}
+
+// x returns the horizontal coordinate of the center of the value at the index.
+func (chart chart) x(index int) float64 {
+	band := float64(chartWidth-chartLeft-chartRight) / float64(len(chart.values))
+
+	return chartLeft + band*(float64(index)+0.5)
+}
+
+// axis draws the zero line along with the highest and the lowest value of the scale.
+func (chart chart) axis() string {
+	lowest, highest := chart.bounds()
+	ticks := fmt.Sprintf(
+		`<line class="axis" x1="%d" y1="%.1f" x2="%d" y2="%.1f"/>`,
+		chartLeft,
+		chart.y(0),
+		chartWidth-chartRight,
+		chart.y(0),
+	)
+
+	for _, value := range []float64{lowest, highest} {
+		ticks += fmt.Sprintf(
+			`<text class="tick" x="%d" y="%.1f" text-anchor="end">%s</text>`,
+			chartLeft-8,
+			chart.y(value)+4,
+			html.EscapeString(chart.format(value)),
+		)
+	}
+
+	return ticks
+}
+
+func (chart chart) line() string {
+	points := make([]string, len(chart.values))
+	markers := make([]string, len(chart.values))
+
+	for index, value := range chart.values {
+		points[index] = fmt.Sprintf("%.1f,%.1f", chart.x(index), chart.y(value))
+		markers[index] = fmt.Sprintf(
+			`<circle class="point" cx="%.1f" cy="%.1f" r="3"><title>%s</title></circle>`,
+			chart.x(index),
+			chart.y(value),
+			chart.tooltip(index),
+		)
+	}
+
+	return fmt.Sprintf(`<polyline class="series" points="%s"/>`, strings.Join(points, " ")) + strings.Join(markers, "")
+}
+
+func (chart chart) bars() string {
+	band := float64(chartWidth-chartLeft-chartRight) / float64(len(chart.values))
+	bars := make([]string, len(chart.values))
+
+	for index, value := range chart.values {
+		top, bottom := chart.y(value), chart.y(0)
+
+		bars[index] = fmt.Sprintf(
+			`<rect class="bar" x="%.1f" y="%.1f" width="%.1f" height="%.1f"><title>%s</title></rect>`,
+			chart.x(index)-band*0.35,
+			math.Min(top, bottom),
+			band*0.7,
+			math.Abs(bottom-top),
+			chart.tooltip(index),
+		)
+	}
+
+	return strings.Join(bars, "")
+}
+
+// xLabels writes the labels under the chart, skipping some of them evenly when there are too many.
+func (chart chart) xLabels() string {
+	step := (len(chart.labels) + maxChartLabels - 1) / maxChartLabels
+	labels := make([]string, 0, maxChartLabels)
+
+	for index := 0; index < len(chart.labels); index += step {
+		labels = append(labels, fmt.Sprintf(
+			`<text class="label" x="%.1f" y="%d" text-anchor="middle">%s</text>`,
+			chart.x(index),
+			chartHeight-chartBottom/2,
+			html.EscapeString(chart.labels[index]),
+		))
+	}
+
+	return strings.Join(labels, "")
+}
+
+// tooltip returns the label and the value at the index, shown when hovering over its point or bar.
+func (chart chart) tooltip(index int) string {
+	return html.EscapeString(chart.labels[index] + ": " + chart.format(chart.values[index]))
+}
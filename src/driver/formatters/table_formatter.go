@@ -0,0 +1,68 @@
+package formatters
+
+import (
+	"strings"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/locales"
+)
+
+// columnGap separates the columns of the terminal table.
+const columnGap = "  "
+
+// TableFormatter renders each simulation as a table aligned for the terminal, under a rule, with
+// a blank line between simulations.
+type TableFormatter struct {
+	columns []Column
+	locale  locales.Locale
+	written bool
+}
+
+func NewTableFormatter(columns []Column, locale locales.Locale) *TableFormatter {
+	return &TableFormatter{columns: columns, locale: locale}
+}
+
+func (formatter *TableFormatter) FormatResponse(response driver.Response) string {
+	return formatter.render(responseGrid(response, formatter.columns, formatter.locale))
+}
+
+func (formatter *TableFormatter) FormatValidationErrors(validationErrors driver.ValidationErrors) string {
+	return formatter.render(validationErrorsGrid(validationErrors))
+}
+
+func (formatter *TableFormatter) render(table grid) string {
+	var text strings.Builder
+
+	if formatter.written {
+		text.WriteString("\n")
+	}
+
+	formatter.written = true
+	widths := table.widths()
+	rule := make([]string, len(widths))
+
+	for index, width := range widths {
+		rule[index] = strings.Repeat("-", width)
+	}
+
+	lines := make([]string, 0, len(table.rows)+2)
+	lines = append(lines, formatter.line(table, table.headers, widths), strings.Join(rule, columnGap))
+
+	for _, row := range table.rows {
+		lines = append(lines, formatter.line(table, row, widths))
+	}
+
+	text.WriteString(strings.Join(lines, "\n"))
+
+	return text.String()
+}
+
+func (formatter *TableFormatter) line(table grid, cells []string, widths []int) string {
+	padded := make([]string, len(cells))
+
+	for index, cell := range cells {
+		padded[index] = table.pad(cell, index, widths[index])
+	}
+
+	return strings.TrimRight(strings.Join(padded, columnGap), " ")
+}
@@ -0,0 +1,82 @@
+package formatters_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driver/formatters"
+	"capital-gains/src/driver/locales"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableFormatterGivenColumnsWhenFormattedThenTableIsAligned(t *testing.T) {
+	t.Parallel()
+
+	// Given the taxes of a simulation
+	response := newResponse()
+
+	// When I format them as a table with some optional columns
+	output := formatters.NewTableFormatter([]formatters.Column{formatters.OperationColumn, formatters.GainColumn}, locales.Locale{}).
+		FormatResponse(response)
+
+	// Then I expect text aligned to the left and numbers to the right, under a rule
+	expected := "Operation       Gain       Tax\n" +
+		"---------  ---------  --------\n" +
+		"buy             0.00      0.00\n" +
+		"sell        50000.00  10000.00\n" +
+		"sell       -25000.00      0.00"
+	assert.Equal(t, expected, output)
+}
+
+func TestTableFormatterGivenAccountsWhenFormattedThenAccountAndIDAreShown(t *testing.T) {
+	t.Parallel()
+
+	// Given the taxes of a simulation whose operations carry an account and an id
+	response := newAccountResponse()
+
+	// When I format them as a table without optional columns
+	output := formatters.NewTableFormatter(nil, locales.Locale{}).FormatResponse(response)
+
+	// Then I expect the account and the id of each operation next to its tax
+	expected := "Account  ID      Tax\n" +
+		"-------  -----  ----\n" +
+		"alice    buy-1  0.00"
+	assert.Equal(t, expected, output)
+}
+
+func TestTableFormatterGivenSeveralSimulationsWhenFormattedThenTablesAreSeparated(t *testing.T) {
+	t.Parallel()
+
+	// Given a formatter that already rendered a simulation
+	formatter := formatters.NewTableFormatter(nil, locales.Locale{})
+	formatter.FormatResponse(newAccountResponse())
+
+	// When I format the validation errors of the next one
+	output := formatter.FormatValidationErrors(newValidationErrors())
+
+	// Then I expect a table of the errors, after a blank line
+	expected := "\n" +
+		"Line  Index  Field     Code           Message\n" +
+		"----  -----  --------  -------------  -------------\n" +
+		"   2      0  quantity  missing-field  missing field"
+	assert.Equal(t, expected, output)
+}
+
+func TestTableFormatterGivenLocaleWhenFormattedThenAmountsFollowIt(t *testing.T) {
+	t.Parallel()
+
+	// Given the taxes of a simulation
+	response := newResponse()
+
+	// When I format them as a table in Brazilian Portuguese
+	output := formatters.NewTableFormatter([]formatters.Column{formatters.QuantityColumn}, locales.BrazilianPortuguese()).
+		FormatResponse(response)
+
+	// Then I expect the amounts in reais, with the thousands grouped by dots
+	expected := "Quantity           Tax\n" +
+		"--------  ------------\n" +
+		"  10.000       R$ 0,00\n" +
+		"   5.000  R$ 10.000,00\n" +
+		"   5.000       R$ 0,00"
+	assert.Equal(t, expected, output)
+}
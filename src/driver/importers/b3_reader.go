@@ -0,0 +1,276 @@
+package importers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"capital-gains/src/driver"
+)
+
+const (
+	b3DateColumn     = "data do negocio"
+	b3SideColumn     = "tipo de movimentacao"
+	b3MarketColumn   = "mercado"
+	b3TickerColumn   = "codigo de negociacao"
+	b3QuantityColumn = "quantidade"
+	b3PriceColumn    = "preco"
+
+	// b3DateLayout is the layout of the trade dates of the statement (e.g., 15/03/2024).
+	b3DateLayout = "02/01/2006"
+
+	// fractionalSuffix marks the ticker of an asset traded in the fractional market (e.g., PETR4F).
+	fractionalSuffix = "F"
+
+	// centsPerUnit converts the prices and values of the statement into cents.
+	centsPerUnit = 100
+)
+
+// ErrUnsupportedMarket is returned for trades outside the cash and fractional stock markets.
+var ErrUnsupportedMarket = errors.New("unsupported market")
+
+// B3Reader reads the trade statement ("negociação") of the B3 investor area, saved as CSV.
+//
+// Fractional-market tickers are merged into their standard ones, and trades of the same day,
+// side and ticker are consolidated into a single operation at their average price, unless that
+// price, in cents, would not give their total value back. Operations
+// are sorted by trade date, each one naming its ticker, which keeps a position of its own.
+type B3Reader struct{}
+
+func NewB3Reader() *B3Reader {
+	return new(B3Reader)
+}
+
+func (reader *B3Reader) Read(payload string) (driver.Request, error) {
+	csvReader := csv.NewReader(strings.NewReader(payload))
+	csvReader.Comma = detectDelimiter(payload)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+
+	if err != nil {
+		return driver.Request{}, &RowError{Row: 1, Err: headerError(err)}
+	}
+
+	columns, err := indexB3Columns(header)
+
+	if err != nil {
+		return driver.Request{}, &RowError{Row: 1, Err: err}
+	}
+
+	trades := make([]b3Trade, 0)
+
+	for {
+		record, err := csvReader.Read()
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		row := rowOf(csvReader, err)
+
+		if err != nil {
+			return driver.Request{}, &RowError{Row: row, Err: err}
+		}
+
+		trade, err := newB3Trade(csvRow{record: record, columns: columns})
+
+		if err != nil {
+			return driver.Request{}, &RowError{Row: row, Err: err}
+		}
+
+		trades = append(trades, trade)
+	}
+
+	return driver.NewRequest(consolidate(trades)), nil
+}
+
+// detectDelimiter tells whether the statement was saved with semicolons, as spreadsheets
+// set to Brazilian Portuguese do, or with commas.
+func detectDelimiter(payload string) rune {
+	header, _, _ := strings.Cut(payload, "\n")
+
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		return ';'
+	}
+
+	return ','
+}
+
+func indexB3Columns(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+
+	for index, name := range header {
+		normalizedName := removeAccents(normalizeHeader(name))
+
+		if _, exists := columns[normalizedName]; !exists {
+			columns[normalizedName] = index
+		}
+	}
+
+	for _, required := range []string{b3DateColumn, b3SideColumn, b3TickerColumn, b3QuantityColumn, b3PriceColumn} {
+		if _, exists := columns[required]; !exists {
+			return nil, fmt.Errorf("%w %q", ErrMissingColumn, required)
+		}
+	}
+
+	return columns, nil
+}
+
+func removeAccents(text string) string {
+	return strings.NewReplacer(
+		"á", "a", "à", "a", "ã", "a", "â", "a",
+		"é", "e", "ê", "e",
+		"í", "i",
+		"ó", "o", "õ", "o", "ô", "o",
+		"ú", "u",
+		"ç", "c",
+	).Replace(text)
+}
+
+type b3Trade struct {
+	tradedOn time.Time
+	side     string
+	ticker   string
+	quantity int
+	value    float64
+}
+
+func newB3Trade(row csvRow) (b3Trade, error) {
+	if market := removeAccents(strings.ToLower(row.value(b3MarketColumn))); market != "" &&
+		!strings.Contains(market, "vista") && !strings.Contains(market, "fracionario") {
+		return b3Trade{}, fmt.Errorf("%w %q", ErrUnsupportedMarket, row.value(b3MarketColumn))
+	}
+
+	tradedOn, err := time.Parse(b3DateLayout, row.value(b3DateColumn))
+
+	if err != nil {
+		return b3Trade{}, fmt.Errorf(
+			"%w for %q: %q is not formatted as DD/MM/YYYY", ErrInvalidValue, b3DateColumn, row.value(b3DateColumn),
+		)
+	}
+
+	side, err := b3SideOf(row.value(b3SideColumn))
+
+	if err != nil {
+		return b3Trade{}, err
+	}
+
+	quantity, err := strconv.Atoi(strings.ReplaceAll(row.value(b3QuantityColumn), ".", ""))
+
+	if err != nil || quantity <= 0 {
+		return b3Trade{}, fmt.Errorf(
+			"%w for %q: %q is not a positive integer", ErrInvalidValue, b3QuantityColumn, row.value(b3QuantityColumn),
+		)
+	}
+
+	price, err := parseBrazilianPrice(row.value(b3PriceColumn))
+
+	if err != nil {
+		return b3Trade{}, fmt.Errorf("%w for %q: %q is not a price", ErrInvalidValue, b3PriceColumn, row.value(b3PriceColumn))
+	}
+
+	return b3Trade{
+		tradedOn: tradedOn,
+		side:     side,
+		ticker:   normalizeTicker(row.value(b3TickerColumn)),
+		quantity: quantity,
+		value:    price * float64(quantity),
+	}, nil
+}
+
+func b3SideOf(label string) (string, error) {
+	switch strings.ToLower(label) {
+	case "compra", "c":
+		return buySide, nil
+	case "venda", "v":
+		return sellSide, nil
+	default:
+		return "", fmt.Errorf("%w for %q: %q is not Compra or Venda", ErrInvalidValue, b3SideColumn, label)
+	}
+}
+
+// parseBrazilianPrice parses prices such as "R$ 1.234,56", falling back to dot-decimal
+// notation when the statement was saved with another locale.
+func parseBrazilianPrice(value string) (float64, error) {
+	price := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "R$"))
+
+	if strings.Contains(price, ",") {
+		price = strings.ReplaceAll(price, ".", "")
+		price = strings.Replace(price, ",", ".", 1)
+	}
+
+	return strconv.ParseFloat(price, 64)
+}
+
+// normalizeTicker merges a fractional-market ticker into its standard one (e.g., PETR4F into PETR4).
+func normalizeTicker(ticker string) string {
+	normalizedTicker := strings.ToUpper(strings.TrimSpace(ticker))
+	standardTicker := strings.TrimSuffix(normalizedTicker, fractionalSuffix)
+
+	if standardTicker == normalizedTicker || standardTicker == "" {
+		return normalizedTicker
+	}
+
+	if last := standardTicker[len(standardTicker)-1]; last < '0' || last > '9' {
+		return normalizedTicker
+	}
+
+	return standardTicker
+}
+
+// consolidate merges the trades of the same day, side and ticker into one operation at their
+// average price, and sorts the operations by trade date, keeping the statement order otherwise.
+// A trade whose merge would round the average price away from the total value stays an operation
+// of its own, so the cost basis of the position does not drift.
+func consolidate(trades []b3Trade) []driver.Operation {
+	consolidated := make([]b3Trade, 0, len(trades))
+	indexes := make(map[b3Trade]int)
+
+	for _, trade := range trades {
+		key := b3Trade{tradedOn: trade.tradedOn, side: trade.side, ticker: trade.ticker}
+
+		if index, exists := indexes[key]; exists && averagesExactly(consolidated[index], trade) {
+			consolidated[index].quantity += trade.quantity
+			consolidated[index].value += trade.value
+			continue
+		}
+
+		indexes[key] = len(consolidated)
+		consolidated = append(consolidated, trade)
+	}
+
+	sort.SliceStable(consolidated, func(first, second int) bool {
+		return consolidated[first].tradedOn.Before(consolidated[second].tradedOn)
+	})
+
+	operations := make([]driver.Operation, len(consolidated))
+
+	for index, trade := range consolidated {
+		operations[index] = driver.Operation{
+			Date:      trade.tradedOn.Format(driver.DateLayout),
+			Operation: trade.side,
+			Ticker:    trade.ticker,
+			Quantity:  trade.quantity,
+			UnitCost:  trade.value / float64(trade.quantity),
+		}
+	}
+
+	return operations
+}
+
+// averagesExactly tells whether the average price of both trades, in cents, multiplied by their
+// quantity gives their total value back.
+func averagesExactly(first b3Trade, second b3Trade) bool {
+	quantity := float64(first.quantity + second.quantity)
+	totalCents := math.Round((first.value + second.value) * centsPerUnit)
+	averagePrice := math.Round(totalCents/quantity) / centsPerUnit
+
+	return math.Round(averagePrice*quantity*centsPerUnit) == totalCents
+}
@@ -0,0 +1,116 @@
+package importers_test
+
+import (
+	"encoding/csv"
+	"testing"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/importers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// b3Header is the header of the trade statement exported by the B3 investor area.
+const b3Header = "Data do Negócio;Tipo de Movimentação;Mercado;Prazo/Vencimento;Instituição;" +
+	"Código de Negociação;Quantidade;Preço;Valor\n"
+
+func TestB3ReaderGivenStatementWhenReadThenTradesAreNormalizedConsolidatedAndSorted(t *testing.T) {
+	t.Parallel()
+
+	// Given a statement listing the newest trades first, with fractional-market trades
+	// and several trades of the same day, side and ticker
+	payload := b3Header +
+		"05/02/2024;Venda;Mercado à Vista;-;CORRETORA A;PETR4;100;R$ 20,00;R$ 2.000,00\n" +
+		"10/01/2024;Compra;Mercado Fracionário;-;CORRETORA A;PETR4F;50;R$ 11,00;R$ 550,00\n" +
+		"10/01/2024;Compra;Mercado à Vista;-;CORRETORA B;PETR4;150;R$ 10,00;R$ 1.500,00\n" +
+		"10/01/2024;Compra;Mercado à Vista;-;CORRETORA B;VALE3;1.000;R$ 60,50;R$ 60.500,00\n"
+
+	// When I read the statement
+	request, err := importers.NewB3Reader().Read(payload)
+
+	// Then I expect one operation per day, side and ticker, at the average price, sorted by date
+	assert.NoError(t, err)
+
+	expected := []driver.Operation{
+		{Date: "2024-01-10", Operation: "buy", Ticker: "PETR4", Quantity: 200, UnitCost: 10.25},
+		{Date: "2024-01-10", Operation: "buy", Ticker: "VALE3", Quantity: 1000, UnitCost: 60.50},
+		{Date: "2024-02-05", Operation: "sell", Ticker: "PETR4", Quantity: 100, UnitCost: 20.00},
+	}
+	assert.Equal(t, expected, request.Operations())
+}
+
+func TestB3ReaderGivenTradesWhoseAveragePriceIsRoundedWhenReadThenTheyAreNotConsolidated(t *testing.T) {
+	t.Parallel()
+
+	// Given buys of the same day and ticker whose average price, 1550.00 / 150, has no exact value in cents
+	payload := b3Header +
+		"10/01/2024;Compra;Mercado Fracionário;-;CORRETORA A;PETR4F;50;R$ 11,00;R$ 550,00\n" +
+		"10/01/2024;Compra;Mercado à Vista;-;CORRETORA B;PETR4;100;R$ 10,00;R$ 1.000,00\n"
+
+	// When I read the statement
+	request, err := importers.NewB3Reader().Read(payload)
+
+	// Then I expect the buys to stay separate operations, keeping their total value of 1550.00
+	assert.NoError(t, err)
+
+	expected := []driver.Operation{
+		{Date: "2024-01-10", Operation: "buy", Ticker: "PETR4", Quantity: 50, UnitCost: 11.00},
+		{Date: "2024-01-10", Operation: "buy", Ticker: "PETR4", Quantity: 100, UnitCost: 10.00},
+	}
+	assert.Equal(t, expected, request.Operations())
+}
+
+func TestB3ReaderGivenOptionTradeWhenReadThenErrUnsupportedMarketIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a statement with a trade in the options market
+	payload := b3Header +
+		"10/01/2024;Compra;Opção de Compra;-;CORRETORA A;PETRA250;100;R$ 1,00;R$ 100,00\n"
+
+	// When I read the statement
+	_, err := importers.NewB3Reader().Read(payload)
+
+	// Then I expect the trade to be reported instead of dropped
+	var rowError *importers.RowError
+
+	assert.ErrorAs(t, err, &rowError)
+	assert.Equal(t, 2, rowError.Row)
+	assert.ErrorIs(t, err, importers.ErrUnsupportedMarket)
+}
+
+func TestB3ReaderGivenMalformedQuoteWhenReadThenRowErrorLocatesTheRow(t *testing.T) {
+	t.Parallel()
+
+	// Given a statement whose first trade opens a quoted field that is never closed
+	payload := b3Header +
+		"\"10/01/2024;Compra;Mercado à Vista;-;CORRETORA A;PETR4;100;R$ 10,00;R$ 1.000,00\n"
+
+	// When I read the statement
+	_, err := importers.NewB3Reader().Read(payload)
+
+	// Then I expect an error locating the row instead of a panic
+	var rowError *importers.RowError
+
+	assert.ErrorAs(t, err, &rowError)
+	assert.Equal(t, 2, rowError.Row)
+	assert.ErrorIs(t, err, csv.ErrQuote)
+}
+
+func TestB3ReaderGivenCommaSeparatedStatementWithoutAccentsWhenReadThenTradesAreRead(t *testing.T) {
+	t.Parallel()
+
+	// Given a statement saved with commas, dot-decimal prices and unaccented headers
+	payload := "Data do Negocio,Tipo de Movimentacao,Codigo de Negociacao,Quantidade,Preco\n" +
+		"10/01/2024,Compra,ITSA4F,7,9.87\n"
+
+	// When I read the statement
+	request, err := importers.NewB3Reader().Read(payload)
+
+	// Then I expect the trade to be read
+	assert.NoError(t, err)
+
+	expected := []driver.Operation{
+		{Date: "2024-01-10", Operation: "buy", Ticker: "ITSA4", Quantity: 7, UnitCost: 9.87},
+	}
+	assert.Equal(t, expected, request.Operations())
+}
@@ -0,0 +1,315 @@
+package importers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"capital-gains/src/driver"
+)
+
+const (
+	operationColumn = "operation"
+	unitCostColumn  = "unit-cost"
+	quantityColumn  = "quantity"
+	idColumn        = "id"
+	dateColumn      = "date"
+	timeColumn      = "time"
+	sequenceColumn  = "sequence"
+	accountColumn   = "account"
+	tickerColumn    = "ticker"
+	feesColumn      = "fees"
+
+	// byteOrderMark prefixes the header of files exported as UTF-8 by some spreadsheets.
+	byteOrderMark = "\uFEFF"
+)
+
+// knownColumns lists the fields of an operation a column can be mapped to.
+func knownColumns() []string {
+	return []string{
+		operationColumn,
+		unitCostColumn,
+		quantityColumn,
+		idColumn,
+		dateColumn,
+		timeColumn,
+		sequenceColumn,
+		accountColumn,
+		tickerColumn,
+		feesColumn,
+	}
+}
+
+// CSVReader reads operations from a CSV file whose header names the columns as set by its
+// profile. Headers are matched regardless of case, and unknown columns are ignored.
+type CSVReader struct {
+	profile Profile
+}
+
+func NewCSVReader() *CSVReader {
+	return NewCSVReaderWith(DefaultProfile())
+}
+
+// NewCSVReaderWith returns a reader of the CSV layout described by the given profile.
+func NewCSVReaderWith(profile Profile) *CSVReader {
+	return &CSVReader{profile: profile}
+}
+
+// Read returns the operations of the file as a single request, or a RowError locating the
+// first row that could not be read.
+func (reader *CSVReader) Read(payload string) (driver.Request, error) {
+	csvReader := csv.NewReader(strings.NewReader(payload))
+	csvReader.Comma, _ = utf8.DecodeRuneInString(reader.profile.Delimiter)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+
+	if err != nil {
+		return driver.Request{}, &RowError{Row: 1, Err: headerError(err)}
+	}
+
+	columns, err := reader.indexColumns(header)
+
+	if err != nil {
+		return driver.Request{}, &RowError{Row: 1, Err: err}
+	}
+
+	operations := make([]driver.Operation, 0)
+
+	for {
+		record, err := csvReader.Read()
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		row := rowOf(csvReader, err)
+
+		if err != nil {
+			return driver.Request{}, &RowError{Row: row, Err: err}
+		}
+
+		operation, err := reader.newCSVRow(record, columns).toOperation()
+
+		if err != nil {
+			return driver.Request{}, &RowError{Row: row, Err: err}
+		}
+
+		operations = append(operations, operation)
+	}
+
+	return driver.NewRequest(operations), nil
+}
+
+func headerError(err error) error {
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w %q", ErrMissingColumn, unitCostColumn)
+	}
+
+	return err
+}
+
+// indexColumns returns the index of the column holding each field found in the header.
+func (reader *CSVReader) indexColumns(header []string) (map[string]int, error) {
+	indexes := make(map[string]int, len(header))
+
+	for index, name := range header {
+		normalizedName := normalizeHeader(name)
+
+		if _, exists := indexes[normalizedName]; !exists {
+			indexes[normalizedName] = index
+		}
+	}
+
+	columns := make(map[string]int, len(knownColumns()))
+
+	for _, field := range knownColumns() {
+		if index, exists := indexes[normalizeHeader(reader.profile.Columns[field])]; exists {
+			columns[field] = index
+		}
+	}
+
+	required := []string{unitCostColumn, quantityColumn}
+
+	if reader.profile.SideFromSign == "" {
+		required = append(required, operationColumn)
+	}
+
+	for _, field := range required {
+		if _, exists := columns[field]; !exists {
+			return nil, fmt.Errorf("%w %q", ErrMissingColumn, reader.profile.Columns[field])
+		}
+	}
+
+	return columns, nil
+}
+
+func normalizeHeader(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, byteOrderMark)))
+}
+
+type csvRow struct {
+	record  []string
+	columns map[string]int
+	profile Profile
+}
+
+func (reader *CSVReader) newCSVRow(record []string, columns map[string]int) csvRow {
+	return csvRow{record: record, columns: columns, profile: reader.profile}
+}
+
+func (row csvRow) value(field string) string {
+	index, exists := row.columns[field]
+
+	if !exists || index >= len(row.record) {
+		return ""
+	}
+
+	return strings.TrimSpace(row.record[index])
+}
+
+func (row csvRow) toOperation() (driver.Operation, error) {
+	operation := driver.Operation{
+		ID:      row.value(idColumn),
+		Account: row.value(accountColumn),
+		Ticker:  row.value(tickerColumn),
+	}
+
+	var err error
+
+	if operation.Quantity, err = row.integer(quantityColumn, true); err != nil {
+		return driver.Operation{}, err
+	}
+
+	if operation.UnitCost, err = row.decimal(unitCostColumn, true); err != nil {
+		return driver.Operation{}, err
+	}
+
+	if operation.Fees, err = row.decimal(feesColumn, false); err != nil {
+		return driver.Operation{}, err
+	}
+
+	if operation.Sequence, err = row.integer(sequenceColumn, false); err != nil {
+		return driver.Operation{}, err
+	}
+
+	if operation, err = row.withSide(operation); err != nil {
+		return driver.Operation{}, err
+	}
+
+	return row.withTradedAt(operation)
+}
+
+// withSide sets the side of the operation from its label, or from the sign of a field when
+// the profile says so, leaving that field as an absolute value.
+func (row csvRow) withSide(operation driver.Operation) (driver.Operation, error) {
+	switch row.profile.SideFromSign {
+	case quantityColumn:
+		operation.Operation = sideOfSign(float64(operation.Quantity))
+		operation.Quantity = int(math.Abs(float64(operation.Quantity)))
+		return operation, nil
+	case unitCostColumn:
+		operation.Operation = sideOfSign(operation.UnitCost)
+		operation.UnitCost = math.Abs(operation.UnitCost)
+		return operation, nil
+	}
+
+	label := row.value(operationColumn)
+	side, known := row.profile.sideOf(label)
+
+	if !known {
+		return driver.Operation{}, fmt.Errorf("%w for %q: %q is not buy or sell", ErrInvalidValue, operationColumn, label)
+	}
+
+	operation.Operation = side
+
+	return operation, nil
+}
+
+func sideOfSign(value float64) string {
+	if value < 0 {
+		return sellSide
+	}
+
+	return buySide
+}
+
+func (row csvRow) integer(field string, required bool) (int, error) {
+	value := row.value(field)
+
+	if value == "" && !required {
+		return 0, nil
+	}
+
+	parsed, err := strconv.Atoi(row.profile.normalizeNumber(value))
+
+	if err != nil {
+		return 0, fmt.Errorf("%w for %q: %q is not an integer", ErrInvalidValue, field, value)
+	}
+
+	return parsed, nil
+}
+
+func (row csvRow) decimal(field string, required bool) (float64, error) {
+	value := row.value(field)
+
+	if value == "" && !required {
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseFloat(row.profile.normalizeNumber(value), 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("%w for %q: %q is not a decimal", ErrInvalidValue, field, value)
+	}
+
+	return parsed, nil
+}
+
+// withTradedAt sets the date and time of the operation in the layouts of the JSON input.
+func (row csvRow) withTradedAt(operation driver.Operation) (driver.Operation, error) {
+	date := row.value(dateColumn)
+	timeOfDay := row.value(timeColumn)
+
+	if date == "" {
+		if timeOfDay != "" {
+			return driver.Operation{}, fmt.Errorf("%w for %q: a time requires a %q", ErrInvalidValue, timeColumn, dateColumn)
+		}
+
+		return operation, nil
+	}
+
+	tradedAt, err := time.Parse(row.profile.dateLayout(), date)
+
+	if err != nil {
+		return driver.Operation{}, fmt.Errorf(
+			"%w for %q: %q is not formatted as %s", ErrInvalidValue, dateColumn, date, row.profile.DateFormat,
+		)
+	}
+
+	operation.Date = tradedAt.Format(driver.DateLayout)
+
+	if row.profile.hasTimeInDate() {
+		operation.Time = tradedAt.Format(driver.TimeLayout)
+	}
+
+	if timeOfDay == "" {
+		return operation, nil
+	}
+
+	if _, err = time.Parse(driver.TimeLayout, timeOfDay); err != nil {
+		return driver.Operation{}, fmt.Errorf(
+			"%w for %q: %q is not formatted as HH:MM:SS", ErrInvalidValue, timeColumn, timeOfDay,
+		)
+	}
+
+	operation.Time = timeOfDay
+
+	return operation, nil
+}
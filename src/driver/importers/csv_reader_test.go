@@ -0,0 +1,110 @@
+package importers_test
+
+import (
+	"encoding/csv"
+	"testing"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/importers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVReaderGivenHeaderWithOptionalColumnsWhenReadThenOperationsAreReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a CSV export with the columns in any order and case, including optional ones
+	payload := "Date,Ticker,Operation,Quantity,Unit-Cost,Fees,Notes\n" +
+		"2024-01-10,PETR4,buy,100,10.00,1.50,first trade\n" +
+		"2024-02-05,PETR4,Sell,50,20.00,,\n"
+
+	// When I read the payload
+	request, err := importers.NewCSVReader().Read(payload)
+
+	// Then I expect every row to be read as an operation
+	assert.NoError(t, err)
+
+	expected := []driver.Operation{
+		{Date: "2024-01-10", Ticker: "PETR4", Operation: "buy", Quantity: 100, UnitCost: 10.00, Fees: 1.50},
+		{Date: "2024-02-05", Ticker: "PETR4", Operation: "sell", Quantity: 50, UnitCost: 20.00},
+	}
+	assert.Equal(t, expected, request.Operations())
+}
+
+func TestCSVReaderGivenHeaderWithoutRequiredColumnWhenReadThenRowErrorIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a CSV export lacking the unit cost column
+	payload := "operation,quantity\nbuy,100\n"
+
+	// When I read the payload
+	_, err := importers.NewCSVReader().Read(payload)
+
+	// Then I expect an error locating the header row
+	var rowError *importers.RowError
+
+	assert.ErrorAs(t, err, &rowError)
+	assert.Equal(t, 1, rowError.Row)
+	assert.ErrorIs(t, err, importers.ErrMissingColumn)
+	assert.EqualError(t, err, `row 1: missing column "unit-cost"`)
+}
+
+func TestCSVReaderGivenInvalidCellWhenReadThenRowErrorLocatesTheRow(t *testing.T) {
+	t.Parallel()
+
+	// Given a CSV export whose third row has a non-numeric quantity
+	payload := "operation,unit-cost,quantity\n" +
+		"buy,10.00,100\n" +
+		"sell,20.00,fifty\n"
+
+	// When I read the payload
+	_, err := importers.NewCSVReader().Read(payload)
+
+	// Then I expect an error locating the invalid row and column
+	assert.ErrorIs(t, err, importers.ErrInvalidValue)
+	assert.EqualError(t, err, `row 3: invalid value for "quantity": "fifty" is not an integer`)
+}
+
+func TestCSVReaderGivenMalformedQuoteWhenReadThenRowErrorLocatesTheRow(t *testing.T) {
+	t.Parallel()
+
+	// Given a CSV export whose first row opens a quoted field that is never closed
+	payload := "operation,unit-cost,quantity\n" +
+		"\"buy,10.00,100\n"
+
+	// When I read the payload
+	_, err := importers.NewCSVReader().Read(payload)
+
+	// Then I expect an error locating the row instead of a panic
+	var rowError *importers.RowError
+
+	assert.ErrorAs(t, err, &rowError)
+	assert.Equal(t, 2, rowError.Row)
+	assert.ErrorIs(t, err, csv.ErrQuote)
+}
+
+func TestCSVReaderGivenUnsupportedOperationWhenReadThenRowErrorIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a CSV export with an operation that is neither a buy nor a sell
+	payload := "operation,unit-cost,quantity\ndividend,0.50,100\n"
+
+	// When I read the payload
+	_, err := importers.NewCSVReader().Read(payload)
+
+	// Then I expect an error locating the row
+	assert.EqualError(t, err, `row 2: invalid value for "operation": "dividend" is not buy or sell`)
+}
+
+func TestCSVReaderGivenInvalidDateWhenReadThenRowErrorIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a CSV export with a date in another layout
+	payload := "operation,unit-cost,quantity,date\nbuy,10.00,100,10/01/2024\n"
+
+	// When I read the payload
+	_, err := importers.NewCSVReader().Read(payload)
+
+	// Then I expect an error locating the row
+	assert.EqualError(t, err, `row 2: invalid value for "date": "10/01/2024" is not formatted as YYYY-MM-DD`)
+}
@@ -0,0 +1,21 @@
+package importers
+
+import "capital-gains/src/driver"
+
+// Importer reads the operations of a file exported by another system into a request.
+type Importer interface {
+	// Read returns the operations of the file as a single request.
+	//
+	// [param]  payload string           content of the exported file.
+	// [return] driver.Request           request holding the imported operations.
+	// [return] error                    describes what could not be read, as a RowError for row-based files,
+	//                                   or wraps ErrUnsupportedTransaction, along with the request, for the
+	//                                   entries of the file that were skipped for not being buys or sells.
+	Read(payload string) (driver.Request, error)
+}
+
+var (
+	_ Importer = (*CSVReader)(nil)
+	_ Importer = (*B3Reader)(nil)
+	_ Importer = (*OFXReader)(nil)
+)
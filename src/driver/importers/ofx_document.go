@@ -0,0 +1,131 @@
+package importers
+
+import (
+	"html"
+	"strings"
+)
+
+// ofxElement is an element of an OFX document: an aggregate holding other elements,
+// or a leaf holding a value.
+type ofxElement struct {
+	name     string
+	value    string
+	children []*ofxElement
+}
+
+// parseOFX builds the element tree of an OFX document, in either the SGML variant (OFX 1.x),
+// whose leaf elements have no closing tag, or the XML variant (OFX 2.x). Headers, processing
+// instructions and comments are skipped.
+func parseOFX(payload string) *ofxElement {
+	root := &ofxElement{}
+	stack := []*ofxElement{root}
+
+	for tag, text, rest, ok := nextOFXTag(payload); ok; tag, text, rest, ok = nextOFXTag(rest) {
+		switch {
+		case strings.HasPrefix(tag, "?"), strings.HasPrefix(tag, "!"):
+			continue
+		case strings.HasPrefix(tag, "/"):
+			stack = closeElement(stack, strings.ToUpper(strings.TrimSpace(tag[1:])))
+		default:
+			stack = openElement(stack, tag, text)
+		}
+	}
+
+	return root
+}
+
+// nextOFXTag returns the content of the next tag, the text following it up to the tag after,
+// and the rest of the payload.
+func nextOFXTag(payload string) (string, string, string, bool) {
+	start := strings.IndexByte(payload, '<')
+
+	if start < 0 {
+		return "", "", "", false
+	}
+
+	end := strings.IndexByte(payload[start:], '>')
+
+	if end < 0 {
+		return "", "", "", false
+	}
+
+	tag := payload[start+1 : start+end]
+	rest := payload[start+end+1:]
+	text, _, _ := strings.Cut(rest, "<")
+
+	return tag, strings.TrimSpace(text), rest, true
+}
+
+// openElement adds the element to the one on top of the stack. A leaf holding a value on top
+// of the stack is closed first, since SGML leaf elements have no closing tag.
+func openElement(stack []*ofxElement, tag string, text string) []*ofxElement {
+	if current := stack[len(stack)-1]; len(stack) > 1 && current.value != "" {
+		stack = stack[:len(stack)-1]
+	}
+
+	element := &ofxElement{
+		name:  strings.ToUpper(strings.TrimSpace(strings.TrimSuffix(tag, "/"))),
+		value: html.UnescapeString(text),
+	}
+
+	parent := stack[len(stack)-1]
+	parent.children = append(parent.children, element)
+
+	if strings.HasSuffix(tag, "/") {
+		return stack
+	}
+
+	return append(stack, element)
+}
+
+// closeElement pops the stack up to the element with the given name, closing on the way
+// the leaf elements that have no closing tag. Closing tags with no open element are ignored.
+func closeElement(stack []*ofxElement, name string) []*ofxElement {
+	for index := len(stack) - 1; index > 0; index-- {
+		if stack[index].name == name {
+			return stack[:index]
+		}
+	}
+
+	return stack
+}
+
+// child returns the first direct child with the given name, if any.
+func (element *ofxElement) child(name string) *ofxElement {
+	for _, child := range element.children {
+		if child.name == name {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// valueAt returns the value of the leaf at the given path of child names, or an empty string.
+func (element *ofxElement) valueAt(path ...string) string {
+	current := element
+
+	for _, name := range path {
+		if current = current.child(name); current == nil {
+			return ""
+		}
+	}
+
+	return current.value
+}
+
+// findAll returns every descendant with the given name, in document order.
+func (element *ofxElement) findAll(name string) []*ofxElement {
+	found := make([]*ofxElement, 0)
+
+	for _, child := range element.children {
+		if child.name == name {
+			found = append(found, child)
+			continue
+		}
+
+		found = append(found, child.findAll(name)...)
+	}
+
+	return found
+}
@@ -0,0 +1,234 @@
+package importers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"capital-gains/src/driver"
+)
+
+// ofxDateLength and ofxDateTimeLength are the lengths of the date (YYYYMMDD) and the date and
+// time (YYYYMMDDHHMMSS) prefixes of an OFX datetime, which may be followed by milliseconds and a time zone.
+const (
+	ofxDateLength     = 8
+	ofxDateTimeLength = 14
+)
+
+var (
+	// ErrNotOFX is returned when the payload holds no OFX document.
+	ErrNotOFX = errors.New("not an OFX document")
+
+	// ErrUnsupportedTransaction is returned along with the buys and sells of a statement for the
+	// investment transactions it skipped, which are neither.
+	ErrUnsupportedTransaction = errors.New("unsupported transaction")
+)
+
+// OFXReader reads the buy and sell investment transactions of an OFX statement, in either
+// the SGML (OFX 1.x) or the XML (OFX 2.x) variant.
+//
+// Tickers are taken from the security list of the statement, falling back to the security
+// identifier, and each ticker keeps a position of its own. Commissions, fees and taxes add up
+// to the fees of an operation. Any other transaction, such as income or a transfer, is skipped
+// and reported along with the buys and sells, as an error naming each of them.
+type OFXReader struct{}
+
+func NewOFXReader() *OFXReader {
+	return new(OFXReader)
+}
+
+func (reader *OFXReader) Read(payload string) (driver.Request, error) {
+	document := parseOFX(payload)
+	transactionLists := document.findAll("INVTRANLIST")
+
+	if len(transactionLists) == 0 {
+		return driver.Request{}, ErrNotOFX
+	}
+
+	tickers := tickersOf(document)
+	operations := make([]driver.Operation, 0)
+	unsupported := make([]string, 0)
+
+	for _, transactionList := range transactionLists {
+		for _, transaction := range transactionList.children {
+			side, supported := ofxSideOf(transaction.name)
+
+			if !supported {
+				if !isOFXListBoundary(transaction.name) {
+					unsupported = append(unsupported, describeOFXTransaction(transaction))
+				}
+
+				continue
+			}
+
+			operation, err := newOFXOperation(transaction, side, tickers)
+
+			if err != nil {
+				return driver.Request{}, err
+			}
+
+			operations = append(operations, operation)
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return driver.NewRequest(operations), fmt.Errorf("%w: %s", ErrUnsupportedTransaction, strings.Join(unsupported, ", "))
+	}
+
+	return driver.NewRequest(operations), nil
+}
+
+// tickersOf maps the identifier of each security of the statement onto its ticker.
+func tickersOf(document *ofxElement) map[string]string {
+	tickers := make(map[string]string)
+
+	for _, security := range document.findAll("SECINFO") {
+		if ticker := security.valueAt("TICKER"); ticker != "" {
+			tickers[security.valueAt("SECID", "UNIQUEID")] = ticker
+		}
+	}
+
+	return tickers
+}
+
+func ofxSideOf(name string) (string, bool) {
+	switch name {
+	case "BUYSTOCK", "BUYMF", "BUYOTHER", "BUYDEBT":
+		return buySide, true
+	case "SELLSTOCK", "SELLMF", "SELLOTHER", "SELLDEBT":
+		return sellSide, true
+	default:
+		return "", false
+	}
+}
+
+// isOFXListBoundary reports whether the element is the start or end date of a transaction list.
+func isOFXListBoundary(name string) bool {
+	return name == "DTSTART" || name == "DTEND"
+}
+
+func describeOFXTransaction(transaction *ofxElement) string {
+	if id := firstValue(transaction, "FITID"); id != "" {
+		return fmt.Sprintf("%s (%s)", transaction.name, id)
+	}
+
+	return transaction.name
+}
+
+// newOFXOperation maps a buy or sell aggregate, whose details are held by its INVBUY or INVSELL child.
+func newOFXOperation(transaction *ofxElement, side string, tickers map[string]string) (driver.Operation, error) {
+	details := transaction.child("INVBUY")
+
+	if side == sellSide {
+		details = transaction.child("INVSELL")
+	}
+
+	if details == nil {
+		return driver.Operation{}, fmt.Errorf("%w for %s: missing details", ErrInvalidValue, describeOFXTransaction(transaction))
+	}
+
+	operation := driver.Operation{ID: details.valueAt("INVTRAN", "FITID"), Operation: side}
+	securityID := details.valueAt("SECID", "UNIQUEID")
+	operation.Ticker = securityID
+
+	if ticker, exists := tickers[securityID]; exists {
+		operation.Ticker = ticker
+	}
+
+	var err error
+
+	if operation.Date, operation.Time, err = parseOFXDateTime(details.valueAt("INVTRAN", "DTTRADE")); err != nil {
+		return driver.Operation{}, fmt.Errorf("%s: %w", describeOFXTransaction(transaction), err)
+	}
+
+	if operation.Quantity, operation.UnitCost, operation.Fees, err = ofxAmountsOf(details); err != nil {
+		return driver.Operation{}, fmt.Errorf("%s: %w", describeOFXTransaction(transaction), err)
+	}
+
+	return operation, nil
+}
+
+func ofxAmountsOf(details *ofxElement) (int, float64, float64, error) {
+	units, err := parseOFXNumber(details, "UNITS")
+
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if units != math.Trunc(units) || units == 0 {
+		return 0, 0, 0, fmt.Errorf("%w for %q: %v is not a whole number of shares", ErrInvalidValue, "UNITS", units)
+	}
+
+	unitPrice, err := parseOFXNumber(details, "UNITPRICE")
+
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	fees := 0.0
+
+	for _, name := range []string{"COMMISSION", "FEES", "TAXES"} {
+		if details.valueAt(name) == "" {
+			continue
+		}
+
+		amount, err := parseOFXNumber(details, name)
+
+		if err != nil {
+			return 0, 0, 0, err
+		}
+
+		fees += math.Abs(amount)
+	}
+
+	return int(math.Abs(units)), unitPrice, fees, nil
+}
+
+func parseOFXNumber(details *ofxElement, name string) (float64, error) {
+	value := details.valueAt(name)
+	parsed, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("%w for %q: %q is not a number", ErrInvalidValue, name, value)
+	}
+
+	return parsed, nil
+}
+
+// parseOFXDateTime splits an OFX datetime (e.g., 20240110143000.000[-3:BRT]) into the date and
+// time layouts of an operation. The time zone is ignored, as trades are dated in local time.
+func parseOFXDateTime(value string) (string, string, error) {
+	digits := value
+
+	if index := strings.IndexAny(digits, ".["); index >= 0 {
+		digits = digits[:index]
+	}
+
+	if (len(digits) != ofxDateLength && len(digits) != ofxDateTimeLength) || strings.Trim(digits, "0123456789") != "" {
+		return "", "", fmt.Errorf("%w for %q: %q is not an OFX date", ErrInvalidValue, "DTTRADE", value)
+	}
+
+	date := digits[0:4] + "-" + digits[4:6] + "-" + digits[6:8]
+
+	if _, err := time.Parse(driver.DateLayout, date); err != nil {
+		return "", "", fmt.Errorf("%w for %q: %q is not an OFX date", ErrInvalidValue, "DTTRADE", value)
+	}
+
+	if len(digits) == ofxDateLength {
+		return date, "", nil
+	}
+
+	return date, digits[8:10] + ":" + digits[10:12] + ":" + digits[12:14], nil
+}
+
+// firstValue returns the value of the first descendant with the given name, or an empty string.
+func firstValue(element *ofxElement, name string) string {
+	if found := element.findAll(name); len(found) > 0 {
+		return found[0].value
+	}
+
+	return ""
+}
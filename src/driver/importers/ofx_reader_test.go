@@ -0,0 +1,152 @@
+package importers_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/importers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sgmlStatement = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<INVSTMTMSGSRSV1><INVSTMTTRNRS><INVSTMTRS>
+<INVTRANLIST>
+<DTSTART>20240101
+<DTEND>20240229
+<BUYSTOCK>
+<INVBUY>
+<INVTRAN><FITID>1001<DTTRADE>20240110</INVTRAN>
+<SECID><UNIQUEID>BRPETRACNPR6<UNIQUEIDTYPE>ISIN</SECID>
+<UNITS>100.0000<UNITPRICE>10.00<COMMISSION>1.50<FEES>0.30<TOTAL>-1001.80
+</INVBUY>
+<BUYTYPE>BUY
+</BUYSTOCK>
+<SELLSTOCK>
+<INVSELL>
+<INVTRAN><FITID>1002<DTTRADE>20240205143000.000[-3:BRT]</INVTRAN>
+<SECID><UNIQUEID>BRPETRACNPR6<UNIQUEIDTYPE>ISIN</SECID>
+<UNITS>-40<UNITPRICE>12.50<COMMISSION>1.00<TOTAL>499.00
+</INVSELL>
+<SELLTYPE>SELL
+</SELLSTOCK>
+</INVTRANLIST>
+</INVSTMTRS></INVSTMTTRNRS></INVSTMTMSGSRSV1>
+<SECLISTMSGSRSV1><SECLIST>
+<STOCKINFO><SECINFO><SECID><UNIQUEID>BRPETRACNPR6<UNIQUEIDTYPE>ISIN</SECID>
+<SECNAME>PETROBRAS PN<TICKER>PETR4</SECINFO></STOCKINFO>
+</SECLIST></SECLISTMSGSRSV1>
+</OFX>
+`
+
+const xmlStatement = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE"?>
+<OFX>
+  <INVSTMTMSGSRSV1><INVSTMTTRNRS><INVSTMTRS>
+    <INVTRANLIST>
+      <DTSTART>20240101</DTSTART>
+      <DTEND>20240229</DTEND>
+      <BUYSTOCK>
+        <INVBUY>
+          <INVTRAN><FITID>2001</FITID><DTTRADE>20240110</DTTRADE></INVTRAN>
+          <SECID><UNIQUEID>BRVALEACNOR0</UNIQUEID><UNIQUEIDTYPE>ISIN</UNIQUEIDTYPE></SECID>
+          <UNITS>10</UNITS>
+          <UNITPRICE>60.00</UNITPRICE>
+          <TOTAL>-600.00</TOTAL>
+        </INVBUY>
+        <BUYTYPE>BUY</BUYTYPE>
+      </BUYSTOCK>
+    </INVTRANLIST>
+  </INVSTMTRS></INVSTMTTRNRS></INVSTMTMSGSRSV1>
+</OFX>
+`
+
+func TestOFXReaderGivenSGMLStatementWhenReadThenBuysAndSellsAreMapped(t *testing.T) {
+	t.Parallel()
+
+	// Given an OFX 1.x statement with a buy and a sell of a security listed with its ticker
+
+	// When I read the statement
+	request, err := importers.NewOFXReader().Read(sgmlStatement)
+
+	// Then I expect both transactions to be mapped with their date, ticker and fees
+	assert.NoError(t, err)
+
+	expected := []driver.Operation{
+		{
+			ID: "1001", Date: "2024-01-10", Operation: "buy", Ticker: "PETR4",
+			Quantity: 100, UnitCost: 10.00, Fees: 1.80,
+		},
+		{
+			ID: "1002", Date: "2024-02-05", Time: "14:30:00", Operation: "sell", Ticker: "PETR4",
+			Quantity: 40, UnitCost: 12.50, Fees: 1.00,
+		},
+	}
+	assert.Equal(t, expected, request.Operations())
+}
+
+func TestOFXReaderGivenXMLStatementWithoutSecurityListWhenReadThenSecurityIDIsTheTicker(t *testing.T) {
+	t.Parallel()
+
+	// Given an OFX 2.x statement whose security is not listed
+
+	// When I read the statement
+	request, err := importers.NewOFXReader().Read(xmlStatement)
+
+	// Then I expect the buy to be mapped under the security identifier
+	assert.NoError(t, err)
+
+	expected := []driver.Operation{
+		{
+			ID: "2001", Date: "2024-01-10", Operation: "buy", Ticker: "BRVALEACNOR0",
+			Quantity: 10, UnitCost: 60.00,
+		},
+	}
+	assert.Equal(t, expected, request.Operations())
+}
+
+func TestOFXReaderGivenUnsupportedTransactionsWhenReadThenBuysAndSellsAreMappedAndEachOtherOneIsReported(t *testing.T) {
+	t.Parallel()
+
+	// Given a statement with income, a reinvestment and a bank transaction around a buy and a sell
+	payload := `<OFX><INVTRANLIST>
+<BUYSTOCK><INVBUY><INVTRAN><FITID>1<DTTRADE>20240110</INVTRAN>
+<SECID><UNIQUEID>X</SECID><UNITS>2<UNITPRICE>1.00</INVBUY></BUYSTOCK>
+<INCOME><INVTRAN><FITID>2<DTTRADE>20240115</INVTRAN><INCOMETYPE>DIV<TOTAL>10.00</INCOME>
+<REINVEST><INVTRAN><FITID>3<DTTRADE>20240116</INVTRAN><INCOMETYPE>DIV<UNITS>1<UNITPRICE>10.00</REINVEST>
+<INVBANKTRAN><STMTTRN><TRNTYPE>CREDIT<FITID>4<TRNAMT>100.00</STMTTRN></INVBANKTRAN>
+<SELLSTOCK><INVSELL><INVTRAN><FITID>5<DTTRADE>20240120</INVTRAN>
+<SECID><UNIQUEID>X</SECID><UNITS>-1<UNITPRICE>2.00</INVSELL></SELLSTOCK>
+</INVTRANLIST></OFX>`
+
+	// When I read the statement
+	request, err := importers.NewOFXReader().Read(payload)
+
+	// Then I expect the buy and the sell to be mapped
+	expected := []driver.Operation{
+		{ID: "1", Date: "2024-01-10", Operation: "buy", Ticker: "X", Quantity: 2, UnitCost: 1.00},
+		{ID: "5", Date: "2024-01-20", Operation: "sell", Ticker: "X", Quantity: 1, UnitCost: 2.00},
+	}
+	assert.Equal(t, expected, request.Operations())
+
+	// And every other transaction to be named instead of dropped
+	assert.ErrorIs(t, err, importers.ErrUnsupportedTransaction)
+	assert.EqualError(t, err, "unsupported transaction: INCOME (2), REINVEST (3), INVBANKTRAN (4)")
+}
+
+func TestOFXReaderGivenPayloadWithoutTransactionsWhenReadThenErrNotOFXIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a payload that is not an OFX statement
+	payload := "operation,unit-cost,quantity\n"
+
+	// When I read the payload
+	_, err := importers.NewOFXReader().Read(payload)
+
+	// Then I expect it to be rejected
+	assert.ErrorIs(t, err, importers.ErrNotOFX)
+}
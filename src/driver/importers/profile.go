@@ -0,0 +1,187 @@
+package importers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	buySide  = "buy"
+	sellSide = "sell"
+
+	defaultDelimiter        = ","
+	defaultDecimalSeparator = "."
+	defaultDateFormat       = "YYYY-MM-DD"
+)
+
+var (
+	// ErrProfileNotFound is returned when no profile with the requested name is defined.
+	ErrProfileNotFound = errors.New("profile not found")
+
+	// ErrInvalidProfile is returned when a profile cannot be used to read a file.
+	ErrInvalidProfile = errors.New("invalid profile")
+)
+
+// Profile maps the layout of a third-party CSV export onto the fields of an operation.
+// Every setting is optional and defaults to the layout read by the plain CSV input.
+type Profile struct {
+	// Delimiter separates the cells of a row (e.g., ";").
+	Delimiter string `json:"delimiter"`
+
+	// DecimalSeparator separates the decimal places of a number (e.g., "," in 1.234,56).
+	DecimalSeparator string `json:"decimal-separator"`
+
+	// ThousandsSeparator groups the digits of a number, and is dropped before parsing it.
+	ThousandsSeparator string `json:"thousands-separator"`
+
+	// DateFormat is the format of the date column using the tokens YYYY, YY, MM, DD,
+	// and optionally HH, mm and ss when the date column also carries the time of day.
+	DateFormat string `json:"date-format"`
+
+	// Columns maps each field of an operation to the header of the column holding it.
+	Columns map[string]string `json:"columns"`
+
+	// Sides lists the labels meaning "buy" and "sell" in the operation column (e.g., "C" and "V").
+	Sides map[string][]string `json:"sides"`
+
+	// SideFromSign names the field whose sign tells the side when there is no operation
+	// column: negative values are sells and positive values are buys.
+	SideFromSign string `json:"side-from-sign"`
+}
+
+// DefaultProfile returns the profile of the plain CSV input, whose columns are named after the fields.
+func DefaultProfile() Profile {
+	return Profile{}.withDefaults()
+}
+
+// LoadProfile reads the profile with the given name from a JSON config file holding
+// a "profiles" object keyed by profile name.
+func LoadProfile(path string, name string) (Profile, error) {
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		return Profile{}, fmt.Errorf("reading profiles: %w", err)
+	}
+
+	var config struct {
+		Profiles map[string]Profile `json:"profiles"`
+	}
+
+	if err = json.Unmarshal(content, &config); err != nil {
+		return Profile{}, fmt.Errorf("reading profiles from %s: %w", path, err)
+	}
+
+	profile, exists := config.Profiles[name]
+
+	if !exists {
+		return Profile{}, fmt.Errorf("%w: %q in %s", ErrProfileNotFound, name, path)
+	}
+
+	profile = profile.withDefaults()
+
+	if err = profile.validate(); err != nil {
+		return Profile{}, fmt.Errorf("profile %q: %w", name, err)
+	}
+
+	return profile, nil
+}
+
+func (profile Profile) withDefaults() Profile {
+	if profile.Delimiter == "" {
+		profile.Delimiter = defaultDelimiter
+	}
+
+	if profile.DecimalSeparator == "" {
+		profile.DecimalSeparator = defaultDecimalSeparator
+	}
+
+	if profile.DateFormat == "" {
+		profile.DateFormat = defaultDateFormat
+	}
+
+	columns := make(map[string]string, len(knownColumns()))
+
+	for _, column := range knownColumns() {
+		columns[column] = column
+	}
+
+	for field, header := range profile.Columns {
+		columns[strings.ToLower(strings.TrimSpace(field))] = header
+	}
+
+	profile.Columns = columns
+
+	if len(profile.Sides) == 0 {
+		profile.Sides = map[string][]string{buySide: {buySide}, sellSide: {sellSide}}
+	}
+
+	return profile
+}
+
+func (profile Profile) validate() error {
+	if utf8.RuneCountInString(profile.Delimiter) != 1 {
+		return fmt.Errorf("%w: delimiter %q must be a single character", ErrInvalidProfile, profile.Delimiter)
+	}
+
+	if len(profile.Columns) != len(knownColumns()) {
+		return fmt.Errorf("%w: columns may only map %s", ErrInvalidProfile, strings.Join(knownColumns(), ", "))
+	}
+
+	for side := range profile.Sides {
+		if side != buySide && side != sellSide {
+			return fmt.Errorf("%w: sides may only be %q or %q, got %q", ErrInvalidProfile, buySide, sellSide, side)
+		}
+	}
+
+	if profile.SideFromSign != "" && profile.SideFromSign != quantityColumn && profile.SideFromSign != unitCostColumn {
+		return fmt.Errorf("%w: side-from-sign must be %q or %q", ErrInvalidProfile, quantityColumn, unitCostColumn)
+	}
+
+	return nil
+}
+
+// sideOf returns the side a label of the operation column stands for, matched regardless of case.
+func (profile Profile) sideOf(label string) (string, bool) {
+	for side, labels := range profile.Sides {
+		for _, candidate := range labels {
+			if strings.EqualFold(strings.TrimSpace(candidate), label) {
+				return side, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// normalizeNumber rewrites a number in the profile notation into dot-decimal notation.
+func (profile Profile) normalizeNumber(value string) string {
+	if profile.ThousandsSeparator != "" {
+		value = strings.ReplaceAll(value, profile.ThousandsSeparator, "")
+	}
+
+	return strings.Replace(value, profile.DecimalSeparator, ".", 1)
+}
+
+// dateLayout translates the tokens of the date format into a Go time layout.
+// Longer tokens come first so that YYYY is not read as two YY.
+func (profile Profile) dateLayout() string {
+	replacer := strings.NewReplacer(
+		"YYYY", "2006",
+		"YY", "06",
+		"MM", "01",
+		"DD", "02",
+		"HH", "15",
+		"mm", "04",
+		"ss", "05",
+	)
+
+	return replacer.Replace(profile.DateFormat)
+}
+
+func (profile Profile) hasTimeInDate() bool {
+	return strings.Contains(profile.DateFormat, "HH")
+}
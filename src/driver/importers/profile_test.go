@@ -0,0 +1,109 @@
+package importers_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/importers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeProfiles(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "profiles.json")
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadProfileGivenBrazilianBrokerLayoutWhenReadThenOperationsAreMapped(t *testing.T) {
+	t.Parallel()
+
+	// Given a profile mapping a Brazilian broker export, with labels for each side
+	path := writeProfiles(t, `{"profiles":{"broker-br":{
+		"delimiter":";","decimal-separator":",","thousands-separator":".","date-format":"DD/MM/YYYY",
+		"columns":{"date":"Data","operation":"C/V","ticker":"Ativo","quantity":"Quantidade","unit-cost":"Preço"},
+		"sides":{"buy":["C","Compra"],"sell":["V","Venda"]}}}}`)
+
+	// And an export in that layout
+	payload := "Data;C/V;Ativo;Quantidade;Preço\n" +
+		"10/01/2024;C;PETR4;1.000;10,50\n" +
+		"05/02/2024;Venda;PETR4;500;1.234,56\n"
+
+	// When I load the profile and read the export with it
+	profile, err := importers.LoadProfile(path, "broker-br")
+	assert.NoError(t, err)
+
+	request, err := importers.NewCSVReaderWith(profile).Read(payload)
+
+	// Then I expect the rows to be mapped onto operations
+	assert.NoError(t, err)
+
+	expected := []driver.Operation{
+		{Date: "2024-01-10", Operation: "buy", Ticker: "PETR4", Quantity: 1000, UnitCost: 10.50},
+		{Date: "2024-02-05", Operation: "sell", Ticker: "PETR4", Quantity: 500, UnitCost: 1234.56},
+	}
+	assert.Equal(t, expected, request.Operations())
+}
+
+func TestLoadProfileGivenSideFromSignWhenReadThenNegativeQuantitiesAreSells(t *testing.T) {
+	t.Parallel()
+
+	// Given a profile telling the side by the sign of the quantity, with the time in the date column
+	path := writeProfiles(t, `{"profiles":{"tracker":{
+		"date-format":"YYYY-MM-DD HH:mm:ss","side-from-sign":"quantity",
+		"columns":{"date":"Timestamp","quantity":"Shares","unit-cost":"Price"}}}}`)
+
+	// And an export with signed quantities
+	payload := "Timestamp,Shares,Price\n" +
+		"2024-01-10 10:15:00,100,10.00\n" +
+		"2024-01-10 15:30:00,-40,12.00\n"
+
+	// When I load the profile and read the export with it
+	profile, err := importers.LoadProfile(path, "tracker")
+	assert.NoError(t, err)
+
+	request, err := importers.NewCSVReaderWith(profile).Read(payload)
+
+	// Then I expect the side to follow the sign and the quantity to be absolute
+	assert.NoError(t, err)
+
+	expected := []driver.Operation{
+		{Date: "2024-01-10", Time: "10:15:00", Operation: "buy", Quantity: 100, UnitCost: 10.00},
+		{Date: "2024-01-10", Time: "15:30:00", Operation: "sell", Quantity: 40, UnitCost: 12.00},
+	}
+	assert.Equal(t, expected, request.Operations())
+}
+
+func TestLoadProfileGivenUnknownProfileWhenLoadThenErrProfileNotFoundIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a config file without the requested profile
+	path := writeProfiles(t, `{"profiles":{}}`)
+
+	// When I load the profile
+	_, err := importers.LoadProfile(path, "missing")
+
+	// Then I expect the profile not to be found
+	assert.ErrorIs(t, err, importers.ErrProfileNotFound)
+}
+
+func TestLoadProfileGivenUnknownFieldMappingWhenLoadThenErrInvalidProfileIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a profile mapping a column onto a field operations do not have
+	path := writeProfiles(t, `{"profiles":{"broken":{"columns":{"price":"Preço"}}}}`)
+
+	// When I load the profile
+	_, err := importers.LoadProfile(path, "broken")
+
+	// Then I expect the profile to be rejected
+	assert.ErrorIs(t, err, importers.ErrInvalidProfile)
+}
@@ -0,0 +1,48 @@
+package importers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrMissingColumn is returned when the header lacks a column required to build an operation.
+	ErrMissingColumn = errors.New("missing column")
+
+	// ErrInvalidValue is returned when a cell cannot be converted into the field it maps to.
+	ErrInvalidValue = errors.New("invalid value")
+)
+
+// RowError reports a row of an imported file that could not be read. Rows are numbered as
+// the lines of the file, so the header is row 1 and the first operation usually row 2.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (rowError *RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", rowError.Row, rowError.Err)
+}
+
+func (rowError *RowError) Unwrap() error {
+	return rowError.Err
+}
+
+// rowOf returns the row the reader has just read, or the one a row that could not be read
+// starts at, since the reader keeps no position for such a row.
+func rowOf(csvReader *csv.Reader, err error) int {
+	var parseError *csv.ParseError
+
+	if errors.As(err, &parseError) {
+		return parseError.StartLine
+	}
+
+	if err != nil {
+		return 0
+	}
+
+	row, _ := csvReader.FieldPos(0)
+
+	return row
+}
@@ -0,0 +1,81 @@
+package jsonrpc
+
+import "encoding/json"
+
+// version is the only version of the protocol spoken by the daemon.
+const version = "2.0"
+
+// Error codes defined by JSON-RPC 2.0.
+const (
+	// ParseError means the message is not valid JSON.
+	ParseError = -32700
+
+	// InvalidRequest means the message is not a valid request object.
+	InvalidRequest = -32600
+
+	// MethodNotFound means the method does not exist.
+	MethodNotFound = -32601
+
+	// InvalidParams means the params cannot be read as a simulation, or the simulation cannot be
+	// calculated; its validation errors are given as the data of the error.
+	InvalidParams = -32602
+
+	// InternalError means the request failed unexpectedly while being answered; the session goes on
+	// with the next one.
+	InternalError = -32603
+)
+
+// request is a call of a method. A request without id is a notification, which gets no response.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// isNotification tells whether the request was sent without id, so no response is expected.
+func (request request) isNotification() bool {
+	return request.ID == nil
+}
+
+// response holds either the result of a request or its error, along with the id of the request.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is the error object of a response.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func newError(code int, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// WithData returns the error carrying the details of what went wrong.
+func (err *Error) WithData(data any) *Error {
+	withData := *err
+	withData.Data = data
+
+	return &withData
+}
+
+// newResult returns the response to the request with the given result.
+func newResult(id json.RawMessage, result string) response {
+	return response{JSONRPC: version, ID: id, Result: json.RawMessage(result)}
+}
+
+// newErrorResponse returns the response to the request with the given error. Requests whose id
+// could not be read are answered with a null id.
+func newErrorResponse(id json.RawMessage, err *Error) response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+
+	return response{JSONRPC: version, ID: id, Error: err}
+}
@@ -0,0 +1,98 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"os"
+
+	"capital-gains/src/driver/commandbus"
+	"capital-gains/src/driver/parsers"
+)
+
+// Server answers JSON-RPC 2.0 requests, one message per line, over any stream: the standard streams
+// or the connections of a Unix domain socket. Each stream is a session of its own, holding the
+// portfolio its operations are applied to until it ends.
+type Server struct {
+	settings     Settings
+	unitsOfWork  commandbus.UnitOfWorkFactory
+	parser       *parsers.OperationsParser
+	strictParser *parsers.StrictOperationsParser
+}
+
+// NewServer returns the server calculating each request within a unit of work of its own, begun by
+// the given factory, which also begins the portfolio of each session.
+func NewServer(settings Settings, unitsOfWork commandbus.UnitOfWorkFactory) *Server {
+	return &Server{
+		settings:     settings,
+		unitsOfWork:  unitsOfWork,
+		parser:       parsers.NewOperationsParser(),
+		strictParser: parsers.NewStrictOperationsParser(),
+	}
+}
+
+// Serve answers the messages read from the input until EOF, writing the reply to each one as a line
+// of the output. Blank lines are skipped.
+func (server *Server) Serve(input io.Reader, output io.Writer) error {
+	session := server.newSession()
+	reader := bufio.NewReader(input)
+
+	for {
+		message, err := reader.ReadBytes('\n')
+
+		if len(bytes.TrimSpace(message)) > 0 {
+			if writeErr := session.reply(message, output); writeErr != nil {
+				return writeErr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ServeListener answers the messages of every connection accepted by the listener, each one in a
+// session of its own, until the listener is closed.
+func (server *Server) ServeListener(listener net.Listener) error {
+	for {
+		connection, err := listener.Accept()
+
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			defer func() { _ = connection.Close() }()
+
+			_ = server.Serve(connection, connection)
+		}()
+	}
+}
+
+// Listen listens on the Unix domain socket at the given path. A socket file left behind by a server
+// that did not stop cleanly is removed first, while one another server still listens on is kept, so
+// listening fails as the path is in use.
+func Listen(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		connection, dialErr := net.Dial("unix", path)
+
+		if dialErr == nil {
+			_ = connection.Close()
+		} else if removeErr := os.Remove(path); removeErr != nil {
+			return nil, removeErr
+		}
+	}
+
+	return net.Listen("unix", path)
+}
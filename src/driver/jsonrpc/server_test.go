@@ -0,0 +1,305 @@
+package jsonrpc_test
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"capital-gains/src/driver/commandbus"
+	"capital-gains/src/driver/jsonrpc"
+	"capital-gains/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const taxedOperations = `[{"operation":"buy","unit-cost":10.00,"quantity":10000},` +
+	`{"operation":"sell","unit-cost":20.00,"quantity":5000}]`
+
+// serve answers the messages as a single session of a server with the given settings, returning the
+// lines it replied with.
+func serve(t *testing.T, settings jsonrpc.Settings, messages ...string) []string {
+	t.Helper()
+
+	var output bytes.Buffer
+
+	err := jsonrpc.NewServer(settings, test.NewUnitOfWork).Serve(strings.NewReader(strings.Join(messages, "\n")), &output)
+	assert.NoError(t, err)
+
+	return strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+}
+
+func TestServerGivenCalculateRequestWhenServedThenTaxesAreTheResult(t *testing.T) {
+	t.Parallel()
+
+	// Given a request calculating a buy and a taxed sell
+	message := `{"jsonrpc":"2.0","id":1,"method":"calculate","params":` + taxedOperations + `}`
+
+	// When the server answers it
+	replies := serve(t, jsonrpc.Settings{}, message)
+
+	// Then I expect the tax array, as the console writes it, as the result
+	assert.Equal(t, []string{`{"jsonrpc":"2.0","id":1,"result":[{"tax":0.00},{"tax":10000.00}]}`}, replies)
+}
+
+func TestServerGivenExplainRequestWhenServedThenExplanationIsTheResult(t *testing.T) {
+	t.Parallel()
+
+	// Given a request explaining a single buy
+	message := `{"jsonrpc":"2.0","id":"explain-1","method":"explain",` +
+		`"params":{"operations":[{"operation":"buy","unit-cost":10.00,"quantity":100}]}}`
+
+	// When the server answers it
+	replies := serve(t, jsonrpc.Settings{}, message)
+
+	// Then I expect the step-by-step calculation as the result
+	expected := `{"jsonrpc":"2.0","id":"explain-1","result":{"operations":[{"index":0,"operation":"buy","quantity":100,` +
+		`"unit-cost":10.00,"applied-at":0,"gain":0.00,"deducted-loss":0.00,"tax":0.00,` +
+		`"position":{"quantity":100,"average-unit-cost":10.00,"accumulated-loss":0.00}}],"reordered":[]}}`
+	assert.Equal(t, []string{expected}, replies)
+}
+
+func TestServerGivenOperationsAppliedToThePortfolioWhenServedThenPositionIsCarriedOver(t *testing.T) {
+	t.Parallel()
+
+	// Given a buy applied to the portfolio, then a sell, then the position asked for
+	messages := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"portfolio.apply","params":[{"operation":"buy","unit-cost":10.00,"quantity":10000}]}`,
+		`{"jsonrpc":"2.0","id":2,"method":"portfolio.apply","params":[{"operation":"sell","unit-cost":20.00,"quantity":5000}]}`,
+		`{"jsonrpc":"2.0","id":3,"method":"position.get"}`,
+	}
+
+	// When the server answers them in the same session
+	replies := serve(t, jsonrpc.Settings{}, messages...)
+
+	// Then I expect the sell to be taxed on the buy applied before it, and the position it left
+	expected := []string{
+		`{"jsonrpc":"2.0","id":1,"result":[{"tax":0.00}]}`,
+		`{"jsonrpc":"2.0","id":2,"result":[{"tax":10000.00}]}`,
+		`{"jsonrpc":"2.0","id":3,"result":[{"quantity":5000,"average-unit-cost":10.00,"accumulated-loss":0.00}]}`,
+	}
+	assert.Equal(t, expected, replies)
+}
+
+func TestServerGivenOperationAppliedTwiceToThePortfolioWhenServedThenItIsAppliedOnce(t *testing.T) {
+	t.Parallel()
+
+	// Given the same identified buy applied twice to the portfolio, then the position asked for
+	messages := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"portfolio.apply","params":[{"id":"t1","operation":"buy","unit-cost":10.00,"quantity":10000}]}`,
+		`{"jsonrpc":"2.0","id":2,"method":"portfolio.apply","params":[{"id":"t1","operation":"buy","unit-cost":10.00,"quantity":10000}]}`,
+		`{"jsonrpc":"2.0","id":3,"method":"position.get"}`,
+	}
+
+	// When the server answers them in the same session
+	replies := serve(t, jsonrpc.Settings{}, messages...)
+
+	// Then I expect the second one to be ignored, leaving the position of a single buy
+	expected := []string{
+		`{"jsonrpc":"2.0","id":1,"result":[{"id":"t1","tax":0.00}]}`,
+		`{"jsonrpc":"2.0","id":2,"result":[]}`,
+		`{"jsonrpc":"2.0","id":3,"result":[{"quantity":10000,"average-unit-cost":10.00,"accumulated-loss":0.00}]}`,
+	}
+	assert.Equal(t, expected, replies)
+}
+
+func TestServerGivenInvalidOperationsAppliedToThePortfolioWhenServedThenNoneIsApplied(t *testing.T) {
+	t.Parallel()
+
+	// Given a buy applied to the portfolio along with an unsupported operation
+	messages := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"portfolio.apply","params":[{"operation":"buy","unit-cost":10.00,"quantity":100},` +
+			`{"operation":"hold","unit-cost":10.00,"quantity":100}]}`,
+		`{"jsonrpc":"2.0","id":2,"method":"position.get"}`,
+	}
+
+	// When the server answers them in the same session
+	replies := serve(t, jsonrpc.Settings{}, messages...)
+
+	// Then I expect the params to be rejected and the portfolio to be left empty
+	assert.Contains(t, replies[0], `"error":{"code":-32602,`)
+	assert.Contains(t, replies[0], `"data":{"errors":[{"index":1,"field":"operation","code":"unsupported-operation",`)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":2,"result":[{"quantity":0,"average-unit-cost":0.00,"accumulated-loss":0.00}]}`, replies[1])
+}
+
+func TestServerGivenInvalidMessagesWhenServedThenErrorsAreReplied(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		settings jsonrpc.Settings
+		message  string
+		expected string
+	}{
+		{
+			name:     "not JSON",
+			message:  `{"jsonrpc":"2.0",`,
+			expected: `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error: not valid JSON"}}`,
+		},
+		{
+			name:    "another version",
+			message: `{"jsonrpc":"1.0","id":1,"method":"calculate","params":[]}`,
+			expected: `{"jsonrpc":"2.0","id":1,"error":{"code":-32600,` +
+				`"message":"invalid request: expected an object with jsonrpc \"2.0\" and a method"}}`,
+		},
+		{
+			name:     "empty batch",
+			message:  `[]`,
+			expected: `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"invalid request: a batch must hold at least one request"}}`,
+		},
+		{
+			name:     "unknown method",
+			message:  `{"jsonrpc":"2.0","id":1,"method":"portfolio.reset"}`,
+			expected: `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found: portfolio.reset"}}`,
+		},
+		{
+			name:     "unknown field in strict mode",
+			settings: jsonrpc.Settings{Strict: true},
+			message:  `{"jsonrpc":"2.0","id":1,"method":"calculate","params":[{"operation":"buy","unitcost":10.00,"quantity":100}]}`,
+			expected: `"code":-32602,`,
+		},
+		{
+			name:     "undated operation in chronological mode",
+			settings: jsonrpc.Settings{Chronological: true},
+			message:  `{"jsonrpc":"2.0","id":1,"method":"calculate","params":[{"operation":"buy","unit-cost":10.00,"quantity":100}]}`,
+			expected: `"code":-32602,`,
+		},
+		{
+			name: "corrections applied to the portfolio",
+			message: `{"jsonrpc":"2.0","id":1,"method":"portfolio.apply","params":{"operations":[{"id":"a","operation":"buy",` +
+				`"unit-cost":10.00,"quantity":100}],"corrections":[{"action":"cancel","id":"a"}]}}`,
+			expected: `"message":"invalid params: corrections recalculate a whole history, not a portfolio"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given an invalid message
+			message := testCase.message
+
+			// When the server answers it
+			replies := serve(t, testCase.settings, message)
+
+			// Then I expect its error to be replied
+			assert.Len(t, replies, 1)
+			assert.Contains(t, replies[0], testCase.expected)
+		})
+	}
+}
+
+func TestServerGivenRequestFailingUnexpectedlyWhenServedThenInternalErrorIsRepliedAndTheSessionGoesOn(t *testing.T) {
+	t.Parallel()
+
+	// Given a server whose units of work fail on every command, and two requests
+	server := jsonrpc.NewServer(jsonrpc.Settings{}, func() commandbus.UnitOfWork { return commandbus.UnitOfWork{} })
+	messages := `{"jsonrpc":"2.0","id":1,"method":"calculate","params":[]}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"position.reset"}` + "\n"
+
+	// When the server answers them
+	var output bytes.Buffer
+
+	err := server.Serve(strings.NewReader(messages), &output)
+
+	// Then I expect an internal error for the failing request, and the next one still answered
+	replies := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.NoError(t, err)
+	assert.Len(t, replies, 2)
+	assert.Contains(t, replies[0], `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"internal error: `)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"method not found: position.reset"}}`, replies[1])
+}
+
+func TestServerGivenBatchWithNotificationsWhenServedThenOnlyRequestsAreAnswered(t *testing.T) {
+	t.Parallel()
+
+	// Given a batch of a notification and a request, then a notification on its own
+	messages := []string{
+		`[{"jsonrpc":"2.0","method":"portfolio.apply","params":[{"operation":"buy","unit-cost":10.00,"quantity":100}]},` +
+			`{"jsonrpc":"2.0","id":1,"method":"position.get"}]`,
+		`{"jsonrpc":"2.0","method":"calculate","params":[]}`,
+	}
+
+	// When the server answers them
+	replies := serve(t, jsonrpc.Settings{}, messages...)
+
+	// Then I expect a single reply, holding the response to the request, the notification still applied
+	expected := `[{"jsonrpc":"2.0","id":1,"result":[{"quantity":100,"average-unit-cost":10.00,"accumulated-loss":0.00}]}]`
+	assert.Equal(t, []string{expected}, replies)
+}
+
+func TestServerGivenUnixSocketWhenConnectedThenEachConnectionHasAPortfolioOfItsOwn(t *testing.T) {
+	t.Parallel()
+
+	// Given a server listening on a Unix domain socket
+	listener, err := net.Listen("unix", filepath.Join(t.TempDir(), "capital-gains.sock"))
+	assert.NoError(t, err)
+
+	served := make(chan error, 1)
+
+	go func() { served <- jsonrpc.NewServer(jsonrpc.Settings{}, test.NewUnitOfWork).ServeListener(listener) }()
+
+	// When two connections apply a buy each to their portfolio and ask for its position
+	replies := make([]string, 0, 2)
+
+	for range 2 {
+		connection, err := net.Dial("unix", listener.Addr().String())
+		assert.NoError(t, err)
+
+		_, err = connection.Write([]byte(
+			`{"jsonrpc":"2.0","method":"portfolio.apply","params":[{"operation":"buy","unit-cost":10.00,"quantity":100}]}` + "\n" +
+				`{"jsonrpc":"2.0","id":1,"method":"position.get"}` + "\n",
+		))
+		assert.NoError(t, err)
+
+		reply, err := bufio.NewReader(connection).ReadString('\n')
+		assert.NoError(t, err)
+
+		replies = append(replies, reply)
+		assert.NoError(t, connection.Close())
+	}
+
+	// Then I expect each one to hold only its own buy, and the server to stop once the listener is closed
+	expected := `{"jsonrpc":"2.0","id":1,"result":[{"quantity":100,"average-unit-cost":10.00,"accumulated-loss":0.00}]}` + "\n"
+	assert.Equal(t, []string{expected, expected}, replies)
+	assert.NoError(t, listener.Close())
+	assert.NoError(t, <-served)
+}
+
+func TestListenGivenSocketFileLeftBehindWhenListeningThenItIsReplaced(t *testing.T) {
+	t.Parallel()
+
+	// Given the socket file of a server that stopped without removing it
+	path := filepath.Join(t.TempDir(), "capital-gains.sock")
+	stale, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	assert.NoError(t, stale.Close())
+
+	// When listening on its path
+	listener, err := jsonrpc.Listen(path)
+
+	// Then I expect a listener of its own on that path
+	assert.NoError(t, err)
+	assert.NoError(t, listener.Close())
+}
+
+func TestListenGivenSocketInUseWhenListeningThenItFails(t *testing.T) {
+	t.Parallel()
+
+	// Given a socket another server listens on
+	path := filepath.Join(t.TempDir(), "capital-gains.sock")
+	live, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+
+	defer func() { _ = live.Close() }()
+
+	// When listening on its path
+	_, err = jsonrpc.Listen(path)
+
+	// Then I expect listening to fail, the other server keeping its socket
+	assert.Error(t, err)
+}
@@ -0,0 +1,253 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"capital-gains/src/application/commands"
+	"capital-gains/src/application/domain/models"
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/commandbus"
+)
+
+// Methods offered by the daemon.
+const (
+	calculateMethod      = "calculate"
+	explainMethod        = "explain"
+	portfolioApplyMethod = "portfolio.apply"
+	positionGetMethod    = "position.get"
+)
+
+// session answers the messages of a single stream, one at a time, applying the operations given to
+// portfolio.apply to the portfolio it holds until the stream ends.
+type session struct {
+	server    *Server
+	portfolio commandbus.UnitOfWork
+}
+
+func (server *Server) newSession() *session {
+	return &session{server: server, portfolio: server.unitsOfWork()}
+}
+
+// reply writes the reply to a message, a single request or a batch of them, as a line of the output.
+// Nothing is written when only notifications were sent.
+func (session *session) reply(message []byte, output io.Writer) error {
+	message = bytes.TrimSpace(message)
+
+	var reply any
+
+	switch {
+	case !json.Valid(message):
+		reply = newErrorResponse(nil, newError(ParseError, "parse error: not valid JSON"))
+	case message[0] == '[':
+		reply = session.answerBatch(message)
+	default:
+		if response, ok := session.answer(message); ok {
+			reply = response
+		}
+	}
+
+	if reply == nil {
+		return nil
+	}
+
+	serializedReply, err := json.Marshal(reply)
+
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = output.Write(append(serializedReply, '\n'))
+
+	return err
+}
+
+// answerBatch returns the responses to the requests of a batch, nil when all of them were notifications.
+func (session *session) answerBatch(message []byte) any {
+	var requests []json.RawMessage
+
+	if err := json.Unmarshal(message, &requests); err != nil || len(requests) == 0 {
+		return newErrorResponse(nil, newError(InvalidRequest, "invalid request: a batch must hold at least one request"))
+	}
+
+	responses := make([]response, 0, len(requests))
+
+	for _, request := range requests {
+		if response, ok := session.answer(request); ok {
+			responses = append(responses, response)
+		}
+	}
+
+	if len(responses) == 0 {
+		return nil
+	}
+
+	return responses
+}
+
+// answer returns the response to a single request, or false when it is a notification.
+func (session *session) answer(message json.RawMessage) (response, bool) {
+	var request request
+
+	if err := json.Unmarshal(message, &request); err != nil || request.JSONRPC != version || request.Method == "" {
+		detail := "invalid request: expected an object with jsonrpc \"2.0\" and a method"
+
+		return newErrorResponse(request.ID, newError(InvalidRequest, detail)), true
+	}
+
+	result, err := session.callSafely(request.Method, request.Params)
+
+	if request.isNotification() {
+		return response{}, false
+	}
+
+	if err != nil {
+		return newErrorResponse(request.ID, err), true
+	}
+
+	return newResult(request.ID, result), true
+}
+
+// callSafely runs the method as call does, answering an unexpected failure with an internal error
+// rather than letting it end the session, and the process along with it.
+func (session *session) callSafely(method string, params json.RawMessage) (result string, err *Error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result, err = "", newError(InternalError, fmt.Sprintf("internal error: %v", recovered))
+		}
+	}()
+
+	return session.call(method, params)
+}
+
+// call runs the method with the given params, returning its result as JSON.
+func (session *session) call(method string, params json.RawMessage) (string, *Error) {
+	switch method {
+	case calculateMethod:
+		return session.calculate(params, func(capitalGains []models.CapitalGain) string {
+			return driver.NewResponse(capitalGains).ToString()
+		})
+	case explainMethod:
+		return session.calculate(params, func(capitalGains []models.CapitalGain) string {
+			return driver.NewExplanation(capitalGains).ToString()
+		})
+	case portfolioApplyMethod:
+		return session.apply(params)
+	case positionGetMethod:
+		return session.positions()
+	default:
+		return "", newError(MethodNotFound, fmt.Sprintf("method not found: %s", method))
+	}
+}
+
+// calculate calculates the simulation given as params within a unit of work of its own, rendering
+// its capital gains, or its tax diff report when it has corrections.
+func (session *session) calculate(params json.RawMessage, render func([]models.CapitalGain) string) (string, *Error) {
+	simulation, err := session.simulationOf(params, session.server.settings.Chronological)
+
+	if err != nil {
+		return "", err
+	}
+
+	command := commands.NewCalculateCapitalGain()
+
+	if session.server.settings.Chronological {
+		command = command.Chronologically()
+	}
+
+	calculation, calculationErr := session.server.unitsOfWork().Calculate(simulation, command)
+
+	if calculationErr != nil {
+		return "", calculationError(calculationErr)
+	}
+
+	if calculation.IsCorrected() {
+		return driver.NewTaxDiffReport(calculation.TaxDiffs()).ToString(), nil
+	}
+
+	return render(calculation.CapitalGains()), nil
+}
+
+// apply applies the operations given as params to the portfolio of the session, after the ones
+// applied before them, returning the tax of each one. Nothing is applied when any of them is invalid.
+func (session *session) apply(params json.RawMessage) (string, *Error) {
+	simulation, err := session.simulationOf(params, false)
+
+	if err != nil {
+		return "", err
+	}
+
+	if simulation.HasCorrections() {
+		return "", newError(InvalidParams, "invalid params: corrections recalculate a whole history, not a portfolio")
+	}
+
+	capitalGains, calculationErr := session.portfolio.Apply(simulation)
+
+	if calculationErr != nil {
+		return "", calculationError(calculationErr)
+	}
+
+	serializedTaxes, marshalErr := json.Marshal(driver.NewStreamedTaxes(capitalGains))
+
+	if marshalErr != nil {
+		panic(marshalErr)
+	}
+
+	return string(serializedTaxes), nil
+}
+
+// positions returns the position of every account of the portfolio of the session, leaving it as it is.
+func (session *session) positions() (string, *Error) {
+	capitalGains, calculationErr := session.portfolio.Apply(driver.Request{})
+
+	if calculationErr != nil {
+		return "", calculationError(calculationErr)
+	}
+
+	return driver.NewPortfolioPositions(capitalGains).ToString(), nil
+}
+
+// calculationError returns the error of params that passed validation but still cannot be calculated.
+func calculationError(err error) *Error {
+	validationErrors := driver.ValidationErrors{driver.NewValidationError(err)}
+
+	return newError(InvalidParams, "invalid params: "+validationErrors.Error()).WithData(validationErrors)
+}
+
+// simulationOf reads the params as a simulation, holding to the contract field by field in strict
+// mode, and validates it.
+func (session *session) simulationOf(params json.RawMessage, chronological bool) (driver.Request, *Error) {
+	simulation, validationErrors := session.parse(params)
+
+	if len(validationErrors) == 0 {
+		validationErrors = simulation.Validate()
+
+		if chronological {
+			validationErrors = append(validationErrors, simulation.ValidateTradeDates()...)
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return driver.Request{}, newError(InvalidParams, "invalid params: "+validationErrors.Error()).WithData(validationErrors)
+	}
+
+	return simulation, nil
+}
+
+func (session *session) parse(params json.RawMessage) (driver.Request, driver.ValidationErrors) {
+	if session.server.settings.Strict {
+		return session.server.strictParser.Parse(string(params))
+	}
+
+	simulation, ok := session.server.parser.Parse(string(params))
+
+	if !ok {
+		err := fmt.Errorf("%w: expected params holding a JSON array of operations or an object with operations", driver.ErrMalformedInput)
+
+		return driver.Request{}, driver.ValidationErrors{driver.NewValidationError(err)}
+	}
+
+	return simulation, nil
+}
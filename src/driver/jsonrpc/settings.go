@@ -0,0 +1,13 @@
+package jsonrpc
+
+// Settings holds the behaviors of the daemon, selected by command-line flags. The zero value keeps
+// the default behavior.
+type Settings struct {
+	// Chronological applies the operations of each calculation in the order they were traded,
+	// instead of the order they were given. Operations applied to a portfolio keep the order they
+	// were given in, since they follow the ones applied before them.
+	Chronological bool
+
+	// Strict rejects params with unknown or absent fields and lossy numbers.
+	Strict bool
+}
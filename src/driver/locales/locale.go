@@ -0,0 +1,185 @@
+package locales
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// currencySymbol is the symbol of the Brazilian real, the currency every amount is in.
+const currencySymbol = "R$"
+
+// ErrInvalidNumber is returned when a text is not a number written in the notation of the locale.
+var ErrInvalidNumber = errors.New("invalid number")
+
+// Locale is the notation numbers are written in. The zero value is no locale: numbers are read and
+// written in the dot-decimal notation of the JSON contract, without digit grouping nor currency symbol.
+type Locale struct {
+	name               string
+	decimalSeparator   string
+	thousandsSeparator string
+	listSeparator      string
+	currencySpacing    string
+}
+
+// BrazilianPortuguese writes 1.234,56 and R$ 1.234,56, separating lists with semicolons.
+func BrazilianPortuguese() Locale {
+	return Locale{name: "pt-BR", decimalSeparator: ",", thousandsSeparator: ".", listSeparator: ";", currencySpacing: " "}
+}
+
+// AmericanEnglish writes 1,234.56 and R$1,234.56, separating lists with commas.
+func AmericanEnglish() Locale {
+	return Locale{name: "en-US", decimalSeparator: ".", thousandsSeparator: ",", listSeparator: ","}
+}
+
+// Parse returns the locale with the given name, regardless of case and of the separator between
+// language and region.
+func Parse(name string) (Locale, error) {
+	switch strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", "-")) {
+	case "pt-br":
+		return BrazilianPortuguese(), nil
+	case "en-us":
+		return AmericanEnglish(), nil
+	default:
+		return Locale{}, fmt.Errorf("unsupported locale %q: expected pt-BR or en-US", name)
+	}
+}
+
+// Name returns the name of the locale, such as pt-BR, or none without locale.
+func (locale Locale) Name() string {
+	return locale.name
+}
+
+// IsZero tells whether no locale was selected.
+func (locale Locale) IsZero() bool {
+	return locale.name == ""
+}
+
+// DecimalSeparator returns the separator of the decimal places, a dot without locale.
+func (locale Locale) DecimalSeparator() string {
+	if locale.IsZero() {
+		return "."
+	}
+
+	return locale.decimalSeparator
+}
+
+// ThousandsSeparator returns the separator grouping the digits, none without locale.
+func (locale Locale) ThousandsSeparator() string {
+	return locale.thousandsSeparator
+}
+
+// ListSeparator returns the separator of the cells of a row, as spreadsheets export them, a comma
+// without locale.
+func (locale Locale) ListSeparator() string {
+	if locale.IsZero() {
+		return ","
+	}
+
+	return locale.listSeparator
+}
+
+// Normalize rewrites a number written in the notation of the locale, optionally preceded by the
+// currency symbol, into the dot-decimal notation of the JSON contract. Digit grouping is optional,
+// but must come in groups of three digits when present.
+func (locale Locale) Normalize(text string) (string, error) {
+	number := strings.TrimSpace(text)
+	sign := ""
+
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", strings.TrimSpace(number[1:])
+	}
+
+	number = strings.TrimSpace(strings.TrimPrefix(number, currencySymbol))
+	wholePart, decimalPart, hasDecimals := strings.Cut(number, locale.DecimalSeparator())
+
+	wholePart, grouped := locale.ungroup(wholePart)
+
+	if !grouped {
+		return "", fmt.Errorf("%w: %q is not grouped in thousands as in %s", ErrInvalidNumber, text, locale.name)
+	}
+
+	if !isDigits(wholePart) || (hasDecimals && !isDigits(decimalPart)) {
+		return "", fmt.Errorf("%w: %q is not a number as written in %s", ErrInvalidNumber, text, locale.name)
+	}
+
+	if hasDecimals {
+		return sign + wholePart + "." + decimalPart, nil
+	}
+
+	return sign + wholePart, nil
+}
+
+// ungroup drops the thousands separators of the whole part of a number, reporting whether they
+// split it into groups of three digits.
+func (locale Locale) ungroup(wholePart string) (string, bool) {
+	if locale.thousandsSeparator == "" || !strings.Contains(wholePart, locale.thousandsSeparator) {
+		return wholePart, true
+	}
+
+	groups := strings.Split(wholePart, locale.thousandsSeparator)
+
+	if groups[0] == "" || len(groups[0]) > 3 {
+		return "", false
+	}
+
+	for _, group := range groups[1:] {
+		if len(group) != 3 {
+			return "", false
+		}
+	}
+
+	return strings.Join(groups, ""), true
+}
+
+// FormatNumber writes the number with the given decimal places in the notation of the locale.
+func (locale Locale) FormatNumber(number float64, decimals int) string {
+	formatted := strconv.FormatFloat(number, 'f', decimals, 64)
+
+	if locale.IsZero() {
+		return formatted
+	}
+
+	sign := ""
+
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+
+	wholePart, decimalPart, hasDecimals := strings.Cut(formatted, ".")
+	groups := make([]string, 0, len(wholePart)/3+1)
+
+	for len(wholePart) > 3 {
+		groups = append([]string{wholePart[len(wholePart)-3:]}, groups...)
+		wholePart = wholePart[:len(wholePart)-3]
+	}
+
+	formatted = sign + strings.Join(append([]string{wholePart}, groups...), locale.thousandsSeparator)
+
+	if hasDecimals {
+		formatted += locale.decimalSeparator + decimalPart
+	}
+
+	return formatted
+}
+
+// FormatAmount writes the amount of money with two decimal places in the notation of the locale,
+// preceded by the currency symbol unless no locale was selected.
+func (locale Locale) FormatAmount(amount float64) string {
+	if locale.IsZero() {
+		return locale.FormatNumber(amount, 2)
+	}
+
+	formatted := locale.FormatNumber(amount, 2)
+
+	if strings.HasPrefix(formatted, "-") {
+		return "-" + currencySymbol + locale.currencySpacing + formatted[1:]
+	}
+
+	return currencySymbol + locale.currencySpacing + formatted
+}
+
+func isDigits(text string) bool {
+	return text != "" && strings.Trim(text, "0123456789") == ""
+}
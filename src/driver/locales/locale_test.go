@@ -0,0 +1,99 @@
+package locales_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driver/locales"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGivenKnownNameWhenParsedThenLocaleIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// When I parse the names of the locales, in any case and with any separator
+	// Then I expect each of them to be recognized
+	for name, expected := range map[string]locales.Locale{
+		"pt-BR": locales.BrazilianPortuguese(),
+		"pt_br": locales.BrazilianPortuguese(),
+		"EN-US": locales.AmericanEnglish(),
+	} {
+		locale, err := locales.Parse(name)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, locale)
+	}
+}
+
+func TestParseGivenUnknownNameWhenParsedThenErrorIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// When I parse the name of a locale that is not supported
+	_, err := locales.Parse("fr-FR")
+
+	// Then I expect an error listing the supported ones
+	assert.EqualError(t, err, `unsupported locale "fr-FR": expected pt-BR or en-US`)
+}
+
+func TestNormalizeGivenBrazilianNumbersWhenNormalizedThenDotDecimalIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// When I normalize numbers written in Brazilian Portuguese, with or without grouping and symbol
+	// Then I expect them in the notation of the JSON contract
+	for text, expected := range map[string]string{
+		"1.234,56":     "1234.56",
+		"1234,5":       "1234.5",
+		"R$ 10.000,00": "10000.00",
+		"-R$ 0,99":     "-0.99",
+		"100":          "100",
+	} {
+		normalized, err := locales.BrazilianPortuguese().Normalize(text)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, normalized)
+	}
+}
+
+func TestNormalizeGivenAmericanNumbersWhenNormalizedThenDotDecimalIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// When I normalize a number written in American English
+	normalized, err := locales.AmericanEnglish().Normalize("R$1,234.56")
+
+	// Then I expect the grouping and the symbol to be dropped
+	assert.NoError(t, err)
+	assert.Equal(t, "1234.56", normalized)
+}
+
+func TestNormalizeGivenMalformedNumbersWhenNormalizedThenErrorIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// When I normalize texts that are not numbers in Brazilian Portuguese
+	// Then I expect each of them to be rejected
+	for _, text := range []string{"1.23,45", "12.3456", ".123", "1,2,3", "abc", "", "1.234.56"} {
+		_, err := locales.BrazilianPortuguese().Normalize(text)
+
+		assert.ErrorIs(t, err, locales.ErrInvalidNumber, text)
+	}
+}
+
+func TestFormatAmountGivenLocaleWhenFormattedThenAmountFollowsIt(t *testing.T) {
+	t.Parallel()
+
+	// When I format amounts in each locale, and without locale
+	// Then I expect the notation of each of them
+	assert.Equal(t, "R$ 1.234.567,89", locales.BrazilianPortuguese().FormatAmount(1234567.89))
+	assert.Equal(t, "-R$ 250,00", locales.BrazilianPortuguese().FormatAmount(-250))
+	assert.Equal(t, "R$1,234.50", locales.AmericanEnglish().FormatAmount(1234.5))
+	assert.Equal(t, "1234.50", locales.Locale{}.FormatAmount(1234.5))
+}
+
+func TestFormatNumberGivenLocaleWhenFormattedThenDigitsAreGrouped(t *testing.T) {
+	t.Parallel()
+
+	// When I format whole and decimal numbers in Brazilian Portuguese
+	// Then I expect the thousands grouped and the decimal comma
+	assert.Equal(t, "10.000", locales.BrazilianPortuguese().FormatNumber(10000, 0))
+	assert.Equal(t, "-999,5", locales.BrazilianPortuguese().FormatNumber(-999.5, 1))
+	assert.Equal(t, "10000", locales.Locale{}.FormatNumber(10000, 0))
+}
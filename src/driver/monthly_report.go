@@ -0,0 +1,72 @@
+package driver
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"capital-gains/src/application/domain/events"
+	"capital-gains/src/application/domain/models"
+)
+
+// MonthlyTax sums up the sells of an account within a month: what they were sold for, the gain
+// they realized (negative for a loss) and the tax due. Undated operations are summed up without a month.
+type MonthlyTax struct {
+	Account  string `json:"account,omitempty"`
+	Month    string `json:"month,omitempty"`
+	Proceeds Amount `json:"proceeds"`
+	Gain     Amount `json:"gain"`
+	Tax      Amount `json:"tax"`
+}
+
+// MonthlyReport is the report output of an input line: the tax due per month of each account,
+// in month order within each account, and the total tax of the line.
+type MonthlyReport struct {
+	Months   []MonthlyTax `json:"months"`
+	TotalTax Amount       `json:"total-tax"`
+}
+
+func NewMonthlyReport(capitalGains []models.CapitalGain) MonthlyReport {
+	report := MonthlyReport{Months: make([]MonthlyTax, 0)}
+
+	for _, capitalGain := range capitalGains {
+		months := make([]MonthlyTax, 0)
+
+		for _, taxEvent := range capitalGain.Events() {
+			trade := taxEvent.Trade()
+			month := formatMonth(trade.TradedAt())
+			index := slices.IndexFunc(months, func(monthlyTax MonthlyTax) bool { return monthlyTax.Month == month })
+
+			if index < 0 {
+				months = append(months, MonthlyTax{Account: capitalGain.Account(), Month: month})
+				index = len(months) - 1
+			}
+
+			if trade.Side() == events.SellSide {
+				months[index].Proceeds += Amount(float64(trade.Quantity()) * trade.UnitCost())
+			}
+
+			months[index].Gain += Amount(taxEvent.Realization().Gain())
+			months[index].Tax += Amount(taxEvent.Amount())
+			report.TotalTax += Amount(taxEvent.Amount())
+		}
+
+		slices.SortStableFunc(months, func(first MonthlyTax, second MonthlyTax) int {
+			return strings.Compare(first.Month, second.Month)
+		})
+
+		report.Months = append(report.Months, months...)
+	}
+
+	return report
+}
+
+func (report MonthlyReport) ToString() string {
+	serializedReport, err := json.Marshal(report)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return string(serializedReport)
+}
@@ -0,0 +1,38 @@
+package driver
+
+import "capital-gains/src/application/commands"
+
+// OpeningBalance is the position an account held before its first operation.
+type OpeningBalance struct {
+	Quantity        int     `json:"quantity"`
+	AverageUnitCost float64 `json:"average-unit-cost"`
+	AccumulatedLoss float64 `json:"accumulated-loss"`
+	Account         string  `json:"account,omitempty"`
+}
+
+func (openingBalance OpeningBalance) ToCommand() commands.Command {
+	return commands.NewRegisterOpeningBalance(
+		openingBalance.Quantity,
+		openingBalance.AverageUnitCost,
+		openingBalance.AccumulatedLoss,
+	).ForAccount(openingBalance.Account)
+}
+
+// Validate returns the problems that keep the opening balance from being registered, all of them at once.
+func (openingBalance OpeningBalance) Validate() ValidationErrors {
+	validationErrors := make(ValidationErrors, 0)
+
+	if openingBalance.Quantity < 0 {
+		validationErrors = append(validationErrors, fieldError("quantity", ErrInvalidValue, "must not be negative"))
+	}
+
+	if openingBalance.AverageUnitCost < 0 {
+		validationErrors = append(validationErrors, fieldError("average-unit-cost", ErrInvalidValue, "must not be negative"))
+	}
+
+	if openingBalance.AccumulatedLoss < 0 {
+		validationErrors = append(validationErrors, fieldError("accumulated-loss", ErrInvalidValue, "must not be negative"))
+	}
+
+	return validationErrors
+}
@@ -1,31 +1,182 @@
 package driver
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"capital-gains/src/application/commands"
+	"capital-gains/src/application/domain/models"
 )
 
 const (
 	buyOperationName  = "buy"
 	sellOperationName = "sell"
+
+	// DateLayout is the layout of the optional trade date of an operation (e.g., 2024-03-15).
+	DateLayout = "2006-01-02"
+
+	// TimeLayout is the layout of the optional trade time of an operation (e.g., 14:30:00).
+	TimeLayout = "15:04:05"
 )
 
 type Operation struct {
 	Quantity  int     `json:"quantity"`
 	UnitCost  float64 `json:"unit-cost"`
 	Operation string  `json:"operation"`
+	ID        string  `json:"id,omitempty"`
+	Date      string  `json:"date,omitempty"`
+	Time      string  `json:"time,omitempty"`
+	Sequence  int     `json:"sequence,omitempty"`
+	Account   string  `json:"account,omitempty"`
+	Ticker    string  `json:"ticker,omitempty"`
+	Fees      float64 `json:"fees,omitempty"`
 }
 
-func (operation Operation) ToCommand() commands.Command {
+// ToCommand returns the command registering the operation, or the validation error of an operation
+// that is neither a buy nor a sell, or whose date or time is not in its format, which Validate
+// reports beforehand.
+func (operation Operation) ToCommand() (commands.Command, error) {
+	metadata, err := operation.metadata()
+
+	if err != nil {
+		return nil, err
+	}
+
 	normalizedOperationName := strings.ToLower(strings.TrimSpace(operation.Operation))
 
 	switch normalizedOperationName {
 	case buyOperationName:
-		return commands.NewRegisterBuy(operation.Quantity, operation.UnitCost)
+		return commands.NewRegisterBuy(operation.Quantity, operation.UnitCost).
+			WithFees(operation.Fees).
+			WithMetadata(metadata), nil
 	case sellOperationName:
-		return commands.NewRegisterSell(operation.Quantity, operation.UnitCost)
+		return commands.NewRegisterSell(operation.Quantity, operation.UnitCost).
+			WithFees(operation.Fees).
+			WithMetadata(metadata), nil
+	default:
+		return nil, fieldError("operation", ErrUnsupportedOperation, fmt.Sprintf("%q is neither buy nor sell", operation.Operation))
+	}
+}
+
+// Validate returns the problems that keep the operation from being registered, all of them at once.
+func (operation Operation) Validate() ValidationErrors {
+	validationErrors := make(ValidationErrors, 0)
+	normalizedOperationName := strings.ToLower(strings.TrimSpace(operation.Operation))
+
+	switch normalizedOperationName {
+	case buyOperationName, sellOperationName:
+	case "":
+		validationErrors = append(validationErrors, fieldError("operation", ErrMissingField, "expected buy or sell"))
+	default:
+		validationErrors = append(validationErrors, fieldError("operation", ErrUnsupportedOperation, fmt.Sprintf("%q is neither buy nor sell", operation.Operation)))
+	}
+
+	if operation.Quantity <= 0 {
+		validationErrors = append(validationErrors, fieldError("quantity", ErrInvalidValue, "must be greater than zero"))
+	}
+
+	if operation.UnitCost < 0 {
+		validationErrors = append(validationErrors, fieldError("unit-cost", ErrInvalidValue, "must not be negative"))
+	}
+
+	if operation.Fees < 0 {
+		validationErrors = append(validationErrors, fieldError("fees", ErrInvalidValue, "must not be negative"))
+	}
+
+	return append(validationErrors, operation.validateTradeMoment()...)
+}
+
+func (operation Operation) validateTradeMoment() ValidationErrors {
+	_, _, validationErrors := operation.tradeMoment()
+
+	return validationErrors
+}
+
+// tradeMoment returns when the operation was traded, the zero time when it is undated, and whether
+// it names the time of day, or the error of a date or a time that is not in its format.
+func (operation Operation) tradeMoment() (time.Time, bool, ValidationErrors) {
+	if operation.Date == "" {
+		if operation.Time != "" {
+			return time.Time{}, false, ValidationErrors{fieldError("date", ErrMissingField, "required along with the time")}
+		}
+
+		return time.Time{}, false, nil
+	}
+
+	tradedOn, err := time.Parse(DateLayout, operation.Date)
+
+	if err != nil {
+		return time.Time{}, false, ValidationErrors{fieldError("date", ErrInvalidValue, fmt.Sprintf("%q is not in the format YYYY-MM-DD", operation.Date))}
+	}
+
+	if operation.Time == "" {
+		return tradedOn, false, nil
+	}
+
+	tradedAt, err := time.Parse(DateLayout+" "+TimeLayout, operation.Date+" "+operation.Time)
+
+	if err != nil {
+		return time.Time{}, false, ValidationErrors{fieldError("time", ErrInvalidValue, fmt.Sprintf("%q is not in the format HH:MM:SS", operation.Time))}
+	}
+
+	return tradedAt, true, nil
+}
+
+// trade returns the operation as the domain places it in time, to order it among the others of its
+// account before it is registered, or the error of a date or a time that is not in its format.
+func (operation Operation) trade() (models.Operation, error) {
+	tradedAt, timed, validationErrors := operation.tradeMoment()
+
+	if len(validationErrors) > 0 {
+		return nil, validationErrors[0]
+	}
+
+	metadata := models.NewMetadata().WithID(operation.ID).WithSequence(operation.Sequence).WithTradedOn(tradedAt)
+
+	if timed {
+		metadata = metadata.WithTradedAt(tradedAt)
+	}
+
+	quantity := models.NewQuantity(operation.Quantity)
+	unitCost := models.NewMonetaryValue(operation.UnitCost)
+
+	if strings.EqualFold(strings.TrimSpace(operation.Operation), sellOperationName) {
+		return models.NewSell(quantity, unitCost).WithMetadata(metadata), nil
+	}
+
+	return models.NewBuy(quantity, unitCost).WithMetadata(metadata), nil
+}
+
+// sequenceError returns the error of an operation traded at the same moment as others of the other
+// side, with no sequence, or the same one as them, telling which came first.
+func (operation Operation) sequenceError() ValidationError {
+	if operation.Sequence == 0 {
+		return fieldError("sequence", ErrMissingField, "required to order the buys and sells traded at the same moment")
+	}
+
+	return fieldError("sequence", ErrInvalidValue, "does not order the operation among the buys and sells traded at the same moment")
+}
+
+// metadata returns the metadata the command of the operation carries, or the error of a date or a
+// time that is not in its format.
+func (operation Operation) metadata() (commands.Metadata, error) {
+	metadata := commands.NewMetadata().
+		WithID(operation.ID).
+		WithSequence(operation.Sequence).
+		WithAccount(operation.Account).
+		WithTicker(operation.Ticker)
+
+	tradedAt, timed, validationErrors := operation.tradeMoment()
+
+	switch {
+	case len(validationErrors) > 0:
+		return commands.Metadata{}, validationErrors[0]
+	case operation.Date == "":
+		return metadata, nil
+	case timed:
+		return metadata.WithTradedAt(tradedAt), nil
 	default:
-		panic("unsupported operation")
+		return metadata.WithTradedOn(tradedAt), nil
 	}
 }
@@ -0,0 +1,104 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/locales"
+)
+
+// numericFields returns the names of the fields holding numbers, in any object of the input.
+func numericFields() map[string]bool {
+	names := make(map[string]bool)
+
+	for _, field := range append(operationFields(), openingBalanceFields()...) {
+		if field.kind == wholeField || field.kind == moneyField || field.kind == decimalField {
+			names[field.name] = true
+		}
+	}
+
+	return names
+}
+
+// LocalizeNumbers rewrites the numbers given as strings in the notation of the locale, such as
+// "1.234,56" in pt-BR, into JSON numbers. Numbers given as JSON numbers are dot-decimal already, while
+// a numeric field holding a string that is not a number in the locale is reported as a validation
+// error, located as the parsers locate them. Payloads that are not JSON are returned as they are.
+func LocalizeNumbers(payload string, locale locales.Locale) (string, driver.ValidationErrors) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(payload)))
+	decoder.UseNumber()
+
+	var value any
+
+	if err := decoder.Decode(&value); err != nil {
+		return payload, nil
+	}
+
+	if validationErrors := localizeValue(value, numericFields(), locale); len(validationErrors) > 0 {
+		return payload, validationErrors
+	}
+
+	return toJSON(value), nil
+}
+
+func localizeValue(value any, names map[string]bool, locale locales.Locale) driver.ValidationErrors {
+	validationErrors := make(driver.ValidationErrors, 0)
+
+	switch typedValue := value.(type) {
+	case []any:
+		return localizeList(typedValue, "operations", names, locale)
+	case map[string]any:
+		for _, name := range slices.Sorted(maps.Keys(typedValue)) {
+			validationErrors = append(validationErrors, localizeField(typedValue, name, names, locale)...)
+		}
+	}
+
+	return validationErrors
+}
+
+// localizeField rewrites the number of the named field of the object, or the ones nested in it.
+func localizeField(object map[string]any, name string, names map[string]bool, locale locales.Locale) driver.ValidationErrors {
+	switch fieldValue := object[name].(type) {
+	case []any:
+		return localizeList(fieldValue, name, names, locale)
+	case string:
+		if !names[name] {
+			return nil
+		}
+
+		normalized, err := locale.Normalize(fieldValue)
+
+		if err != nil {
+			return driver.ValidationErrors{driver.NewValidationError(fmt.Errorf("%w: %w", driver.ErrInvalidValue, err)).WithField(name)}
+		}
+
+		object[name] = json.Number(normalized)
+
+		return nil
+	default:
+		return localizeValue(fieldValue, names, locale).Within(name)
+	}
+}
+
+// localizeList rewrites the numbers of each element of the named list, locating the errors of
+// operations by their index and the others by the path of the field.
+func localizeList(list []any, name string, names map[string]bool, locale locales.Locale) driver.ValidationErrors {
+	validationErrors := make(driver.ValidationErrors, 0)
+
+	for index, element := range list {
+		elementErrors := localizeValue(element, names, locale)
+
+		if name == "operations" {
+			validationErrors = append(validationErrors, elementErrors.WithIndex(index)...)
+			continue
+		}
+
+		validationErrors = append(validationErrors, elementErrors.Within(fmt.Sprintf("%s[%d]", name, index))...)
+	}
+
+	return validationErrors
+}
@@ -0,0 +1,109 @@
+package parsers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"capital-gains/src/driver"
+)
+
+// document is the object form of an input line, carrying an optional header
+// (such as the opening balance or the default account) next to the list of
+// operations and their corrections.
+type document struct {
+	Account         string                  `json:"account"`
+	OpeningBalance  *driver.OpeningBalance  `json:"opening-balance"`
+	OpeningBalances []driver.OpeningBalance `json:"opening-balances"`
+	Operations      []driver.Operation      `json:"operations"`
+	Corrections     []driver.Correction     `json:"corrections"`
+}
+
+type OperationsParser struct{}
+
+func NewOperationsParser() *OperationsParser {
+	return new(OperationsParser)
+}
+
+func (parser *OperationsParser) Parse(payload string) (driver.Request, bool) {
+	trimmedPayload := strings.TrimSpace(payload)
+
+	if trimmedPayload == "" {
+		return driver.Request{}, false
+	}
+
+	if strings.HasPrefix(trimmedPayload, "{") {
+		return parser.parseDocument(trimmedPayload)
+	}
+
+	var operations []driver.Operation
+
+	err := json.Unmarshal([]byte(trimmedPayload), &operations)
+
+	if err != nil {
+		return driver.Request{}, false
+	}
+
+	return driver.NewRequest(operations), true
+}
+
+func (parser *OperationsParser) parseDocument(payload string) (driver.Request, bool) {
+	var parsedDocument document
+
+	err := json.Unmarshal([]byte(payload), &parsedDocument)
+
+	if err != nil || parsedDocument.Operations == nil {
+		return driver.Request{}, false
+	}
+
+	operations := parsedDocument.Operations
+	openingBalances := parsedDocument.OpeningBalances
+
+	if parsedDocument.OpeningBalance != nil {
+		openingBalances = append([]driver.OpeningBalance{*parsedDocument.OpeningBalance}, openingBalances...)
+	}
+
+	for index := range operations {
+		if operations[index].Account == "" {
+			operations[index].Account = parsedDocument.Account
+		}
+	}
+
+	for index := range openingBalances {
+		if openingBalances[index].Account == "" {
+			openingBalances[index].Account = parsedDocument.Account
+		}
+	}
+
+	request := driver.NewRequest(operations).
+		WithOpeningBalances(openingBalances).
+		WithCorrections(parsedDocument.Corrections)
+
+	return request, true
+}
+
+// ParseOperation parses a single operation object, as given in each line of the NDJSON input.
+func (parser *OperationsParser) ParseOperation(payload string) (driver.Operation, bool) {
+	trimmedPayload := strings.TrimSpace(payload)
+
+	if !strings.HasPrefix(trimmedPayload, "{") {
+		return driver.Operation{}, false
+	}
+
+	var operation driver.Operation
+
+	if err := json.Unmarshal([]byte(trimmedPayload), &operation); err != nil {
+		return driver.Operation{}, false
+	}
+
+	return operation, true
+}
+
+// MalformedInput returns the error of a value that cannot be read, telling apart broken JSON.
+func MalformedInput(text string, expected string) driver.ValidationErrors {
+	if !json.Valid([]byte(text)) {
+		expected = "not valid JSON"
+	}
+
+	return driver.ValidationErrors{driver.NewValidationError(fmt.Errorf("%w: %s", driver.ErrMalformedInput, expected))}
+}
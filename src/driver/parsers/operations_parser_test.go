@@ -0,0 +1,92 @@
+package parsers_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/parsers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationsParserParsesValidJsonArray(t *testing.T) {
+	t.Parallel()
+
+	// Given a valid JSON array representing market operations
+	payload := `[{"operation":"buy","unit-cost":10.00,"quantity":100}]`
+	parser := parsers.NewOperationsParser()
+
+	// When parsing the payload
+	request, ok := parser.Parse(payload)
+
+	// Then I expect the parsing to succeed
+	assert.True(t, ok)
+
+	// And I expect a request with a single operation
+	assert.Len(t, request.Operations(), 1)
+}
+
+func TestOperationsParserReturnsFalseWhenPayloadIsNotValidJsonArray(t *testing.T) {
+	t.Parallel()
+
+	// Given an invalid payload that is not a JSON array of operations
+	payload := `this is not json`
+	parser := parsers.NewOperationsParser()
+
+	// When parsing the payload
+	_, ok := parser.Parse(payload)
+
+	// Then I expect the parsing to fail
+	assert.False(t, ok)
+}
+
+func TestOperationsParserReturnsFalseWhenPayloadIsEmpty(t *testing.T) {
+	t.Parallel()
+
+	// Given an empty payload
+	payload := `   `
+	parser := parsers.NewOperationsParser()
+
+	// When parsing the payload
+	_, ok := parser.Parse(payload)
+
+	// Then I expect the parsing to fail
+	assert.False(t, ok)
+}
+
+func TestOperationsParserParsesDocumentWithOpeningBalanceHeader(t *testing.T) {
+	t.Parallel()
+
+	// Given a JSON object with an opening balance header and a list of operations
+	payload := `{"opening-balance":{"quantity":100,"average-unit-cost":10.00,"accumulated-loss":500.00},` +
+		`"operations":[{"operation":"sell","unit-cost":20.00,"quantity":50}]}`
+	parser := parsers.NewOperationsParser()
+
+	// When parsing the payload
+	request, ok := parser.Parse(payload)
+
+	// Then I expect the parsing to succeed
+	assert.True(t, ok)
+
+	// And I expect a request with a single operation
+	assert.Len(t, request.Operations(), 1)
+
+	// And I expect the opening balance to be carried by the request
+	openingBalance, hasOpeningBalance := request.OpeningBalance()
+	assert.True(t, hasOpeningBalance)
+	assert.Equal(t, driver.OpeningBalance{Quantity: 100, AverageUnitCost: 10.00, AccumulatedLoss: 500.00}, openingBalance)
+}
+
+func TestOperationsParserReturnsFalseWhenDocumentHasNoOperations(t *testing.T) {
+	t.Parallel()
+
+	// Given a JSON object without the list of operations
+	payload := `{"opening-balance":{"quantity":100,"average-unit-cost":10.00,"accumulated-loss":0.00}}`
+	parser := parsers.NewOperationsParser()
+
+	// When parsing the payload
+	_, ok := parser.Parse(payload)
+
+	// Then I expect the parsing to fail
+	assert.False(t, ok)
+}
@@ -0,0 +1,280 @@
+package parsers
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"capital-gains/src/driver"
+)
+
+// fieldKind tells how the value of a field is read in strict mode.
+type fieldKind int
+
+const (
+	// textField holds a string.
+	textField fieldKind = iota
+
+	// wholeField holds a whole number, given either as a JSON number or as a string.
+	wholeField
+
+	// moneyField holds an amount of money with at most two decimal places, given either as a JSON
+	// number or as a string.
+	moneyField
+
+	// decimalField holds a decimal number of any precision, given either as a JSON number or as a string.
+	decimalField
+
+	// objectField holds an object whose own fields are read in strict mode.
+	objectField
+
+	// listField holds an array of objects whose own fields are read in strict mode.
+	listField
+)
+
+// fieldSpec is the contract of a field of an input object.
+type fieldSpec struct {
+	name     string
+	kind     fieldKind
+	required bool
+	fields   func() []fieldSpec
+	indexed  bool
+}
+
+func operationFields() []fieldSpec {
+	return []fieldSpec{
+		{name: "operation", kind: textField, required: true},
+		{name: "unit-cost", kind: moneyField, required: true},
+		{name: "quantity", kind: wholeField, required: true},
+		{name: "id", kind: textField},
+		{name: "date", kind: textField},
+		{name: "time", kind: textField},
+		{name: "sequence", kind: wholeField},
+		{name: "account", kind: textField},
+		{name: "ticker", kind: textField},
+		{name: "fees", kind: moneyField},
+	}
+}
+
+func openingBalanceFields() []fieldSpec {
+	return []fieldSpec{
+		{name: "quantity", kind: wholeField, required: true},
+		{name: "average-unit-cost", kind: decimalField, required: true},
+		{name: "accumulated-loss", kind: moneyField, required: true},
+		{name: "account", kind: textField},
+	}
+}
+
+func correctionFields() []fieldSpec {
+	return []fieldSpec{
+		{name: "action", kind: textField, required: true},
+		{name: "id", kind: textField, required: true},
+		{name: "replacement", kind: objectField, fields: operationFields},
+	}
+}
+
+func documentFields() []fieldSpec {
+	return []fieldSpec{
+		{name: "account", kind: textField},
+		{name: "opening-balance", kind: objectField, fields: openingBalanceFields},
+		{name: "opening-balances", kind: listField, fields: openingBalanceFields},
+		{name: "operations", kind: listField, required: true, fields: operationFields, indexed: true},
+		{name: "corrections", kind: listField, fields: correctionFields},
+	}
+}
+
+// StrictOperationsParser parses the same input as OperationsParser, but rejects unknown fields, absent
+// required fields, whole numbers given with decimals and amounts of money with more than two decimal
+// places. Numbers may be given as strings, so their digits are checked as written rather than as floats.
+type StrictOperationsParser struct {
+	parser *OperationsParser
+}
+
+func NewStrictOperationsParser() *StrictOperationsParser {
+	return &StrictOperationsParser{parser: NewOperationsParser()}
+}
+
+// Parse parses an array of operations or a document holding them, reporting every field breaking the contract.
+func (strictParser *StrictOperationsParser) Parse(payload string) (driver.Request, driver.ValidationErrors) {
+	trimmedPayload := strings.TrimSpace(payload)
+
+	var normalized any
+
+	var validationErrors driver.ValidationErrors
+
+	switch {
+	case strings.HasPrefix(trimmedPayload, "["):
+		normalized, validationErrors = strictList(json.RawMessage(trimmedPayload), fieldSpec{kind: listField, fields: operationFields, indexed: true})
+	case strings.HasPrefix(trimmedPayload, "{"):
+		normalized, validationErrors = strictObject(json.RawMessage(trimmedPayload), documentFields())
+	default:
+		return driver.Request{}, MalformedInput(trimmedPayload, "expected a JSON array of operations or an object with operations")
+	}
+
+	if len(validationErrors) > 0 {
+		return driver.Request{}, validationErrors
+	}
+
+	request, ok := strictParser.parser.Parse(toJSON(normalized))
+
+	if !ok {
+		return driver.Request{}, MalformedInput(trimmedPayload, "expected numbers within the supported range")
+	}
+
+	return request, nil
+}
+
+// ParseOperation parses a single operation object, reporting every field breaking the contract.
+func (strictParser *StrictOperationsParser) ParseOperation(payload string) (driver.Operation, driver.ValidationErrors) {
+	normalized, validationErrors := strictObject(json.RawMessage(strings.TrimSpace(payload)), operationFields())
+
+	if len(validationErrors) > 0 {
+		return driver.Operation{}, validationErrors
+	}
+
+	operation, ok := strictParser.parser.ParseOperation(toJSON(normalized))
+
+	if !ok {
+		return driver.Operation{}, MalformedInput(payload, "expected numbers within the supported range")
+	}
+
+	return operation, nil
+}
+
+// strictObject reads the object field by field, returning it with its numbers normalized.
+func strictObject(payload json.RawMessage, fields []fieldSpec) (map[string]any, driver.ValidationErrors) {
+	var object map[string]json.RawMessage
+
+	if err := json.Unmarshal(payload, &object); err != nil || object == nil {
+		return nil, MalformedInput(string(payload), "expected a JSON object")
+	}
+
+	validationErrors := make(driver.ValidationErrors, 0)
+	normalized := make(map[string]any, len(object))
+
+	for _, name := range slices.Sorted(maps.Keys(object)) {
+		if !slices.ContainsFunc(fields, func(field fieldSpec) bool { return field.name == name }) {
+			validationErrors = append(validationErrors, strictError(name, driver.ErrUnknownField, "not defined by the contract"))
+		}
+	}
+
+	for _, field := range fields {
+		value, present := object[field.name]
+
+		if !present || string(value) == "null" {
+			if field.required {
+				validationErrors = append(validationErrors, strictError(field.name, driver.ErrMissingField, "required in strict mode"))
+			}
+
+			continue
+		}
+
+		fieldValue, fieldErrors := strictValue(value, field)
+		normalized[field.name] = fieldValue
+		validationErrors = append(validationErrors, fieldErrors...)
+	}
+
+	return normalized, validationErrors
+}
+
+// strictList reads each object of the array, locating their errors by index or by the path of the field.
+func strictList(payload json.RawMessage, field fieldSpec) ([]any, driver.ValidationErrors) {
+	var elements []json.RawMessage
+
+	if err := json.Unmarshal(payload, &elements); err != nil {
+		return nil, MalformedInput(string(payload), "expected a JSON array of objects")
+	}
+
+	validationErrors := make(driver.ValidationErrors, 0)
+	normalized := make([]any, 0, len(elements))
+
+	for index, element := range elements {
+		normalizedElement, elementErrors := strictObject(element, field.fields())
+		normalized = append(normalized, normalizedElement)
+
+		if field.indexed {
+			validationErrors = append(validationErrors, elementErrors.WithIndex(index)...)
+			continue
+		}
+
+		validationErrors = append(validationErrors, elementErrors.Within(fmt.Sprintf("%s[%d]", field.name, index))...)
+	}
+
+	return normalized, validationErrors
+}
+
+func strictValue(value json.RawMessage, field fieldSpec) (any, driver.ValidationErrors) {
+	switch field.kind {
+	case objectField:
+		normalized, validationErrors := strictObject(value, field.fields())
+		return normalized, validationErrors.Within(field.name)
+	case listField:
+		return strictList(value, field)
+	case textField:
+		var text string
+
+		if err := json.Unmarshal(value, &text); err != nil {
+			return nil, driver.ValidationErrors{strictError(field.name, driver.ErrInvalidValue, "must be a string")}
+		}
+
+		return text, nil
+	default:
+		number, problem := strictNumber(value, field.kind)
+
+		if problem != "" {
+			return nil, driver.ValidationErrors{strictError(field.name, driver.ErrInvalidValue, problem)}
+		}
+
+		return number, nil
+	}
+}
+
+// strictNumber returns the number as written, whether as a JSON number or as a string, or what is
+// wrong with it.
+func strictNumber(value json.RawMessage, kind fieldKind) (json.Number, string) {
+	literal := string(value)
+
+	if strings.HasPrefix(literal, `"`) {
+		if err := json.Unmarshal(value, &literal); err != nil {
+			return "", "must be a number"
+		}
+
+		literal = strings.TrimSpace(literal)
+	}
+
+	wholePart, decimalPart, hasDecimals := strings.Cut(strings.TrimPrefix(literal, "-"), ".")
+
+	if !isDigits(wholePart) || (hasDecimals && !isDigits(decimalPart)) {
+		return "", fmt.Sprintf("%q must be a plain decimal number", literal)
+	}
+
+	if kind == wholeField && hasDecimals {
+		return "", fmt.Sprintf("%q must be a whole number", literal)
+	}
+
+	if kind == moneyField && len(decimalPart) > 2 {
+		return "", fmt.Sprintf("%q must have at most two decimal places", literal)
+	}
+
+	return json.Number(literal), ""
+}
+
+func isDigits(text string) bool {
+	return text != "" && strings.Trim(text, "0123456789") == ""
+}
+
+func strictError(field string, kind error, detail string) driver.ValidationError {
+	return driver.NewValidationError(fmt.Errorf("%w: %s", kind, detail)).WithField(field)
+}
+
+func toJSON(value any) string {
+	serializedValue, err := json.Marshal(value)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return string(serializedValue)
+}
@@ -0,0 +1,83 @@
+package parsers_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/parsers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictOperationsParserAcceptsNumbersGivenAsStrings(t *testing.T) {
+	t.Parallel()
+
+	// Given an array of operations with numbers given both as JSON numbers and as strings
+	payload := `[{"operation":"buy","unit-cost":"10.25","quantity":"100"},{"operation":"sell","unit-cost":20,"quantity":50,"fees":"1.5"}]`
+	parser := parsers.NewStrictOperationsParser()
+
+	// When parsing the payload
+	request, validationErrors := parser.Parse(payload)
+
+	// Then I expect no validation errors
+	assert.Empty(t, validationErrors)
+
+	// And I expect the numbers to be read as written
+	operations := request.Operations()
+	assert.Equal(t, driver.Operation{Operation: "buy", UnitCost: 10.25, Quantity: 100}, operations[0])
+	assert.Equal(t, driver.Operation{Operation: "sell", UnitCost: 20, Quantity: 50, Fees: 1.5}, operations[1])
+}
+
+func TestStrictOperationsParserRejectsUnknownAndAbsentFields(t *testing.T) {
+	t.Parallel()
+
+	// Given an operation with a misspelled unit cost, so the unit cost itself is absent
+	payload := `[{"operation":"buy","unitcost":10.00,"quantity":100}]`
+	parser := parsers.NewStrictOperationsParser()
+
+	// When parsing the payload
+	_, validationErrors := parser.Parse(payload)
+
+	// Then I expect both the unknown and the absent field to be reported for the operation
+	assert.Len(t, validationErrors, 2)
+	assert.ErrorIs(t, validationErrors[0], driver.ErrUnknownField)
+	assert.Equal(t, "unitcost", validationErrors[0].Field)
+	assert.ErrorIs(t, validationErrors[1], driver.ErrMissingField)
+	assert.Equal(t, "unit-cost", validationErrors[1].Field)
+	assert.Equal(t, 0, validationErrors[1].Index)
+}
+
+func TestStrictOperationsParserRejectsLossyNumbers(t *testing.T) {
+	t.Parallel()
+
+	// Given an operation with a fractional quantity and a price with three decimal places
+	payload := `[{"operation":"buy","unit-cost":"10.125","quantity":10.5}]`
+	parser := parsers.NewStrictOperationsParser()
+
+	// When parsing the payload
+	_, validationErrors := parser.Parse(payload)
+
+	// Then I expect each number to be rejected as written
+	expected := `{"errors":[` +
+		`{"index":0,"field":"unit-cost","code":"invalid-value","message":"invalid value: \"10.125\" must have at most two decimal places"},` +
+		`{"index":0,"field":"quantity","code":"invalid-value","message":"invalid value: \"10.5\" must be a whole number"}]}`
+	assert.Equal(t, expected, validationErrors.ToString())
+}
+
+func TestStrictOperationsParserLocatesErrorsWithinDocuments(t *testing.T) {
+	t.Parallel()
+
+	// Given a document whose opening balance lacks its accumulated loss and whose correction has an unknown field
+	payload := `{"opening-balance":{"quantity":100,"average-unit-cost":10.3333},` +
+		`"operations":[{"id":"1","operation":"buy","unit-cost":10.00,"quantity":100}],` +
+		`"corrections":[{"action":"cancel","id":"1","reason":"duplicated"}]}`
+	parser := parsers.NewStrictOperationsParser()
+
+	// When parsing the payload
+	_, validationErrors := parser.Parse(payload)
+
+	// Then I expect each error located by the path of its field
+	assert.Len(t, validationErrors, 2)
+	assert.Equal(t, "opening-balance.accumulated-loss", validationErrors[0].Field)
+	assert.Equal(t, "corrections[0].reason", validationErrors[1].Field)
+}
@@ -0,0 +1,60 @@
+package driver
+
+import (
+	"encoding/json"
+
+	"capital-gains/src/application/domain/models"
+)
+
+// PortfolioPosition is the position an account of a portfolio holds in a ticker between calculations:
+// how many shares, at which average unit cost, and the loss the account has left to be deducted from
+// its next gains.
+type PortfolioPosition struct {
+	Account         string `json:"account,omitempty"`
+	Ticker          string `json:"ticker,omitempty"`
+	Quantity        int    `json:"quantity"`
+	AverageUnitCost Amount `json:"average-unit-cost"`
+	AccumulatedLoss Amount `json:"accumulated-loss"`
+}
+
+// PortfolioPositions are the positions of every account of a portfolio, one per ticker held.
+type PortfolioPositions []PortfolioPosition
+
+// NewPortfolioPositions returns the positions each capital gain left its account in, one per ticker,
+// or a single one when the account holds none.
+func NewPortfolioPositions(capitalGains []models.CapitalGain) PortfolioPositions {
+	positions := make(PortfolioPositions, 0, len(capitalGains))
+
+	for _, capitalGain := range capitalGains {
+		holdings := capitalGain.Holdings()
+		tickers := holdings.Tickers()
+
+		if len(tickers) == 0 {
+			tickers = []string{""}
+		}
+
+		for _, ticker := range tickers {
+			position := holdings.PositionOf(ticker)
+
+			positions = append(positions, PortfolioPosition{
+				Account:         capitalGain.Account(),
+				Ticker:          ticker,
+				Quantity:        position.Quantity().ToInt(),
+				AverageUnitCost: Amount(position.AverageUnitCost().ToFloat64()),
+				AccumulatedLoss: Amount(position.AccumulatedLoss().ToFloat64()),
+			})
+		}
+	}
+
+	return positions
+}
+
+func (positions PortfolioPositions) ToString() string {
+	serializedPositions, err := json.Marshal([]PortfolioPosition(positions))
+
+	if err != nil {
+		panic(err)
+	}
+
+	return string(serializedPositions)
+}
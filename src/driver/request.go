@@ -1,13 +1,147 @@
 package driver
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"capital-gains/src/application/domain/models"
+)
+
 type Request struct {
-	operations []Operation
+	operations      []Operation
+	openingBalances []OpeningBalance
+	corrections     []Correction
 }
 
 func NewRequest(operations []Operation) Request {
 	return Request{operations: operations}
 }
 
+func NewRequestWithOpeningBalance(openingBalance OpeningBalance, operations []Operation) Request {
+	return Request{operations: operations, openingBalances: []OpeningBalance{openingBalance}}
+}
+
+// WithOpeningBalances returns the request seeding each account with its own opening balance.
+func (request Request) WithOpeningBalances(openingBalances []OpeningBalance) Request {
+	request.openingBalances = append(request.openingBalances, openingBalances...)
+	return request
+}
+
+func (request Request) WithCorrections(corrections []Correction) Request {
+	request.corrections = corrections
+	return request
+}
+
 func (request *Request) Operations() []Operation {
 	return request.operations
 }
+
+func (request *Request) OpeningBalance() (OpeningBalance, bool) {
+	if len(request.openingBalances) == 0 {
+		return OpeningBalance{}, false
+	}
+
+	return request.openingBalances[0], true
+}
+
+func (request *Request) OpeningBalances() []OpeningBalance {
+	return request.openingBalances
+}
+
+func (request *Request) Corrections() []Correction {
+	return request.corrections
+}
+
+func (request *Request) HasCorrections() bool {
+	return len(request.corrections) > 0
+}
+
+// Validate returns the problems found in every operation, opening balance and correction of the
+// request, none when the request can be calculated. An operation is corrected at most once, so a
+// second correction of the same id, such as an amend after a cancel, is rejected.
+func (request *Request) Validate() ValidationErrors {
+	validationErrors := make(ValidationErrors, 0)
+	operationIDs := make(map[string]bool, len(request.operations))
+
+	for index, openingBalance := range request.openingBalances {
+		validationErrors = append(validationErrors, openingBalance.Validate().Within(fmt.Sprintf("opening-balances[%d]", index))...)
+	}
+
+	for index, operation := range request.operations {
+		validationErrors = append(validationErrors, operation.Validate().WithIndex(index)...)
+		operationIDs[operation.ID] = operation.ID != ""
+	}
+
+	correctedIDs := make(map[string]int, len(request.corrections))
+
+	for index, correction := range request.corrections {
+		correctionErrors := correction.Validate(operationIDs)
+
+		if previous, corrected := correctedIDs[correction.ID]; corrected {
+			detail := fmt.Sprintf("the operation %q is already corrected by corrections[%d]", correction.ID, previous)
+			correctionErrors = append(correctionErrors, fieldError("id", ErrInvalidValue, detail))
+		} else if operationIDs[correction.ID] {
+			correctedIDs[correction.ID] = index
+		}
+
+		validationErrors = append(validationErrors, correctionErrors.Within(fmt.Sprintf("corrections[%d]", index))...)
+	}
+
+	return validationErrors
+}
+
+// ValidateTradeDates returns an error for each operation without the trade date that placing it in
+// chronological order requires, and for each buy and sell traded at the same moment of an account
+// with no sequence telling which one came first.
+func (request *Request) ValidateTradeDates() ValidationErrors {
+	validationErrors := make(ValidationErrors, 0)
+
+	for index, operation := range request.operations {
+		if operation.Date == "" {
+			validationErrors = append(validationErrors, fieldError("date", ErrMissingField, "required to apply the operations chronologically").WithIndex(index))
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+
+	return request.validateTradeOrder()
+}
+
+// validateTradeOrder places the operations of each account in chronological order the way the
+// calculation will, once every operation is valid, returning an error for each operation that
+// cannot be ordered.
+func (request *Request) validateTradeOrder() ValidationErrors {
+	validationErrors := make(ValidationErrors, 0)
+	indexesByAccount := make(map[string][]int)
+	trades := make(map[string][]models.Operation)
+
+	for index, operation := range request.operations {
+		trade, err := operation.trade()
+
+		if err != nil || len(operation.Validate()) > 0 {
+			return validationErrors
+		}
+
+		indexesByAccount[operation.Account] = append(indexesByAccount[operation.Account], index)
+		trades[operation.Account] = append(trades[operation.Account], trade)
+	}
+
+	for account, indexes := range indexesByAccount {
+		var ambiguousOrder models.AmbiguousOrderError
+
+		if _, err := models.NewChronologicalOrder(trades[account]); errors.As(err, &ambiguousOrder) {
+			for _, position := range ambiguousOrder.Indexes {
+				validationErrors = append(validationErrors, request.operations[indexes[position]].sequenceError().WithIndex(indexes[position]))
+			}
+		}
+	}
+
+	sort.SliceStable(validationErrors, func(first, second int) bool {
+		return validationErrors[first].Index < validationErrors[second].Index
+	})
+
+	return validationErrors
+}
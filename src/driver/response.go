@@ -6,26 +6,116 @@ import (
 	"capital-gains/src/application/domain/models"
 )
 
+// AccountSummary is the final state of the portfolio of an account, along with the total tax it paid:
+// the position of its operations without a ticker, the one of each ticker it traded, and the loss
+// they share.
+type AccountSummary struct {
+	TotalTax        Amount           `json:"total-tax"`
+	Quantity        int              `json:"quantity"`
+	AverageUnitCost Amount           `json:"average-unit-cost"`
+	Tickers         []TickerPosition `json:"tickers,omitempty"`
+	AccumulatedLoss Amount           `json:"accumulated-loss"`
+}
+
+// TickerPosition is the position an account holds in a single ticker.
+type TickerPosition struct {
+	Ticker          string `json:"ticker"`
+	Quantity        int    `json:"quantity"`
+	AverageUnitCost Amount `json:"average-unit-cost"`
+}
+
+// GroupedTaxes are the taxes of a simulation grouped by the account of its operations.
+type GroupedTaxes[T any] struct {
+	Accounts []T `json:"accounts"`
+}
+
+// SummarizedTaxes wraps the taxes of a simulation, either a single array of them or the ones grouped
+// by account, along with its summary.
+type SummarizedTaxes[T any] struct {
+	Taxes   T                 `json:"taxes"`
+	Summary SimulationSummary `json:"summary"`
+}
+
+// OperationTax is the tax of an operation along with the trade it was calculated for and the
+// accumulated loss it left, for the outputs showing more than the tax.
+type OperationTax struct {
+	Account       string
+	ID            string
+	Operation     string
+	Quantity      int
+	UnitCost      Amount
+	Gain          Amount
+	RemainingLoss Amount
+	Tax           Amount
+}
+
+// AccountResponse holds the taxes of the operations of a single account.
+type AccountResponse struct {
+	Account    string         `json:"account"`
+	Taxes      []Tax          `json:"taxes"`
+	Summary    AccountSummary `json:"summary"`
+	Operations []OperationTax `json:"-"`
+}
+
+// Response is the output of an input line. When the operations carry accounts, the taxes
+// are grouped by account, each group with its summary; otherwise they form a single array.
+// When summarized, the taxes are wrapped along with the summary of the whole simulation.
 type Response struct {
-	taxes []Tax
+	taxes      []Tax
+	accounts   []AccountResponse
+	grouped    bool
+	summary    SimulationSummary
+	summarized bool
 }
 
 func NewResponse(capitalGains []models.CapitalGain) Response {
-	taxItems := make([]Tax, 0)
+	response := Response{taxes: make([]Tax, 0), accounts: make([]AccountResponse, 0)}
 
 	for _, capitalGain := range capitalGains {
-		taxEvents := capitalGain.Events()
+		accountResponse := newAccountResponse(capitalGain)
 
-		for _, taxEvent := range taxEvents {
-			taxItems = append(taxItems, NewTax(taxEvent.Amount()))
-		}
+		response.taxes = append(response.taxes, accountResponse.Taxes...)
+		response.accounts = append(response.accounts, accountResponse)
+		response.grouped = response.grouped || capitalGain.Account() != ""
 	}
 
-	return Response{taxes: taxItems}
+	response.summary = NewSimulationSummary(capitalGains)
+
+	return response
+}
+
+// WithSummary returns the response wrapping the taxes along with the summary of the simulation.
+func (response Response) WithSummary() Response {
+	response.summarized = true
+	return response
+}
+
+// Grouped tells whether the taxes are grouped by account, as the operations carry one.
+func (response Response) Grouped() bool {
+	return response.grouped
+}
+
+// Accounts returns the taxes of each account, in the order the accounts first appeared.
+func (response Response) Accounts() []AccountResponse {
+	return response.accounts
+}
+
+// Summary returns the totals and the final position of the simulation.
+func (response Response) Summary() SimulationSummary {
+	return response.summary
+}
+
+// Summarized tells whether the taxes are wrapped along with the summary of the simulation.
+func (response Response) Summarized() bool {
+	return response.summarized
 }
 
 func (response Response) MarshalJSON() ([]byte, error) {
-	return json.Marshal(response.taxes)
+	if response.grouped {
+		return marshalTaxes(response, GroupedTaxes[AccountResponse]{Accounts: response.accounts})
+	}
+
+	return marshalTaxes(response, response.taxes)
 }
 
 func (response Response) ToString() string {
@@ -37,3 +127,72 @@ func (response Response) ToString() string {
 
 	return string(serializedResponse)
 }
+
+// marshalTaxes serializes the taxes of the response, wrapped along with its summary when it is summarized.
+func marshalTaxes[T any](response Response, taxes T) ([]byte, error) {
+	if response.summarized {
+		return json.Marshal(SummarizedTaxes[T]{Taxes: taxes, Summary: response.summary})
+	}
+
+	return json.Marshal(taxes)
+}
+
+func newAccountResponse(capitalGain models.CapitalGain) AccountResponse {
+	taxes := make([]Tax, 0)
+	operations := make([]OperationTax, 0)
+	totalTax := models.NewZeroMonetaryValue()
+
+	for _, taxEvent := range capitalGain.Events() {
+		trade := taxEvent.Trade()
+
+		taxes = append(taxes, NewTax(taxEvent.Amount()).WithID(taxEvent.OperationID()))
+		operations = append(operations, OperationTax{
+			Account:       capitalGain.Account(),
+			ID:            taxEvent.OperationID(),
+			Operation:     trade.Side(),
+			Quantity:      trade.Quantity(),
+			UnitCost:      Amount(trade.UnitCost()),
+			Gain:          Amount(taxEvent.Realization().Gain()),
+			RemainingLoss: Amount(taxEvent.Position().AccumulatedLoss()),
+			Tax:           Amount(taxEvent.Amount()),
+		})
+		totalTax = totalTax.Add(models.NewMonetaryValue(taxEvent.Amount()))
+	}
+
+	holdings := capitalGain.Holdings()
+	position := holdings.PositionOf("")
+
+	return AccountResponse{
+		Account:    capitalGain.Account(),
+		Taxes:      taxes,
+		Operations: operations,
+		Summary: AccountSummary{
+			TotalTax:        Amount(totalTax.ToFloat64()),
+			Quantity:        position.Quantity().ToInt(),
+			AverageUnitCost: Amount(position.AverageUnitCost().ToFloat64()),
+			Tickers:         tickerPositionsOf(holdings),
+			AccumulatedLoss: Amount(holdings.AccumulatedLoss().ToFloat64()),
+		},
+	}
+}
+
+// tickerPositionsOf returns the position held in each ticker, leaving out the operations without one.
+func tickerPositionsOf(holdings models.Holdings) []TickerPosition {
+	positions := make([]TickerPosition, 0)
+
+	for _, ticker := range holdings.Tickers() {
+		if ticker == "" {
+			continue
+		}
+
+		position := holdings.PositionOf(ticker)
+
+		positions = append(positions, TickerPosition{
+			Ticker:          ticker,
+			Quantity:        position.Quantity().ToInt(),
+			AverageUnitCost: Amount(position.AverageUnitCost().ToFloat64()),
+		})
+	}
+
+	return positions
+}
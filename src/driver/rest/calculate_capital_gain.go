@@ -0,0 +1,176 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"capital-gains/src/application/commands"
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/commandbus"
+	"capital-gains/src/driver/parsers"
+)
+
+// CalculateCapitalGain calculates the simulation posted as the body of a request, holding the same
+// operations as an input line, and responds with the same output the console writes for it.
+type CalculateCapitalGain struct {
+	settings     Settings
+	unitsOfWork  commandbus.UnitOfWorkFactory
+	parser       *parsers.OperationsParser
+	strictParser *parsers.StrictOperationsParser
+}
+
+// NewCalculateCapitalGain returns the handler calculating each request within a unit of work of its
+// own, begun by the given factory, so requests are calculated concurrently.
+func NewCalculateCapitalGain(settings Settings, unitsOfWork commandbus.UnitOfWorkFactory) *CalculateCapitalGain {
+	return &CalculateCapitalGain{
+		settings:     settings,
+		unitsOfWork:  unitsOfWork,
+		parser:       parsers.NewOperationsParser(),
+		strictParser: parsers.NewStrictOperationsParser(),
+	}
+}
+
+// ServeHTTP responds with 200 and the output of the simulation, 400 when the body cannot be read as
+// a simulation, 422 when it holds validation errors, 413 when it is too large, 415 when it is not JSON
+// and 500 when it fails unexpectedly.
+func (calculateCapitalGain *CalculateCapitalGain) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if !acceptsJSON(request) {
+		writeError(writer, http.StatusUnsupportedMediaType, "unsupported-media-type", "the request body must be application/json")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(writer, request.Body, calculateCapitalGain.settings.MaxBodyBytes))
+
+	var maxBytesError *http.MaxBytesError
+
+	if errors.As(err, &maxBytesError) {
+		message := fmt.Sprintf("the request body exceeds %d bytes", maxBytesError.Limit)
+		writeError(writer, http.StatusRequestEntityTooLarge, "payload-too-large", message)
+
+		return
+	}
+
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, "malformed-input", err.Error())
+		return
+	}
+
+	calculateCapitalGain.respondSafely(writer, string(body))
+}
+
+// respondSafely responds with the output of the simulation in the body, answering an unexpected
+// failure with an internal error rather than dropping the connection without a response.
+func (calculateCapitalGain *CalculateCapitalGain) respondSafely(writer http.ResponseWriter, body string) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			writeError(writer, http.StatusInternalServerError, "internal-error", fmt.Sprintf("internal error: %v", recovered))
+		}
+	}()
+
+	simulation, validationErrors := calculateCapitalGain.parse(body)
+
+	if len(validationErrors) > 0 {
+		writeJSON(writer, statusOf(validationErrors), validationErrors.ToString())
+		return
+	}
+
+	output, validationErrors := calculateCapitalGain.calculate(simulation)
+
+	if len(validationErrors) > 0 {
+		writeJSON(writer, statusOf(validationErrors), validationErrors.ToString())
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, output)
+}
+
+// parse reads the body as a simulation, holding to the contract field by field in strict mode, and
+// validates it with the current settings.
+func (calculateCapitalGain *CalculateCapitalGain) parse(body string) (driver.Request, driver.ValidationErrors) {
+	if calculateCapitalGain.settings.Strict {
+		simulation, validationErrors := calculateCapitalGain.strictParser.Parse(body)
+
+		if len(validationErrors) > 0 {
+			return driver.Request{}, validationErrors
+		}
+
+		return simulation, calculateCapitalGain.validate(simulation)
+	}
+
+	simulation, ok := calculateCapitalGain.parser.Parse(body)
+
+	if !ok {
+		expected := "expected a JSON array of operations or an object with operations"
+
+		if !json.Valid([]byte(body)) {
+			expected = "not valid JSON"
+		}
+
+		validationError := driver.NewValidationError(fmt.Errorf("%w: %s", driver.ErrMalformedInput, expected))
+
+		return driver.Request{}, driver.ValidationErrors{validationError}
+	}
+
+	return simulation, calculateCapitalGain.validate(simulation)
+}
+
+func (calculateCapitalGain *CalculateCapitalGain) validate(simulation driver.Request) driver.ValidationErrors {
+	validationErrors := simulation.Validate()
+
+	if calculateCapitalGain.settings.Chronological {
+		validationErrors = append(validationErrors, simulation.ValidateTradeDates()...)
+	}
+
+	return validationErrors
+}
+
+// calculate returns the output of the simulation: its tax diff report when it has corrections, its
+// taxes otherwise, or the error keeping it from being calculated.
+func (calculateCapitalGain *CalculateCapitalGain) calculate(simulation driver.Request) (string, driver.ValidationErrors) {
+	command := commands.NewCalculateCapitalGain()
+
+	if calculateCapitalGain.settings.Chronological {
+		command = command.Chronologically()
+	}
+
+	calculation, err := calculateCapitalGain.unitsOfWork().Calculate(simulation, command)
+
+	if err != nil {
+		return "", driver.ValidationErrors{driver.NewValidationError(err)}
+	}
+
+	if calculation.IsCorrected() {
+		return driver.NewTaxDiffReport(calculation.TaxDiffs()).ToString(), nil
+	}
+
+	return driver.NewResponse(calculation.CapitalGains()).ToString(), nil
+}
+
+// acceptsJSON tells whether the body of the request is JSON, which is assumed when no media type is given.
+func acceptsJSON(request *http.Request) bool {
+	contentType := request.Header.Get("Content-Type")
+
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	return err == nil && mediaType == "application/json"
+}
+
+// statusOf returns 400 when the body could not be read as a simulation at all, 422 when it was read
+// but cannot be calculated.
+func statusOf(validationErrors driver.ValidationErrors) int {
+	for _, validationError := range validationErrors {
+		if errors.Is(validationError, driver.ErrMalformedInput) {
+			return http.StatusBadRequest
+		}
+	}
+
+	return http.StatusUnprocessableEntity
+}
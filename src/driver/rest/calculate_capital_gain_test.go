@@ -0,0 +1,203 @@
+package rest_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"capital-gains/src/driver/commandbus"
+	"capital-gains/src/driver/rest"
+	"capital-gains/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const taxedOperations = `[{"operation":"buy","unit-cost":10.00,"quantity":10000},` +
+	`{"operation":"sell","unit-cost":20.00,"quantity":5000}]`
+
+// newRouter wires the calculation and the health endpoints with in-memory repositories and the given settings.
+func newRouter(settings rest.Settings, health *rest.Health) http.Handler {
+	return rest.NewRouter(rest.NewCalculateCapitalGain(settings, test.NewUnitOfWork), health)
+}
+
+// post posts the body as JSON to the calculation endpoint, returning the recorded response.
+func post(router http.Handler, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/v1/capital-gains", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestCalculateCapitalGainGivenOperationsWhenPostedThenTaxesAreReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a server with the default settings
+	router := newRouter(rest.Settings{MaxBodyBytes: rest.DefaultMaxBodyBytes}, rest.NewHealth())
+
+	// When I post a buy and a taxed sell
+	response := post(router, taxedOperations)
+
+	// Then I expect the tax array, as the console writes it
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.Equal(t, `[{"tax":0.00},{"tax":10000.00}]`, response.Body.String())
+}
+
+func TestCalculateCapitalGainGivenOperationsOfATickerWhenPostedThenTaxArrayIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a server with the default settings
+	router := newRouter(rest.Settings{MaxBodyBytes: rest.DefaultMaxBodyBytes}, rest.NewHealth())
+
+	// When I post a buy of a ticker, without any account
+	response := post(router, `[{"operation":"buy","unit-cost":10,"quantity":100,"ticker":"PETR4"}]`)
+
+	// Then I expect the tax array, the ticker not grouping the taxes as an account would
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `[{"tax":0.00}]`, response.Body.String())
+}
+
+func TestCalculateCapitalGainGivenUnexpectedFailureWhenPostedThenInternalErrorIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given a server whose units of work fail on every command
+	calculateCapitalGain := rest.NewCalculateCapitalGain(
+		rest.Settings{MaxBodyBytes: rest.DefaultMaxBodyBytes},
+		func() commandbus.UnitOfWork { return commandbus.UnitOfWork{} },
+	)
+	router := rest.NewRouter(calculateCapitalGain, rest.NewHealth())
+
+	// When I post a buy and a taxed sell
+	response := post(router, taxedOperations)
+
+	// Then I expect an internal error, shaped as the other errors
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.Contains(t, response.Body.String(), `{"errors":[{"code":"internal-error","message":"internal error: `)
+}
+
+func TestCalculateCapitalGainGivenSeveralRequestsWhenPostedThenEachOneIsIndependent(t *testing.T) {
+	t.Parallel()
+
+	// Given a server that already calculated a simulation
+	router := newRouter(rest.Settings{MaxBodyBytes: rest.DefaultMaxBodyBytes}, rest.NewHealth())
+	post(router, taxedOperations)
+
+	// When I post the same operations again
+	response := post(router, taxedOperations)
+
+	// Then I expect the same taxes, nothing being carried over from the previous request
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `[{"tax":0.00},{"tax":10000.00}]`, response.Body.String())
+}
+
+func TestCalculateCapitalGainGivenConcurrentRequestsWhenPostedThenNoneSeesTheOperationsOfAnother(t *testing.T) {
+	t.Parallel()
+
+	// Given a server and many simulations selling at a price of their own
+	const requests = 50
+
+	router := newRouter(rest.Settings{MaxBodyBytes: rest.DefaultMaxBodyBytes}, rest.NewHealth())
+	responses := make([]*httptest.ResponseRecorder, requests)
+
+	// When they are all posted at the same time
+	var waitGroup sync.WaitGroup
+
+	for index := range requests {
+		waitGroup.Go(func() {
+			responses[index] = post(router, fmt.Sprintf(`[{"operation":"buy","unit-cost":10.00,"quantity":10000},`+
+				`{"operation":"sell","unit-cost":%d.00,"quantity":5000}]`, 20+index))
+		})
+	}
+
+	waitGroup.Wait()
+
+	// Then I expect each one to be taxed on its own sell only
+	for index, response := range responses {
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, fmt.Sprintf(`[{"tax":0.00},{"tax":%d.00}]`, 1000*(10+index)), response.Body.String())
+	}
+}
+
+func TestCalculateCapitalGainGivenInvalidBodiesWhenPostedThenErrorStatusIsReturned(t *testing.T) {
+	t.Parallel()
+
+	// Given bodies that cannot be calculated for different reasons
+	cases := []struct {
+		name     string
+		settings rest.Settings
+		body     string
+		status   int
+		expected string
+	}{
+		{
+			name:     "broken JSON",
+			body:     `[{"operation":`,
+			status:   http.StatusBadRequest,
+			expected: `{"errors":[{"code":"malformed-input","message":"malformed input: not valid JSON"}]}`,
+		},
+		{
+			name:   "invalid operation",
+			body:   `[{"operation":"buy","unit-cost":10.00,"quantity":0}]`,
+			status: http.StatusUnprocessableEntity,
+			expected: `{"errors":[{"index":0,"field":"quantity","code":"invalid-value",` +
+				`"message":"invalid value: must be greater than zero"}]}`,
+		},
+		{
+			name:     "unknown field in strict mode",
+			settings: rest.Settings{Strict: true},
+			body:     `[{"operation":"buy","unitcost":10.00,"unit-cost":10.00,"quantity":100}]`,
+			status:   http.StatusUnprocessableEntity,
+			expected: `{"errors":[{"index":0,"field":"unitcost","code":"unknown-field","message":"unknown field: not defined by the contract"}]}`,
+		},
+		{
+			name:     "too large",
+			settings: rest.Settings{MaxBodyBytes: 16},
+			body:     taxedOperations,
+			status:   http.StatusRequestEntityTooLarge,
+			expected: `{"errors":[{"code":"payload-too-large","message":"the request body exceeds 16 bytes"}]}`,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// When I post each of them
+			settings := testCase.settings
+
+			if settings.MaxBodyBytes == 0 {
+				settings.MaxBodyBytes = rest.DefaultMaxBodyBytes
+			}
+
+			response := post(newRouter(settings, rest.NewHealth()), testCase.body)
+
+			// Then I expect the status and the errors telling why it was not calculated
+			assert.Equal(t, testCase.status, response.Code)
+			assert.Equal(t, testCase.expected, response.Body.String())
+		})
+	}
+}
+
+func TestCalculateCapitalGainGivenAnotherMediaTypeWhenPostedThenItIsRefused(t *testing.T) {
+	t.Parallel()
+
+	// Given a server with the default settings
+	router := newRouter(rest.Settings{MaxBodyBytes: rest.DefaultMaxBodyBytes}, rest.NewHealth())
+
+	// When I post operations as a form
+	request := httptest.NewRequest(http.MethodPost, "/v1/capital-gains", strings.NewReader(taxedOperations))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	// Then I expect the request to be refused before being read
+	assert.Equal(t, http.StatusUnsupportedMediaType, response.Code)
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Health reports whether the server is alive and whether it is ready to take requests, so
+// orchestrators stop routing requests to it before it shuts down.
+type Health struct {
+	ready atomic.Bool
+}
+
+// NewHealth returns the health of a server not ready yet.
+func NewHealth() *Health {
+	return new(Health)
+}
+
+// SetReady tells whether the server takes requests.
+func (health *Health) SetReady(ready bool) {
+	health.ready.Store(ready)
+}
+
+// ServeLiveness responds with 200 as long as the server answers at all.
+func (health *Health) ServeLiveness(writer http.ResponseWriter, _ *http.Request) {
+	writeJSON(writer, http.StatusOK, `{"status":"ok"}`)
+}
+
+// ServeReadiness responds with 200 while the server takes requests, 503 before it starts and once it shuts down.
+func (health *Health) ServeReadiness(writer http.ResponseWriter, _ *http.Request) {
+	if !health.ready.Load() {
+		writeJSON(writer, http.StatusServiceUnavailable, `{"status":"unavailable"}`)
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, `{"status":"ready"}`)
+}
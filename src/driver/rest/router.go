@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// NewRouter routes the calculation and the health endpoints. Other paths get 404, and other methods
+// on these paths get 405 along with the allowed ones.
+func NewRouter(calculateCapitalGain *CalculateCapitalGain, health *Health) http.Handler {
+	router := http.NewServeMux()
+
+	router.Handle("POST /v1/capital-gains", calculateCapitalGain)
+	router.HandleFunc("GET /healthz", health.ServeLiveness)
+	router.HandleFunc("GET /readyz", health.ServeReadiness)
+
+	return router
+}
+
+func writeJSON(writer http.ResponseWriter, status int, body string) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_, _ = writer.Write([]byte(body))
+}
+
+// writeError responds with an error refusing the request before its body was read as a simulation,
+// shaped as validation errors so clients handle every error alike.
+func writeError(writer http.ResponseWriter, status int, code string, message string) {
+	type responseError struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+
+	body, err := json.Marshal(struct {
+		Errors []responseError `json:"errors"`
+	}{Errors: []responseError{{Code: code, Message: message}}})
+
+	if err != nil {
+		panic(err)
+	}
+
+	writeJSON(writer, status, string(body))
+}
@@ -0,0 +1,72 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"capital-gains/src/driver/rest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// get sends a request of the given method to the path, returning the recorded response.
+func get(router http.Handler, method string, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+
+	return recorder
+}
+
+func TestRouterGivenServerWhenCheckingLivenessThenItIsAlive(t *testing.T) {
+	t.Parallel()
+
+	// Given a server not ready yet
+	router := newRouter(rest.Settings{MaxBodyBytes: rest.DefaultMaxBodyBytes}, rest.NewHealth())
+
+	// When I check its liveness
+	response := get(router, http.MethodGet, "/healthz")
+
+	// Then I expect it to be alive
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `{"status":"ok"}`, response.Body.String())
+}
+
+func TestRouterGivenReadinessWhenCheckedThenItFollowsTheHealth(t *testing.T) {
+	t.Parallel()
+
+	// Given a server not ready yet
+	health := rest.NewHealth()
+	router := newRouter(rest.Settings{MaxBodyBytes: rest.DefaultMaxBodyBytes}, health)
+
+	// When I check its readiness before it is ready, while it is, and once it shuts down
+	before := get(router, http.MethodGet, "/readyz")
+
+	health.SetReady(true)
+	ready := get(router, http.MethodGet, "/readyz")
+
+	health.SetReady(false)
+	after := get(router, http.MethodGet, "/readyz")
+
+	// Then I expect it to take requests only while it is ready
+	assert.Equal(t, http.StatusServiceUnavailable, before.Code)
+	assert.Equal(t, http.StatusOK, ready.Code)
+	assert.Equal(t, `{"status":"ready"}`, ready.Body.String())
+	assert.Equal(t, http.StatusServiceUnavailable, after.Code)
+}
+
+func TestRouterGivenUnknownRoutesWhenRequestedThenTheyAreNotFoundOrNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	// Given a server with the default settings
+	router := newRouter(rest.Settings{MaxBodyBytes: rest.DefaultMaxBodyBytes}, rest.NewHealth())
+
+	// When I request a path that does not exist, and the calculation with another method
+	notFound := get(router, http.MethodGet, "/v1/taxes")
+	notAllowed := get(router, http.MethodGet, "/v1/capital-gains")
+
+	// Then I expect 404 and 405 along with the allowed method
+	assert.Equal(t, http.StatusNotFound, notFound.Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, notAllowed.Code)
+	assert.Equal(t, http.MethodPost, notAllowed.Header().Get("Allow"))
+}
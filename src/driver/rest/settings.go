@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// DefaultMaxBodyBytes is the largest request body read unless given otherwise: 1 MiB.
+const DefaultMaxBodyBytes = 1 << 20
+
+// ErrInvalidSettings is returned when the server cannot be started with the given settings.
+var ErrInvalidSettings = errors.New("invalid settings")
+
+// Settings holds the behaviors of the server, selected by command-line flags.
+type Settings struct {
+	// Address is the host and port the server listens on, such as :8080.
+	Address string
+
+	// MaxBodyBytes is the largest request body read; larger ones are refused.
+	MaxBodyBytes int64
+
+	// Chronological applies the operations of each request in the order they were traded,
+	// instead of the order they were given.
+	Chronological bool
+
+	// Strict rejects request bodies with unknown or absent fields and lossy numbers.
+	Strict bool
+}
+
+// Validate reports settings the server cannot be started with.
+func (settings Settings) Validate() error {
+	if _, _, err := net.SplitHostPort(settings.Address); err != nil {
+		return fmt.Errorf("%w: address %q: %w", ErrInvalidSettings, settings.Address, err)
+	}
+
+	if settings.MaxBodyBytes <= 0 {
+		return fmt.Errorf("%w: the largest request body must be greater than zero", ErrInvalidSettings)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package rest_test
+
+import (
+	"testing"
+
+	"capital-gains/src/driver/rest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettingsValidateAcceptsAddressAndBodyLimit(t *testing.T) {
+	t.Parallel()
+
+	// Given settings listening on a port of every interface
+	settings := rest.Settings{Address: ":8080", MaxBodyBytes: rest.DefaultMaxBodyBytes}
+
+	// When validating them
+	err := settings.Validate()
+
+	// Then I expect them to be accepted
+	assert.NoError(t, err)
+}
+
+func TestSettingsValidateRejectsInvalidSettings(t *testing.T) {
+	t.Parallel()
+
+	// When validating settings without a port, and without room for any body
+	// Then I expect each of them to be rejected
+	for _, settings := range []rest.Settings{
+		{Address: "localhost", MaxBodyBytes: rest.DefaultMaxBodyBytes},
+		{Address: ":8080", MaxBodyBytes: 0},
+	} {
+		assert.ErrorIs(t, settings.Validate(), rest.ErrInvalidSettings)
+	}
+}
@@ -0,0 +1,277 @@
+package schemas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// localReferencePrefix is the prefix of the references to the definitions of the same document.
+const localReferencePrefix = "#/$defs/"
+
+// Schema is a JSON Schema document, interpreted with the subset of the draft 2020-12 keywords the
+// published schemas use: $ref to local definitions, anyOf, type, enum, properties, required,
+// additionalProperties, items, minimum, exclusiveMinimum, minLength and pattern.
+type Schema struct {
+	root map[string]any
+}
+
+// Violation is a place where an instance breaks the schema.
+type Violation struct {
+	// Path holds the property names and the array indexes leading from the root of the instance to
+	// the value breaking the schema.
+	Path    []any
+	Message string
+}
+
+func (violation Violation) String() string {
+	if len(violation.Path) == 0 {
+		return violation.Message
+	}
+
+	return fmt.Sprintf("%s: %s", FieldOf(violation.Path), violation.Message)
+}
+
+// FieldOf returns the path as a field name, such as corrections[0].replacement.quantity.
+func FieldOf(path []any) string {
+	var field strings.Builder
+
+	for _, segment := range path {
+		switch typedSegment := segment.(type) {
+		case int:
+			fmt.Fprintf(&field, "[%d]", typedSegment)
+		default:
+			if field.Len() > 0 {
+				field.WriteString(".")
+			}
+
+			field.WriteString(fmt.Sprint(typedSegment))
+		}
+	}
+
+	return field.String()
+}
+
+func Parse(document []byte) (Schema, error) {
+	var root map[string]any
+
+	if err := json.Unmarshal(document, &root); err != nil {
+		return Schema{}, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	return Schema{root: root}, nil
+}
+
+// Validate returns every violation of the schema by the JSON payload, none when it conforms to it.
+//
+// [param]  payload []byte   JSON value to be validated.
+//
+// [return] []Violation      places where the payload breaks the schema.
+// [return] error            error when the payload is not JSON at all.
+func (schema Schema) Validate(payload []byte) ([]Violation, error) {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var instance any
+
+	if err := decoder.Decode(&instance); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	return schema.validate(schema.root, instance, nil), nil
+}
+
+func (schema Schema) validate(node map[string]any, instance any, path []any) []Violation {
+	node = schema.resolve(node)
+
+	if branches, ok := node["anyOf"].([]any); ok {
+		return schema.validateAnyOf(branches, instance, path)
+	}
+
+	if expectedType, ok := node["type"].(string); ok && !hasType(instance, expectedType) {
+		return []Violation{{Path: path, Message: fmt.Sprintf("must be of type %s", expectedType)}}
+	}
+
+	violations := validateEnum(node, instance, path)
+
+	switch typedInstance := instance.(type) {
+	case json.Number:
+		violations = append(violations, validateNumber(node, typedInstance, path)...)
+	case string:
+		violations = append(violations, validateString(node, typedInstance, path)...)
+	case map[string]any:
+		violations = append(violations, schema.validateObject(node, typedInstance, path)...)
+	case []any:
+		violations = append(violations, schema.validateArray(node, typedInstance, path)...)
+	}
+
+	return violations
+}
+
+func (schema Schema) definition(reference string) map[string]any {
+	definitions, _ := schema.root["$defs"].(map[string]any)
+	definition, ok := definitions[strings.TrimPrefix(reference, localReferencePrefix)].(map[string]any)
+
+	if !strings.HasPrefix(reference, localReferencePrefix) || !ok {
+		panic(fmt.Sprintf("unsupported schema reference %q", reference))
+	}
+
+	return definition
+}
+
+// validateAnyOf accepts the instance conforming to any of the branches. Otherwise, it returns the
+// violations of the branch of its type that it comes closest to, the one it breaks the fewest times.
+func (schema Schema) validateAnyOf(branches []any, instance any, path []any) []Violation {
+	var closest []Violation
+
+	expectedTypes := make([]string, 0, len(branches))
+
+	for _, branch := range branches {
+		node := schema.resolve(branch.(map[string]any))
+		expectedType, _ := node["type"].(string)
+
+		if expectedType != "" && !hasType(instance, expectedType) {
+			expectedTypes = append(expectedTypes, expectedType)
+			continue
+		}
+
+		violations := schema.validate(node, instance, path)
+
+		if len(violations) == 0 {
+			return nil
+		}
+
+		if closest == nil || len(violations) < len(closest) {
+			closest = violations
+		}
+	}
+
+	if closest == nil {
+		return []Violation{{Path: path, Message: fmt.Sprintf("must be of type %s", strings.Join(slices.Compact(expectedTypes), " or "))}}
+	}
+
+	return closest
+}
+
+// resolve returns the definition the node refers to, or the node itself when it is not a reference.
+func (schema Schema) resolve(node map[string]any) map[string]any {
+	if reference, ok := node["$ref"].(string); ok {
+		return schema.resolve(schema.definition(reference))
+	}
+
+	return node
+}
+
+func (schema Schema) validateObject(node map[string]any, instance map[string]any, path []any) []Violation {
+	violations := make([]Violation, 0)
+	properties, _ := node["properties"].(map[string]any)
+
+	requiredProperties, _ := node["required"].([]any)
+
+	for _, required := range requiredProperties {
+		if _, ok := instance[required.(string)]; !ok {
+			violations = append(violations, Violation{Path: appendPath(path, required), Message: "is required"})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(instance)) {
+		property, defined := properties[name].(map[string]any)
+
+		if !defined {
+			if additional, ok := node["additionalProperties"].(bool); ok && !additional {
+				violations = append(violations, Violation{Path: appendPath(path, name), Message: "is not allowed"})
+			}
+
+			continue
+		}
+
+		violations = append(violations, schema.validate(property, instance[name], appendPath(path, name))...)
+	}
+
+	return violations
+}
+
+func (schema Schema) validateArray(node map[string]any, instance []any, path []any) []Violation {
+	items, ok := node["items"].(map[string]any)
+
+	if !ok {
+		return nil
+	}
+
+	violations := make([]Violation, 0)
+
+	for index, item := range instance {
+		violations = append(violations, schema.validate(items, item, appendPath(path, index))...)
+	}
+
+	return violations
+}
+
+func validateEnum(node map[string]any, instance any, path []any) []Violation {
+	allowed, ok := node["enum"].([]any)
+
+	if !ok || slices.Contains(allowed, instance) {
+		return nil
+	}
+
+	names := make([]string, 0, len(allowed))
+
+	for _, value := range allowed {
+		names = append(names, fmt.Sprint(value))
+	}
+
+	return []Violation{{Path: path, Message: fmt.Sprintf("must be one of %s", strings.Join(names, ", "))}}
+}
+
+func validateNumber(node map[string]any, instance json.Number, path []any) []Violation {
+	value, _ := instance.Float64()
+
+	if minimum, ok := node["minimum"].(float64); ok && value < minimum {
+		return []Violation{{Path: path, Message: fmt.Sprintf("must be greater than or equal to %v", minimum)}}
+	}
+
+	if minimum, ok := node["exclusiveMinimum"].(float64); ok && value <= minimum {
+		return []Violation{{Path: path, Message: fmt.Sprintf("must be greater than %v", minimum)}}
+	}
+
+	return nil
+}
+
+func validateString(node map[string]any, instance string, path []any) []Violation {
+	if minLength, ok := node["minLength"].(float64); ok && float64(len(instance)) < minLength {
+		return []Violation{{Path: path, Message: fmt.Sprintf("must have at least %v characters", minLength)}}
+	}
+
+	if pattern, ok := node["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(instance) {
+		return []Violation{{Path: path, Message: fmt.Sprintf("must match the pattern %s", pattern)}}
+	}
+
+	return nil
+}
+
+func hasType(instance any, expectedType string) bool {
+	switch typedInstance := instance.(type) {
+	case json.Number:
+		value, err := typedInstance.Float64()
+		return expectedType == "number" || (expectedType == "integer" && err == nil && value == math.Trunc(value))
+	case string:
+		return expectedType == "string"
+	case bool:
+		return expectedType == "boolean"
+	case map[string]any:
+		return expectedType == "object"
+	case []any:
+		return expectedType == "array"
+	default:
+		return expectedType == "null"
+	}
+}
+
+func appendPath(path []any, segment any) []any {
+	return append(slices.Clone(path), segment)
+}
@@ -0,0 +1,165 @@
+package schemas_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"capital-gains/src/driver"
+	"capital-gains/src/driver/schemas"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// jsonFieldsOf returns the names the fields of the given type are serialized with.
+func jsonFieldsOf(value any) []string {
+	valueType := reflect.TypeOf(value)
+	names := make([]string, 0, valueType.NumField())
+
+	for index := range valueType.NumField() {
+		name, _, _ := strings.Cut(valueType.Field(index).Tag.Get("json"), ",")
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// propertiesOf returns the names of the properties of the given definition of the schema document.
+func propertiesOf(t *testing.T, document []byte, definition string) []string {
+	t.Helper()
+
+	var schema struct {
+		Defs map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+
+	assert.NoError(t, json.Unmarshal(document, &schema))
+
+	names := make([]string, 0)
+
+	for name := range schema.Defs[definition].Properties {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// serialize returns the JSON the given value is written as.
+func serialize(value any) string {
+	serializedValue, _ := json.Marshal(value)
+	return string(serializedValue)
+}
+
+func TestRequestSchemaDefinesTheFieldsOfTheDriverTypes(t *testing.T) {
+	t.Parallel()
+
+	// Given the published request schema
+	document := schemas.RequestDocument()
+
+	// When comparing its definitions with the types the input is read into
+	// Then I expect each definition to have exactly the fields of its type
+	assert.Equal(t, jsonFieldsOf(driver.Operation{}), propertiesOf(t, document, "operation"))
+	assert.Equal(t, jsonFieldsOf(driver.OpeningBalance{}), propertiesOf(t, document, "opening-balance"))
+	assert.Equal(t, jsonFieldsOf(driver.Correction{}), propertiesOf(t, document, "correction"))
+}
+
+func TestResponseSchemaAcceptsTheOutputsOfTheDriverTypes(t *testing.T) {
+	t.Parallel()
+
+	// Given the outputs the calculation writes
+	outputs := []string{
+		serialize(driver.NewResponse(nil)),
+		serialize([]driver.Tax{driver.NewTax(0), driver.NewTax(10000).WithID("sell-1")}),
+		serialize(driver.NewTaxDiffReport(nil)),
+		serialize(driver.NewMonthlyReport(nil)),
+		serialize(driver.NewResponse(nil).WithSummary()),
+		serialize(driver.ValidationErrors{driver.NewValidationError(driver.ErrMissingField).WithLine(2).WithIndex(0).WithField("date")}),
+		`{"accounts":[{"account":"alice","taxes":[{"tax":0.00}],` +
+			`"summary":{"total-tax":0.00,"quantity":100,"average-unit-cost":10.00,"accumulated-loss":0.00}}]}`,
+	}
+
+	// When validating them against the published response schema
+	// Then I expect every one of them to conform to it
+	for _, output := range outputs {
+		violations, err := schemas.Response().Validate([]byte(output))
+
+		assert.NoError(t, err)
+		assert.Empty(t, violations, output)
+	}
+}
+
+func TestSchemaReportsEveryViolationWithItsPath(t *testing.T) {
+	t.Parallel()
+
+	// Given a document with an unknown operation, a missing quantity and a correction without an id
+	payload := `{"operations":[{"operation":"hold","unit-cost":10.00}],"corrections":[{"action":"cancel"}]}`
+
+	// When validating it against the request schema
+	violations, err := schemas.Request().Validate([]byte(payload))
+
+	// Then I expect each violation located by its path
+	assert.NoError(t, err)
+
+	reported := make([]string, 0, len(violations))
+
+	for _, violation := range violations {
+		reported = append(reported, violation.String())
+	}
+
+	expected := []string{
+		"corrections[0].id: is required",
+		"operations[0].quantity: is required",
+		`operations[0].operation: must match the pattern ^\s*([Bb][Uu][Yy]|[Ss][Ee][Ll][Ll])\s*$`,
+	}
+	assert.Equal(t, expected, reported)
+}
+
+func TestSchemaAcceptsOperationsInAnyCaseAndNumbersWrittenAsStrings(t *testing.T) {
+	t.Parallel()
+
+	// Given operations whose kind is in any case and whose numbers are written as strings, plain and localized
+	payload := `{"opening-balance":{"quantity":"100","average-unit-cost":"R$ 10,00","accumulated-loss":0},` +
+		`"operations":[{"operation":" SELL ","unit-cost":"1234.56","quantity":"50","fees":"R$ 1.234,56"}],` +
+		`"corrections":[{"action":"Cancel","id":"1"}]}`
+
+	// When validating it against the request schema
+	violations, err := schemas.Request().Validate([]byte(payload))
+
+	// Then I expect it to conform to the schema
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestSchemaReportsTheExpectedTypesOfATopLevelValue(t *testing.T) {
+	t.Parallel()
+
+	// Given a top-level number rather than a simulation
+	payload := `42`
+
+	// When validating it against the request schema
+	violations, err := schemas.Request().Validate([]byte(payload))
+
+	// Then I expect a single violation naming the expected types
+	assert.NoError(t, err)
+	assert.Equal(t, []schemas.Violation{{Message: "must be of type array or object"}}, violations)
+}
+
+func TestSchemaReturnsErrorWhenPayloadIsNotJSON(t *testing.T) {
+	t.Parallel()
+
+	// Given a payload that is not JSON
+	payload := `[oops`
+
+	// When validating it against the request schema
+	_, err := schemas.Request().Validate([]byte(payload))
+
+	// Then I expect an error
+	assert.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package schemas
+
+import (
+	_ "embed"
+	"slices"
+)
+
+//go:embed request.schema.json
+var requestSchema []byte
+
+//go:embed response.schema.json
+var responseSchema []byte
+
+// RequestDocument returns the published JSON Schema of a request line.
+func RequestDocument() []byte {
+	return slices.Clone(requestSchema)
+}
+
+// ResponseDocument returns the published JSON Schema of a response line.
+func ResponseDocument() []byte {
+	return slices.Clone(responseSchema)
+}
+
+// Request returns the schema of a request line, embedded in the binary.
+func Request() Schema {
+	return mustParse(requestSchema)
+}
+
+// Response returns the schema of a response line, embedded in the binary.
+func Response() Schema {
+	return mustParse(responseSchema)
+}
+
+// OperationSchema returns the schema of a single operation, as given in each line of the NDJSON input.
+func OperationSchema() Schema {
+	schema := Request()
+
+	return Schema{root: map[string]any{"$ref": localReferencePrefix + "operation", "$defs": schema.root["$defs"]}}
+}
+
+func mustParse(document []byte) Schema {
+	schema, err := Parse(document)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return schema
+}
@@ -0,0 +1,47 @@
+package driver
+
+import (
+	"capital-gains/src/application/domain/events"
+	"capital-gains/src/application/domain/models"
+)
+
+// SimulationSummary sums up a simulation: the tax it paid, what its sells were sold for, the gains
+// and the losses they realized, the loss left to be deducted by every account, and the final position
+// of each account in each ticker it traded.
+type SimulationSummary struct {
+	TotalTax        Amount             `json:"total-tax"`
+	TotalProceeds   Amount             `json:"total-proceeds"`
+	RealizedGain    Amount             `json:"realized-gain"`
+	RealizedLoss    Amount             `json:"realized-loss"`
+	AccumulatedLoss Amount             `json:"accumulated-loss"`
+	Positions       PortfolioPositions `json:"positions"`
+}
+
+func NewSimulationSummary(capitalGains []models.CapitalGain) SimulationSummary {
+	summary := SimulationSummary{Positions: NewPortfolioPositions(capitalGains)}
+
+	for _, capitalGain := range capitalGains {
+		for _, taxEvent := range capitalGain.Events() {
+			trade := taxEvent.Trade()
+			gain := taxEvent.Realization().Gain()
+
+			if trade.Side() == events.SellSide {
+				summary.TotalProceeds += Amount(float64(trade.Quantity()) * trade.UnitCost())
+			}
+
+			if gain > 0 {
+				summary.RealizedGain += Amount(gain)
+			}
+
+			if gain < 0 {
+				summary.RealizedLoss -= Amount(gain)
+			}
+
+			summary.TotalTax += Amount(taxEvent.Amount())
+		}
+
+		summary.AccumulatedLoss += Amount(capitalGain.Holdings().AccumulatedLoss().ToFloat64())
+	}
+
+	return summary
+}
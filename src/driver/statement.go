@@ -0,0 +1,19 @@
+package driver
+
+import (
+	"capital-gains/src/application/domain/models"
+)
+
+// Statement is the whole report of an input line, as rendered for people: the step-by-step
+// calculation of every operation, in input order within each account, along with the tax due per month.
+type Statement struct {
+	Operations []OperationExplanation
+	Report     MonthlyReport
+}
+
+func NewStatement(capitalGains []models.CapitalGain) Statement {
+	return Statement{
+		Operations: NewExplanation(capitalGains).Operations,
+		Report:     NewMonthlyReport(capitalGains),
+	}
+}
@@ -1,16 +1,79 @@
 package driver
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"capital-gains/src/application/domain/models"
+)
 
 type Tax struct {
-	Value float64
+	ID      string
+	Account string
+	Value   float64
 }
 
 func NewTax(value float64) Tax {
 	return Tax{Value: value}
 }
 
+// NewStreamedTaxes returns the tax of every operation of the capital gains, each echoing the id and the
+// account of its operation, as they are written when operations are calculated as they arrive.
+func NewStreamedTaxes(capitalGains []models.CapitalGain) []Tax {
+	taxes := make([]Tax, 0, 1)
+
+	for _, capitalGain := range capitalGains {
+		for _, taxEvent := range capitalGain.Events() {
+			taxes = append(taxes, NewTax(taxEvent.Amount()).WithID(taxEvent.OperationID()).WithAccount(capitalGain.Account()))
+		}
+	}
+
+	return taxes
+}
+
+// WithID returns the tax echoing the id of the operation it was calculated for.
+func (tax Tax) WithID(id string) Tax {
+	tax.ID = id
+	return tax
+}
+
+// WithAccount returns the tax echoing the account of the operation it was calculated for.
+func (tax Tax) WithAccount(account string) Tax {
+	tax.Account = account
+	return tax
+}
+
 func (tax Tax) MarshalJSON() ([]byte, error) {
-	jsonObject := fmt.Sprintf("{\"tax\":%.2f}", tax.Value)
-	return []byte(jsonObject), nil
+	var jsonObject strings.Builder
+
+	jsonObject.WriteString("{")
+
+	for _, field := range [][2]string{{"id", tax.ID}, {"account", tax.Account}} {
+		if field[1] == "" {
+			continue
+		}
+
+		serializedValue, err := json.Marshal(field[1])
+
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(&jsonObject, "%q:%s,", field[0], serializedValue)
+	}
+
+	fmt.Fprintf(&jsonObject, "\"tax\":%.2f}", tax.Value)
+
+	return []byte(jsonObject.String()), nil
+}
+
+func (tax Tax) ToString() string {
+	serializedTax, err := json.Marshal(tax)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return string(serializedTax)
 }
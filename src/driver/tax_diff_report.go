@@ -0,0 +1,89 @@
+package driver
+
+import (
+	"encoding/json"
+	"time"
+
+	"capital-gains/src/application/domain/models"
+)
+
+// monthLayout is the layout of the month a tax is due in (e.g., 2024-03).
+const monthLayout = "2006-01"
+
+type OperationTaxDiff struct {
+	Index      int    `json:"index"`
+	Account    string `json:"account,omitempty"`
+	ID         string `json:"id,omitempty"`
+	Month      string `json:"month,omitempty"`
+	Status     string `json:"status"`
+	TaxBefore  Amount `json:"tax-before"`
+	TaxAfter   Amount `json:"tax-after"`
+	Difference Amount `json:"difference"`
+}
+
+type MonthlyTaxDiff struct {
+	Account    string `json:"account,omitempty"`
+	Month      string `json:"month,omitempty"`
+	TaxBefore  Amount `json:"tax-before"`
+	TaxAfter   Amount `json:"tax-after"`
+	Difference Amount `json:"difference"`
+}
+
+// TaxDiffReport is the output of an input line carrying corrections: the taxes per
+// operation from the first correction onward, and the months whose tax must be rectified.
+type TaxDiffReport struct {
+	Operations []OperationTaxDiff `json:"operations"`
+	Months     []MonthlyTaxDiff   `json:"months"`
+}
+
+func NewTaxDiffReport(taxDiffs []models.TaxDiff) TaxDiffReport {
+	report := TaxDiffReport{
+		Operations: make([]OperationTaxDiff, 0),
+		Months:     make([]MonthlyTaxDiff, 0),
+	}
+
+	for _, taxDiff := range taxDiffs {
+		for _, operation := range taxDiff.Operations() {
+			report.Operations = append(report.Operations, OperationTaxDiff{
+				Index:      operation.Index(),
+				Account:    taxDiff.Account(),
+				ID:         operation.ID(),
+				Month:      formatMonth(operation.Month()),
+				Status:     string(operation.Status()),
+				TaxBefore:  Amount(operation.Before().ToFloat64()),
+				TaxAfter:   Amount(operation.After().ToFloat64()),
+				Difference: Amount(operation.Difference().ToFloat64()),
+			})
+		}
+
+		for _, month := range taxDiff.Months() {
+			report.Months = append(report.Months, MonthlyTaxDiff{
+				Account:    taxDiff.Account(),
+				Month:      formatMonth(month.Month()),
+				TaxBefore:  Amount(month.Before().ToFloat64()),
+				TaxAfter:   Amount(month.After().ToFloat64()),
+				Difference: Amount(month.Difference().ToFloat64()),
+			})
+		}
+	}
+
+	return report
+}
+
+func (report TaxDiffReport) ToString() string {
+	serializedReport, err := json.Marshal(report)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return string(serializedReport)
+}
+
+func formatMonth(month time.Time) string {
+	if month.IsZero() {
+		return ""
+	}
+
+	return month.Format(monthLayout)
+}
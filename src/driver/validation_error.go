@@ -0,0 +1,212 @@
+package driver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrMalformedInput is returned when a value of the input cannot be read as a simulation at all.
+	ErrMalformedInput = errors.New("malformed input")
+
+	// ErrMissingField is returned when a field required to build a command is absent or empty.
+	ErrMissingField = errors.New("missing field")
+
+	// ErrInvalidValue is returned when a field holds a value outside of its domain.
+	ErrInvalidValue = errors.New("invalid value")
+
+	// ErrUnknownField is returned in strict mode when an object has a field the contract does not define.
+	ErrUnknownField = errors.New("unknown field")
+
+	// ErrUnsupportedOperation is returned when the operation is neither a buy nor a sell.
+	ErrUnsupportedOperation = errors.New("unsupported operation")
+
+	// ErrUnsupportedCorrection is returned when the correction is neither an amend nor a cancel.
+	ErrUnsupportedCorrection = errors.New("unsupported correction")
+
+	// ErrUnknownOperationID is returned when a correction targets an id no operation of the input has.
+	ErrUnknownOperationID = errors.New("unknown operation id")
+
+	// ErrSchemaViolation is returned when a value of the input breaks the published JSON Schema.
+	ErrSchemaViolation = errors.New("schema violation")
+
+	// ErrCalculationFailed is returned when a simulation that passed validation still cannot be calculated.
+	ErrCalculationFailed = errors.New("calculation failed")
+)
+
+// noIndex is the index of a validation error that does not concern a single operation.
+const noIndex = -1
+
+// ValidationError is a problem found in the input before any tax is calculated, located by the line
+// its simulation starts on and, when it concerns a single operation, the index of that operation.
+type ValidationError struct {
+	Line  int
+	Index int
+	Field string
+	Err   error
+}
+
+func NewValidationError(err error) ValidationError {
+	return ValidationError{Index: noIndex, Err: err}
+}
+
+// WithLine returns the error located at the given line of the input, counted from 1.
+func (validationError ValidationError) WithLine(line int) ValidationError {
+	validationError.Line = line
+	return validationError
+}
+
+// WithIndex returns the error located at the operation of the given index, counted from 0.
+func (validationError ValidationError) WithIndex(index int) ValidationError {
+	validationError.Index = index
+	return validationError
+}
+
+// WithField returns the error located at the given field, named as in the input.
+func (validationError ValidationError) WithField(field string) ValidationError {
+	validationError.Field = field
+	return validationError
+}
+
+// Code returns the machine-readable kind of the error, such as invalid-value.
+func (validationError ValidationError) Code() string {
+	for _, kind := range []error{
+		ErrMalformedInput,
+		ErrMissingField,
+		ErrInvalidValue,
+		ErrUnknownField,
+		ErrUnsupportedOperation,
+		ErrUnsupportedCorrection,
+		ErrUnknownOperationID,
+		ErrSchemaViolation,
+		ErrCalculationFailed,
+	} {
+		if errors.Is(validationError.Err, kind) {
+			return strings.ReplaceAll(kind.Error(), " ", "-")
+		}
+	}
+
+	return strings.ReplaceAll(ErrMalformedInput.Error(), " ", "-")
+}
+
+func (validationError ValidationError) Error() string {
+	location := make([]string, 0, 3)
+
+	if validationError.Line > 0 {
+		location = append(location, fmt.Sprintf("line %d", validationError.Line))
+	}
+
+	if validationError.Index != noIndex {
+		location = append(location, fmt.Sprintf("operation %d", validationError.Index))
+	}
+
+	if validationError.Field != "" {
+		location = append(location, validationError.Field)
+	}
+
+	if len(location) == 0 {
+		return validationError.Err.Error()
+	}
+
+	return fmt.Sprintf("%s: %s", strings.Join(location, ", "), validationError.Err)
+}
+
+func (validationError ValidationError) Unwrap() error {
+	return validationError.Err
+}
+
+func (validationError ValidationError) MarshalJSON() ([]byte, error) {
+	serialized := struct {
+		Line    int    `json:"line,omitempty"`
+		Index   *int   `json:"index,omitempty"`
+		Field   string `json:"field,omitempty"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{
+		Line:    validationError.Line,
+		Field:   validationError.Field,
+		Code:    validationError.Code(),
+		Message: validationError.Err.Error(),
+	}
+
+	if validationError.Index != noIndex {
+		serialized.Index = &validationError.Index
+	}
+
+	return json.Marshal(serialized)
+}
+
+// ValidationErrors are all the problems found in a simulation, written in place of its output.
+type ValidationErrors []ValidationError
+
+// WithLine returns the errors located at the given line of the input, counted from 1.
+func (validationErrors ValidationErrors) WithLine(line int) ValidationErrors {
+	located := make(ValidationErrors, 0, len(validationErrors))
+
+	for _, validationError := range validationErrors {
+		located = append(located, validationError.WithLine(line))
+	}
+
+	return located
+}
+
+// WithIndex returns the errors located at the operation of the given index, counted from 0.
+func (validationErrors ValidationErrors) WithIndex(index int) ValidationErrors {
+	located := make(ValidationErrors, 0, len(validationErrors))
+
+	for _, validationError := range validationErrors {
+		located = append(located, validationError.WithIndex(index))
+	}
+
+	return located
+}
+
+// Within returns the errors with their fields nested in the given one, such as corrections[0].
+func (validationErrors ValidationErrors) Within(field string) ValidationErrors {
+	located := make(ValidationErrors, 0, len(validationErrors))
+
+	for _, validationError := range validationErrors {
+		nestedField := field
+
+		if validationError.Field != "" {
+			nestedField = field + "." + validationError.Field
+		}
+
+		located = append(located, validationError.WithField(nestedField))
+	}
+
+	return located
+}
+
+func (validationErrors ValidationErrors) Error() string {
+	messages := make([]string, 0, len(validationErrors))
+
+	for _, validationError := range validationErrors {
+		messages = append(messages, validationError.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (validationErrors ValidationErrors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Errors []ValidationError `json:"errors"`
+	}{Errors: validationErrors})
+}
+
+func (validationErrors ValidationErrors) ToString() string {
+	serializedErrors, err := json.Marshal(validationErrors)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return string(serializedErrors)
+}
+
+// fieldError returns the error of a field, with the detail of what is wrong with its value.
+func fieldError(field string, kind error, detail string) ValidationError {
+	return NewValidationError(fmt.Errorf("%w: %s", kind, detail)).WithField(field)
+}
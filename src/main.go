@@ -1,8 +1,11 @@
 package main
 
-import "capital-gains/src/starter"
+import (
+	"os"
+
+	"capital-gains/src/starter"
+)
 
 func main() {
-	dependencies := starter.NewDependencies()
-	dependencies.CalculateCapitalGain.Handle()
+	os.Exit(starter.Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
@@ -0,0 +1,323 @@
+package starter
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"capital-gains/src/driver/console"
+	"capital-gains/src/driver/formatters"
+	"capital-gains/src/driver/importers"
+	"capital-gains/src/driver/locales"
+	"capital-gains/src/driver/schemas"
+)
+
+// Exit codes of the command line.
+const (
+	// ExitSuccess means every simulation of the input was calculated. Entries of imported files that
+	// are not operations, such as income, are still reported on the standard error.
+	ExitSuccess = 0
+
+	// ExitRejectedInput means some simulations were rejected, their validation errors being written
+	// in place of their output.
+	ExitRejectedInput = 1
+
+	// ExitUsageError means the command line itself is wrong: an unknown command or flag, or settings
+	// that cannot be combined.
+	ExitUsageError = 2
+
+	// ExitIOError means the input could not be read or the output could not be written.
+	ExitIOError = 3
+)
+
+const (
+	calculateCommand = "calculate"
+	explainCommand   = "explain"
+	reportCommand    = "report"
+	validateCommand  = "validate"
+	serveCommand     = "serve"
+	daemonCommand    = "daemon"
+	helpCommand      = "help"
+
+	// defaultProfilesPath is the config file the import profiles are read from, unless given otherwise.
+	defaultProfilesPath = "capital-gains.profiles.json"
+)
+
+const usage = `Usage: capital-gains [command] [flags]
+
+Commands:
+  calculate  write the tax of each operation, one line per simulation (default)
+  explain    write the step-by-step calculation of each simulation
+  report     write the tax due per month of each simulation
+  validate   check the input against the published schema, without calculating taxes
+  serve      calculate taxes over HTTP, posting the operations to /v1/capital-gains
+  daemon     answer JSON-RPC 2.0 requests over stdin and stdout, or a Unix domain socket
+
+Run 'capital-gains <command> -h' for the flags of a command.
+`
+
+// streams are the standard streams of the process, or their replacements in tests.
+type streams struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// ioOptions are the flags every command has to choose its input, output and output format.
+type ioOptions struct {
+	input  string
+	output string
+	format string
+}
+
+// Run executes the command line given by the arguments, without the program name, returning the
+// exit code of the process. Without a command, the arguments are read as the flags of calculate,
+// as the program did before having commands.
+func Run(arguments []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	processStreams := streams{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(arguments) == 0 || strings.HasPrefix(arguments[0], "-") {
+		return processStreams.calculate("capital-gains", arguments, console.Settings{}, true)
+	}
+
+	switch command, commandArguments := arguments[0], arguments[1:]; command {
+	case calculateCommand:
+		return processStreams.calculate(command, commandArguments, console.Settings{}, false)
+	case explainCommand:
+		return processStreams.calculate(command, commandArguments, console.Settings{Explain: true}, false)
+	case reportCommand:
+		return processStreams.calculate(command, commandArguments, console.Settings{Report: true}, false)
+	case validateCommand:
+		return processStreams.validate(commandArguments)
+	case serveCommand:
+		return processStreams.serve(commandArguments)
+	case daemonCommand:
+		return processStreams.daemon(commandArguments)
+	case helpCommand:
+		_, _ = fmt.Fprint(stdout, usage)
+		return ExitSuccess
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown command %q\n\n%s", command, usage)
+		return ExitUsageError
+	}
+}
+
+// calculate runs a calculation with the given settings, adding the ones selected by flags. The
+// legacy command line also takes the explain flag, which calculate leaves to its own command.
+func (processStreams streams) calculate(name string, arguments []string, settings console.Settings, legacy bool) int {
+	flags := processStreams.flagSet(name)
+	options := registerIOOptions(flags)
+
+	flags.BoolVar(
+		&settings.Chronological,
+		"chronological",
+		false,
+		"apply the operations of each line in the order they were traded (requires dated operations)",
+	)
+	flags.BoolVar(&settings.Strict, "strict", false, "reject JSON input with unknown or absent fields and lossy numbers")
+	flags.IntVar(&settings.Workers, "workers", 1, "calculate up to that many lines at the same time, writing them in input order")
+	flags.Func("input-format", "read the input as auto (default), json, ndjson, csv, b3 or ofx", func(name string) error {
+		inputFormat, err := console.ParseInputFormat(name)
+		settings.Input = inputFormat
+
+		return err
+	})
+
+	if name == calculateCommand || legacy {
+		flags.BoolVar(&settings.Summary, "summary", false, "wrap the taxes of each line along with its totals and final position")
+		flags.BoolVar(&settings.Stream, "stream", false, "read JSON arrays of any size as a stream, writing taxes as they go")
+	}
+
+	if legacy {
+		flags.BoolVar(&settings.Explain, "explain", false, "write the step-by-step calculation of each line")
+	}
+
+	flags.Func("columns", "optional columns next to the tax: operation, quantity, price, gain, remaining-loss", func(names string) error {
+		columns, err := formatters.ParseColumns(names)
+		settings.Columns = columns
+
+		return err
+	})
+
+	flags.Func("locale", "read amounts given as text and write tables in pt-BR or en-US", func(name string) error {
+		locale, err := locales.Parse(name)
+		settings.Locale = locale
+
+		return err
+	})
+
+	profileName := flags.String("profile", "", "read the input as CSV with the named import profile")
+	profilesPath := flags.String("profiles", defaultProfilesPath, "config file holding the import profiles")
+
+	if exitCode, ok := processStreams.parse(flags, arguments); !ok {
+		return exitCode
+	}
+
+	output, err := formatters.ParseFormat(options.format)
+
+	if err != nil {
+		return processStreams.fail(ExitUsageError, err)
+	}
+
+	settings.Output = output
+
+	if err := applyProfile(&settings, *profilesPath, *profileName); err != nil {
+		return processStreams.fail(ExitUsageError, err)
+	}
+
+	if err := settings.Validate(); err != nil {
+		return processStreams.fail(ExitUsageError, err)
+	}
+
+	return processStreams.run(options, func(dependencies Dependencies) error {
+		return dependencies.CalculateCapitalGain.Handle()
+	}, settings)
+}
+
+// applyProfile reads the input as CSV with the named import profile, when one is named.
+func applyProfile(settings *console.Settings, profilesPath string, profileName string) error {
+	if profileName == "" {
+		return nil
+	}
+
+	profile, err := importers.LoadProfile(profilesPath, profileName)
+
+	if err != nil {
+		return err
+	}
+
+	settings.Input = console.CSVInput
+	settings.Profile = &profile
+
+	return nil
+}
+
+// validate checks the input against the published request schema, without calculating any tax.
+func (processStreams streams) validate(arguments []string) int {
+	settings := console.Settings{Input: console.JSONInput}
+	flags := processStreams.flagSet(validateCommand)
+	options := registerIOOptions(flags)
+
+	flags.Func("input-format", "validate the input as json (default) or ndjson", func(name string) error {
+		inputFormat, err := console.ParseInputFormat(name)
+
+		if err == nil && inputFormat != console.JSONInput && inputFormat != console.NDJSONInput {
+			return fmt.Errorf("%w: only JSON input has a schema", console.ErrIncompatibleSettings)
+		}
+
+		settings.Input = inputFormat
+
+		return err
+	})
+
+	printSchema := flags.String("print-schema", "", "write the published schema, request or response, instead of validating")
+
+	if exitCode, ok := processStreams.parse(flags, arguments); !ok {
+		return exitCode
+	}
+
+	if options.format != string(formatters.JSONFormat) {
+		return processStreams.fail(ExitUsageError, fmt.Errorf("unsupported format %q: validation errors are written as JSON", options.format))
+	}
+
+	switch *printSchema {
+	case "":
+	case "request":
+		_, _ = processStreams.stdout.Write(schemas.RequestDocument())
+		return ExitSuccess
+	case "response":
+		_, _ = processStreams.stdout.Write(schemas.ResponseDocument())
+		return ExitSuccess
+	default:
+		return processStreams.fail(ExitUsageError, fmt.Errorf("unknown schema %q: expected request or response", *printSchema))
+	}
+
+	return processStreams.run(options, func(dependencies Dependencies) error {
+		return dependencies.ValidateInput.Handle()
+	}, settings)
+}
+
+func (processStreams streams) flagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(processStreams.stderr)
+
+	return flags
+}
+
+func registerIOOptions(flags *flag.FlagSet) *ioOptions {
+	options := new(ioOptions)
+
+	flags.StringVar(&options.input, "input", "", "file to read the input from (default stdin)")
+	flags.StringVar(&options.output, "output", "", "file to write the output to (default stdout)")
+	flags.StringVar(&options.format, "format", string(formatters.JSONFormat), "format of the output: json, table, csv, markdown or html")
+
+	return options
+}
+
+// parse parses the flags, returning the exit code to stop with when they are wrong or help was asked for.
+func (processStreams streams) parse(flags *flag.FlagSet, arguments []string) (int, bool) {
+	if err := flags.Parse(arguments); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitSuccess, false
+		}
+
+		return ExitUsageError, false
+	}
+
+	if flags.NArg() > 0 {
+		return processStreams.fail(ExitUsageError, fmt.Errorf("unexpected argument %q", flags.Arg(0))), false
+	}
+
+	return ExitSuccess, true
+}
+
+// run handles the use case over the input and output chosen by the options.
+func (processStreams streams) run(options *ioOptions, handle func(Dependencies) error, settings console.Settings) int {
+	input, output := processStreams.stdin, processStreams.stdout
+
+	if options.input != "" {
+		inputFile, err := os.Open(options.input)
+
+		if err != nil {
+			return processStreams.fail(ExitIOError, err)
+		}
+
+		defer func() { _ = inputFile.Close() }()
+		input = inputFile
+	}
+
+	if options.output != "" {
+		outputFile, err := os.Create(options.output)
+
+		if err != nil {
+			return processStreams.fail(ExitIOError, err)
+		}
+
+		defer func() { _ = outputFile.Close() }()
+		output = outputFile
+	}
+
+	defaultConsole := console.NewDefaultConsoleWith(input, output)
+	err := handle(NewDependenciesWith(settings, defaultConsole))
+
+	switch {
+	case defaultConsole.Err() != nil:
+		return processStreams.fail(ExitIOError, defaultConsole.Err())
+	case err == nil:
+		return ExitSuccess
+	case errors.Is(err, console.ErrRejectedInput):
+		return processStreams.fail(ExitRejectedInput, err)
+	case errors.Is(err, console.ErrSkippedInput):
+		return processStreams.fail(ExitSuccess, err)
+	default:
+		return processStreams.fail(ExitIOError, err)
+	}
+}
+
+func (processStreams streams) fail(exitCode int, err error) int {
+	_, _ = fmt.Fprintln(processStreams.stderr, err)
+	return exitCode
+}
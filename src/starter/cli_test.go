@@ -0,0 +1,236 @@
+package starter_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"capital-gains/src/starter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const operations = `[{"operation":"buy","unit-cost":10.00,"quantity":10000},` +
+	`{"operation":"sell","unit-cost":20.00,"quantity":5000}]`
+
+func run(arguments []string, input string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+
+	exitCode := starter.Run(arguments, strings.NewReader(input), &stdout, &stderr)
+
+	return exitCode, stdout.String(), stderr.String()
+}
+
+func TestRunCalculatesTaxesWithoutArguments(t *testing.T) {
+	t.Parallel()
+
+	// Given operations read from stdin
+	input := operations + "\n"
+
+	// When running the command line without arguments
+	exitCode, stdout, _ := run(nil, input)
+
+	// Then I expect the taxes to be written as they always were
+	assert.Equal(t, starter.ExitSuccess, exitCode)
+	assert.Equal(t, `[{"tax":0.00},{"tax":10000.00}]`+"\n", stdout)
+}
+
+func TestRunReadsLegacyFlagsWithoutACommand(t *testing.T) {
+	t.Parallel()
+
+	// Given operations read from stdin
+	input := operations + "\n"
+
+	// When running the command line with the flags it took before having commands
+	exitCode, stdout, _ := run([]string{"-explain"}, input)
+
+	// Then I expect the flags to be applied to the calculation
+	assert.Equal(t, starter.ExitSuccess, exitCode)
+	assert.True(t, strings.HasPrefix(stdout, `{"operations":[`))
+}
+
+func TestRunCalculatesTaxesFromInputFileToOutputFile(t *testing.T) {
+	t.Parallel()
+
+	// Given operations stored in a file
+	directory := t.TempDir()
+	inputPath := filepath.Join(directory, "operations.json")
+	outputPath := filepath.Join(directory, "taxes.json")
+	assert.NoError(t, os.WriteFile(inputPath, []byte(operations+"\n"), 0o600))
+
+	// When running the calculate command over the file
+	exitCode, stdout, _ := run([]string{"calculate", "--input", inputPath, "--output", outputPath}, "")
+
+	// Then I expect the taxes to be written to the output file only
+	output, err := os.ReadFile(outputPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, starter.ExitSuccess, exitCode)
+	assert.Empty(t, stdout)
+	assert.Equal(t, `[{"tax":0.00},{"tax":10000.00}]`+"\n", string(output))
+}
+
+func TestRunWritesTheMonthlyReport(t *testing.T) {
+	t.Parallel()
+
+	// Given dated operations read from stdin
+	input := `[{"date":"2024-01-10","operation":"buy","unit-cost":10.00,"quantity":10000},` +
+		`{"date":"2024-02-05","operation":"sell","unit-cost":20.00,"quantity":5000}]` + "\n"
+
+	// When running the report command
+	exitCode, stdout, _ := run([]string{"report"}, input)
+
+	// Then I expect the taxes to be summed up per month
+	expected := `{"months":[` +
+		`{"month":"2024-01","proceeds":0.00,"gain":0.00,"tax":0.00},` +
+		`{"month":"2024-02","proceeds":100000.00,"gain":50000.00,"tax":10000.00}],` +
+		`"total-tax":10000.00}` + "\n"
+	assert.Equal(t, starter.ExitSuccess, exitCode)
+	assert.Equal(t, expected, stdout)
+}
+
+func TestRunWritesTaxesInTheSelectedFormat(t *testing.T) {
+	t.Parallel()
+
+	// Given operations read from stdin
+	input := operations + "\n"
+
+	// When running the calculate command asking for CSV with the gain column
+	exitCode, stdout, _ := run([]string{"calculate", "--format", "csv", "--columns", "gain"}, input)
+
+	// Then I expect a CSV file with the gain of each operation
+	expected := "simulation,account,id,gain,tax,error\n" +
+		"1,,,0.00,0.00,\n" +
+		"1,,,50000.00,10000.00,\n"
+	assert.Equal(t, starter.ExitSuccess, exitCode)
+	assert.Equal(t, expected, stdout)
+}
+
+func TestRunWritesTaxesInTheSelectedLocale(t *testing.T) {
+	t.Parallel()
+
+	// Given operations whose unit costs are written as text in Brazilian Portuguese
+	input := `[{"operation":"buy","unit-cost":"10,00","quantity":10000},` +
+		`{"operation":"sell","unit-cost":"20,00","quantity":5000}]` + "\n"
+
+	// When running the calculate command as a table in the pt-BR locale
+	exitCode, stdout, _ := run([]string{"calculate", "--locale", "pt-BR", "--format", "table"}, input)
+
+	// Then I expect the taxes to be written in reais
+	expected := "         Tax\n" +
+		"------------\n" +
+		"     R$ 0,00\n" +
+		"R$ 10.000,00\n"
+	assert.Equal(t, starter.ExitSuccess, exitCode)
+	assert.Equal(t, expected, stdout)
+}
+
+func TestRunCalculatesLinesWithSeveralWorkers(t *testing.T) {
+	t.Parallel()
+
+	// Given two simulations read from stdin
+	input := operations + "\n" + `[{"operation":"buy","unit-cost":10.00,"quantity":100}]` + "\n"
+
+	// When running the calculate command with several workers
+	exitCode, stdout, _ := run([]string{"calculate", "--workers", "4"}, input)
+
+	// Then I expect the taxes of each line in the order of the input
+	assert.Equal(t, starter.ExitSuccess, exitCode)
+	assert.Equal(t, "[{\"tax\":0.00},{\"tax\":10000.00}]\n[{\"tax\":0.00}]\n", stdout)
+}
+
+func TestRunReportsSkippedTransactionsOfAnImportedStatement(t *testing.T) {
+	t.Parallel()
+
+	// Given an OFX statement with a buy and a reinvestment
+	input := "<OFX><INVTRANLIST>\n" +
+		"<BUYSTOCK><INVBUY><INVTRAN><FITID>1<DTTRADE>20240110</INVTRAN>\n" +
+		"<SECID><UNIQUEID>PETR4</SECID><UNITS>100<UNITPRICE>10.00</INVBUY></BUYSTOCK>\n" +
+		"<REINVEST><INVTRAN><FITID>2<DTTRADE>20240115</INVTRAN><UNITS>1<UNITPRICE>10.00</REINVEST>\n" +
+		"</INVTRANLIST></OFX>\n"
+
+	// When running the calculate command
+	exitCode, stdout, stderr := run([]string{"calculate"}, input)
+
+	// Then I expect the buy to be calculated, and the reinvestment to be reported on stderr
+	assert.Equal(t, starter.ExitSuccess, exitCode)
+	assert.Equal(t, "[{\"id\":\"1\",\"tax\":0.00}]\n", stdout)
+	assert.Equal(t, "skipped input: line 1: unsupported transaction: REINVEST (2)\n", stderr)
+}
+
+func TestRunReturnsIOErrorWhenTheOutputCannotBeWritten(t *testing.T) {
+	t.Parallel()
+
+	// Given an output whose reader is already gone
+	reader, writer, err := os.Pipe()
+	assert.NoError(t, err)
+	assert.NoError(t, reader.Close())
+
+	defer func() { _ = writer.Close() }()
+
+	var stderr bytes.Buffer
+
+	// When running the calculate command over it
+	exitCode := starter.Run([]string{"calculate"}, strings.NewReader(operations+"\n"), writer, &stderr)
+
+	// Then I expect the write error to be reported instead of a panic
+	assert.Equal(t, starter.ExitIOError, exitCode)
+	assert.Contains(t, stderr.String(), "broken pipe")
+}
+
+func TestRunPrintsThePublishedSchema(t *testing.T) {
+	t.Parallel()
+
+	// When running the validate command asking for the request schema
+	exitCode, stdout, _ := run([]string{"validate", "--print-schema", "request"}, "")
+
+	// Then I expect the schema document to be written
+	assert.Equal(t, starter.ExitSuccess, exitCode)
+	assert.Contains(t, stdout, `"$schema"`)
+}
+
+func TestRunReturnsExitCodes(t *testing.T) {
+	t.Parallel()
+
+	// Given command lines failing in different ways
+	cases := []struct {
+		name      string
+		arguments []string
+		input     string
+		expected  int
+	}{
+		{name: "rejected input", arguments: []string{"calculate"}, input: `[{"operation":"hold"}]` + "\n", expected: starter.ExitRejectedInput},
+		{name: "unknown command", arguments: []string{"compute"}, expected: starter.ExitUsageError},
+		{name: "unknown flag", arguments: []string{"calculate", "--unknown"}, expected: starter.ExitUsageError},
+		{name: "unexpected argument", arguments: []string{"calculate", "operations.json"}, expected: starter.ExitUsageError},
+		{name: "unsupported format", arguments: []string{"calculate", "--format", "xml"}, expected: starter.ExitUsageError},
+		{name: "unsupported column", arguments: []string{"calculate", "--columns", "fees"}, expected: starter.ExitUsageError},
+		{name: "unsupported locale", arguments: []string{"calculate", "--locale", "fr-FR"}, expected: starter.ExitUsageError},
+		{name: "negative workers", arguments: []string{"calculate", "--workers", "-1"}, expected: starter.ExitUsageError},
+		{name: "report in table format", arguments: []string{"report", "--format", "table"}, expected: starter.ExitUsageError},
+		{name: "server without port", arguments: []string{"serve", "--addr", "localhost"}, expected: starter.ExitUsageError},
+		{name: "server without body", arguments: []string{"serve", "--max-body-bytes", "0"}, expected: starter.ExitUsageError},
+		{name: "daemon with argument", arguments: []string{"daemon", "requests.jsonl"}, expected: starter.ExitUsageError},
+		{name: "daemon socket without directory", arguments: []string{"daemon", "--socket", filepath.Join(t.TempDir(), "absent", "capital-gains.sock")}, expected: starter.ExitIOError},
+		{name: "formatted validation", arguments: []string{"validate", "--format", "table"}, expected: starter.ExitUsageError},
+		{name: "incompatible settings", arguments: []string{"report", "--chronological", "--input-format", "csv", "--strict"}, expected: starter.ExitUsageError},
+		{name: "missing input file", arguments: []string{"calculate", "--input", filepath.Join(t.TempDir(), "absent.json")}, expected: starter.ExitIOError},
+		{name: "unreadable input file", arguments: []string{"calculate", "--input", t.TempDir()}, expected: starter.ExitIOError},
+		{name: "unreadable streamed input", arguments: []string{"calculate", "--stream", "--input", t.TempDir()}, expected: starter.ExitIOError},
+		{name: "help", arguments: []string{"explain", "-h"}, expected: starter.ExitSuccess},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// When running each of them
+			exitCode, _, _ := run(testCase.arguments, testCase.input)
+
+			// Then I expect the exit code telling how it failed
+			assert.Equal(t, testCase.expected, exitCode)
+		})
+	}
+}
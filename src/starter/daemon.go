@@ -0,0 +1,75 @@
+package starter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"capital-gains/src/driver/jsonrpc"
+)
+
+// daemon answers JSON-RPC requests over the standard streams until the input ends, or over a Unix
+// domain socket, when one is given, until the process is interrupted or terminated.
+func (processStreams streams) daemon(arguments []string) int {
+	settings := jsonrpc.Settings{}
+	flags := processStreams.flagSet(daemonCommand)
+
+	socket := flags.String("socket", "", "path of the Unix domain socket to listen on (default stdin and stdout)")
+	flags.BoolVar(
+		&settings.Chronological,
+		"chronological",
+		false,
+		"apply the operations of each calculation in the order they were traded (requires dated operations)",
+	)
+	flags.BoolVar(&settings.Strict, "strict", false, "reject params with unknown or absent fields and lossy numbers")
+
+	if exitCode, ok := processStreams.parse(flags, arguments); !ok {
+		return exitCode
+	}
+
+	server := NewDaemonServer(settings)
+
+	if *socket == "" {
+		if err := server.Serve(processStreams.stdin, processStreams.stdout); err != nil {
+			return processStreams.fail(ExitIOError, err)
+		}
+
+		return ExitSuccess
+	}
+
+	listener, err := jsonrpc.Listen(*socket)
+
+	if err != nil {
+		return processStreams.fail(ExitIOError, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return processStreams.accept(ctx, listener, server)
+}
+
+// accept answers the connections of the listener until the context is done, then closes it, which
+// removes the socket file. Connections still open are dropped along with the process.
+func (processStreams streams) accept(ctx context.Context, listener net.Listener, server *jsonrpc.Server) int {
+	served := make(chan error, 1)
+
+	go func() { served <- server.ServeListener(listener) }()
+
+	_, _ = fmt.Fprintf(processStreams.stderr, "listening on %s\n", listener.Addr())
+
+	select {
+	case err := <-served:
+		return processStreams.fail(ExitIOError, err)
+	case <-ctx.Done():
+	}
+
+	if err := listener.Close(); err != nil {
+		return processStreams.fail(ExitIOError, err)
+	}
+
+	return ExitSuccess
+}
@@ -0,0 +1,26 @@
+package starter_test
+
+import (
+	"testing"
+
+	"capital-gains/src/starter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunAnswersJSONRPCRequestsOverTheStandardStreams(t *testing.T) {
+	t.Parallel()
+
+	// Given a calculation and an operation applied to the portfolio, read from stdin
+	input := `{"jsonrpc":"2.0","id":1,"method":"calculate","params":` + operations + `}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"portfolio.apply","params":[{"operation":"buy","unit-cost":10.00,"quantity":100}]}` + "\n"
+
+	// When running the daemon command until the input ends
+	exitCode, stdout, _ := run([]string{"daemon"}, input)
+
+	// Then I expect a response per request, in the order they were sent
+	expected := `{"jsonrpc":"2.0","id":1,"result":[{"tax":0.00},{"tax":10000.00}]}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"result":[{"tax":0.00}]}` + "\n"
+	assert.Equal(t, starter.ExitSuccess, exitCode)
+	assert.Equal(t, expected, stdout)
+}
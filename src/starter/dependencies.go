@@ -1,37 +1,51 @@
 package starter
 
 import (
-	"capital-gains/src/application/handlers"
-	"capital-gains/src/driven/capitalgains"
-	"capital-gains/src/driven/operations"
+	"net/http"
+
 	"capital-gains/src/driver/console"
+	"capital-gains/src/driver/jsonrpc"
+	"capital-gains/src/driver/rest"
+	"capital-gains/src/starter/wiring"
 )
 
 type Dependencies struct {
 	CalculateCapitalGain console.CalculateCapitalGain
+	ValidateInput        console.ValidateInput
+}
+
+func NewDependencies(settings console.Settings) Dependencies {
+	return NewDependenciesWith(settings, console.NewDefaultConsole())
 }
 
-func NewDependencies() Dependencies {
-	operationsRepository := operations.NewRepository()
-	capitalGainsRepository := capitalgains.NewRepository()
-
-	registerBuyHandler := handlers.NewRegisterBuyHandler(operationsRepository)
-	registerSellHandler := handlers.NewRegisterSellHandler(operationsRepository)
-	calculateCapitalGainHandler := handlers.NewCalculateCapitalGainHandler(
-		operationsRepository,
-		capitalGainsRepository,
-	)
-
-	defaultConsole := console.NewDefaultConsole()
-	calculateCapitalGain := console.NewCalculateCapitalGain(
-		defaultConsole,
-		registerBuyHandler,
-		registerSellHandler,
-		capitalGainsRepository,
-		calculateCapitalGainHandler,
-	)
+// NewDependenciesWith wires the use cases over the given console instead of the standard streams.
+func NewDependenciesWith(settings console.Settings, defaultConsole console.Console) Dependencies {
+	calculateCapitalGain := console.NewCalculateCapitalGain(defaultConsole, settings, wiring.NewUnitOfWork)
+	validateInput := console.NewValidateInput(defaultConsole, settings)
 
 	return Dependencies{
 		CalculateCapitalGain: *calculateCapitalGain,
+		ValidateInput:        *validateInput,
 	}
 }
+
+// ServerDependencies are the handlers of the HTTP server, along with its health.
+type ServerDependencies struct {
+	Router http.Handler
+	Health *rest.Health
+}
+
+func NewServerDependencies(settings rest.Settings) ServerDependencies {
+	health := rest.NewHealth()
+	calculateCapitalGain := rest.NewCalculateCapitalGain(settings, wiring.NewUnitOfWork)
+
+	return ServerDependencies{
+		Router: rest.NewRouter(calculateCapitalGain, health),
+		Health: health,
+	}
+}
+
+// NewDaemonServer wires the JSON-RPC server answering the requests of the daemon.
+func NewDaemonServer(settings jsonrpc.Settings) *jsonrpc.Server {
+	return jsonrpc.NewServer(settings, wiring.NewUnitOfWork)
+}
@@ -0,0 +1,89 @@
+package starter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"capital-gains/src/driver/rest"
+)
+
+const (
+	// defaultAddress is the host and port the server listens on unless given otherwise.
+	defaultAddress = ":8080"
+
+	// readHeaderTimeout is how long a client is given to send the headers of a request.
+	readHeaderTimeout = 10 * time.Second
+
+	// shutdownTimeout is how long the requests in flight are given to complete once the server is asked to stop.
+	shutdownTimeout = 10 * time.Second
+)
+
+// serve starts the HTTP server, which runs until the process is interrupted or terminated.
+func (processStreams streams) serve(arguments []string) int {
+	settings := rest.Settings{}
+	flags := processStreams.flagSet(serveCommand)
+
+	flags.StringVar(&settings.Address, "addr", defaultAddress, "host and port to listen on")
+	flags.Int64Var(&settings.MaxBodyBytes, "max-body-bytes", rest.DefaultMaxBodyBytes, "largest request body read, in bytes")
+	flags.BoolVar(
+		&settings.Chronological,
+		"chronological",
+		false,
+		"apply the operations of each request in the order they were traded (requires dated operations)",
+	)
+	flags.BoolVar(&settings.Strict, "strict", false, "reject request bodies with unknown or absent fields and lossy numbers")
+
+	if exitCode, ok := processStreams.parse(flags, arguments); !ok {
+		return exitCode
+	}
+
+	if err := settings.Validate(); err != nil {
+		return processStreams.fail(ExitUsageError, err)
+	}
+
+	listener, err := net.Listen("tcp", settings.Address)
+
+	if err != nil {
+		return processStreams.fail(ExitIOError, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return processStreams.listen(ctx, listener, NewServerDependencies(settings))
+}
+
+// listen serves requests on the listener until the context is done, then reports it is no longer
+// ready, stops taking new requests and waits for the ones in flight.
+func (processStreams streams) listen(ctx context.Context, listener net.Listener, dependencies ServerDependencies) int {
+	server := &http.Server{Handler: dependencies.Router, ReadHeaderTimeout: readHeaderTimeout}
+	served := make(chan error, 1)
+
+	go func() { served <- server.Serve(listener) }()
+
+	dependencies.Health.SetReady(true)
+	_, _ = fmt.Fprintf(processStreams.stderr, "listening on %s\n", listener.Addr())
+
+	select {
+	case err := <-served:
+		return processStreams.fail(ExitIOError, err)
+	case <-ctx.Done():
+	}
+
+	dependencies.Health.SetReady(false)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return processStreams.fail(ExitIOError, err)
+	}
+
+	return ExitSuccess
+}
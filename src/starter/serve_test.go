@@ -0,0 +1,39 @@
+package starter_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"capital-gains/src/driver/rest"
+	"capital-gains/src/starter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewServerDependenciesServesTheCalculation(t *testing.T) {
+	t.Parallel()
+
+	// Given the dependencies of a server that is ready
+	dependencies := starter.NewServerDependencies(rest.Settings{Address: ":8080", MaxBodyBytes: rest.DefaultMaxBodyBytes})
+	dependencies.Health.SetReady(true)
+
+	server := httptest.NewServer(dependencies.Router)
+	defer server.Close()
+
+	// When I post operations to it
+	response, err := http.Post(server.URL+"/v1/capital-gains", "application/json", strings.NewReader(operations))
+
+	// Then I expect their taxes
+	assert.NoError(t, err)
+
+	defer func() { _ = response.Body.Close() }()
+
+	body, err := io.ReadAll(response.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, `[{"tax":0.00},{"tax":10000.00}]`, string(body))
+}
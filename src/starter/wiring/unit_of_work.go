@@ -0,0 +1,50 @@
+package wiring
+
+import (
+	"capital-gains/src/application/handlers"
+	"capital-gains/src/driven/capitalgains"
+	"capital-gains/src/driven/corrections"
+	"capital-gains/src/driven/operations"
+	"capital-gains/src/driven/positions"
+	"capital-gains/src/driven/taxdiffs"
+	"capital-gains/src/driver/commandbus"
+)
+
+// NewUnitOfWork wires the handlers of a single calculation over in-memory repositories of its own,
+// which hold its state until it ends. It is the unit of work factory of every driver.
+func NewUnitOfWork() commandbus.UnitOfWork {
+	operationsRepository := operations.NewRepository()
+	positionsRepository := positions.NewRepository()
+	correctionsRepository := corrections.NewRepository()
+	capitalGainsRepository := capitalgains.NewRepository()
+	taxDiffsRepository := taxdiffs.NewRepository()
+
+	registerBuyHandler := handlers.NewRegisterBuyHandler(operationsRepository)
+	registerSellHandler := handlers.NewRegisterSellHandler(operationsRepository)
+	registerOpeningBalanceHandler := handlers.NewRegisterOpeningBalanceHandler(positionsRepository)
+	amendOperationHandler := handlers.NewAmendOperationHandler(correctionsRepository)
+	cancelOperationHandler := handlers.NewCancelOperationHandler(correctionsRepository)
+	calculateCapitalGainHandler := handlers.NewCalculateCapitalGainHandler(
+		operationsRepository,
+		positionsRepository,
+		capitalGainsRepository,
+	)
+	calculateTaxDiffHandler := handlers.NewCalculateTaxDiffHandler(
+		operationsRepository,
+		positionsRepository,
+		correctionsRepository,
+		taxDiffsRepository,
+	)
+
+	commandBus := commandbus.NewCommandBus(
+		registerBuyHandler,
+		registerSellHandler,
+		registerOpeningBalanceHandler,
+		amendOperationHandler,
+		cancelOperationHandler,
+		calculateCapitalGainHandler,
+		calculateTaxDiffHandler,
+	)
+
+	return commandbus.NewUnitOfWork(commandBus, capitalGainsRepository, taxDiffsRepository)
+}
@@ -0,0 +1,16 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"capital-gains/src/driver/browser"
+	"capital-gains/src/starter/wiring"
+)
+
+// main exposes the calculator to the page that loaded the module, then keeps the module running so
+// the page can call it.
+func main() {
+	browser.Expose(browser.NewCalculator(wiring.NewUnitOfWork))
+
+	select {}
+}
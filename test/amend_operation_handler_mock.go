@@ -0,0 +1,28 @@
+package test
+
+import "capital-gains/src/application/commands"
+
+type AmendOperationHandlerMock struct {
+	calls    int
+	received []commands.AmendOperation
+}
+
+func NewAmendOperationHandlerMock() *AmendOperationHandlerMock {
+	return &AmendOperationHandlerMock{
+		calls:    0,
+		received: []commands.AmendOperation{},
+	}
+}
+
+func (mock *AmendOperationHandlerMock) Handle(command commands.AmendOperation) {
+	mock.calls++
+	mock.received = append(mock.received, command)
+}
+
+func (mock *AmendOperationHandlerMock) Calls() int {
+	return mock.calls
+}
+
+func (mock *AmendOperationHandlerMock) FirstReceived() commands.AmendOperation {
+	return mock.received[0]
+}
@@ -0,0 +1,30 @@
+package test
+
+import "capital-gains/src/application/commands"
+
+type CalculateTaxDiffHandlerMock struct {
+	calls    int
+	received []commands.CalculateTaxDiff
+}
+
+func NewCalculateTaxDiffHandlerMock() *CalculateTaxDiffHandlerMock {
+	return &CalculateTaxDiffHandlerMock{
+		calls:    0,
+		received: []commands.CalculateTaxDiff{},
+	}
+}
+
+func (mock *CalculateTaxDiffHandlerMock) Handle(command commands.CalculateTaxDiff) error {
+	mock.calls++
+	mock.received = append(mock.received, command)
+
+	return nil
+}
+
+func (mock *CalculateTaxDiffHandlerMock) Calls() int {
+	return mock.calls
+}
+
+func (mock *CalculateTaxDiffHandlerMock) FirstReceived() commands.CalculateTaxDiff {
+	return mock.received[0]
+}
@@ -0,0 +1,28 @@
+package test
+
+import "capital-gains/src/application/commands"
+
+type CancelOperationHandlerMock struct {
+	calls    int
+	received []commands.CancelOperation
+}
+
+func NewCancelOperationHandlerMock() *CancelOperationHandlerMock {
+	return &CancelOperationHandlerMock{
+		calls:    0,
+		received: []commands.CancelOperation{},
+	}
+}
+
+func (mock *CancelOperationHandlerMock) Handle(command commands.CancelOperation) {
+	mock.calls++
+	mock.received = append(mock.received, command)
+}
+
+func (mock *CancelOperationHandlerMock) Calls() int {
+	return mock.calls
+}
+
+func (mock *CancelOperationHandlerMock) FirstReceived() commands.CancelOperation {
+	return mock.received[0]
+}
@@ -1,12 +1,19 @@
 package test
 
-import "capital-gains/src/driver/console"
+import (
+	"io"
+	"strings"
+
+	"capital-gains/src/driver/console"
+)
 
 var _ console.Console = (*ConsoleMock)(nil)
 
 type ConsoleMock struct {
 	inputs  []string
 	outputs []string
+	pending strings.Builder
+	read    int
 }
 
 func NewConsoleMock(inputs []string) *ConsoleMock {
@@ -17,11 +24,33 @@ func NewConsoleMock(inputs []string) *ConsoleMock {
 }
 
 func (console *ConsoleMock) ReadLines() []string {
-	return console.inputs
+	lines := console.inputs[console.read:]
+	console.read = len(console.inputs)
+
+	return lines
+}
+
+func (console *ConsoleMock) ReadLine() (string, bool) {
+	if console.read == len(console.inputs) {
+		return "", false
+	}
+
+	console.read++
+
+	return console.inputs[console.read-1], true
+}
+
+func (console *ConsoleMock) Input() io.Reader {
+	return strings.NewReader(strings.Join(console.ReadLines(), "\n"))
+}
+
+func (console *ConsoleMock) Write(text string) {
+	console.pending.WriteString(text)
 }
 
 func (console *ConsoleMock) WriteLine(line string) {
-	console.outputs = append(console.outputs, line)
+	console.outputs = append(console.outputs, console.pending.String()+line)
+	console.pending.Reset()
 }
 
 func (console *ConsoleMock) WrittenLines() []string {
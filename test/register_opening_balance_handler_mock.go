@@ -0,0 +1,28 @@
+package test
+
+import "capital-gains/src/application/commands"
+
+type RegisterOpeningBalanceHandlerMock struct {
+	calls    int
+	received []commands.RegisterOpeningBalance
+}
+
+func NewRegisterOpeningBalanceHandlerMock() *RegisterOpeningBalanceHandlerMock {
+	return &RegisterOpeningBalanceHandlerMock{
+		calls:    0,
+		received: []commands.RegisterOpeningBalance{},
+	}
+}
+
+func (mock *RegisterOpeningBalanceHandlerMock) Handle(command commands.RegisterOpeningBalance) {
+	mock.calls++
+	mock.received = append(mock.received, command)
+}
+
+func (mock *RegisterOpeningBalanceHandlerMock) Calls() int {
+	return mock.calls
+}
+
+func (mock *RegisterOpeningBalanceHandlerMock) FirstReceived() commands.RegisterOpeningBalance {
+	return mock.received[0]
+}
@@ -0,0 +1,12 @@
+package test
+
+import (
+	"capital-gains/src/driver/commandbus"
+	"capital-gains/src/starter/wiring"
+)
+
+// NewUnitOfWork begins a unit of work wired as the starter wires the one of each calculation, so
+// tests of the drivers calculate over the same handlers and repositories.
+func NewUnitOfWork() commandbus.UnitOfWork {
+	return wiring.NewUnitOfWork()
+}